@@ -14,15 +14,19 @@ package excelize
 import (
 	"bytes"
 	"container/list"
+	"context"
 	"errors"
 	"fmt"
 	"math"
 	"math/rand"
 	"reflect"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/xuri/efp"
@@ -90,9 +94,195 @@ type formulaArg struct {
 	Type   ArgType
 }
 
+// Value returns a string data type of the formula argument, the matrix will
+// be represented with the array constant notation.
+func (fa formulaArg) Value() string {
+	if fa.Type != ArgMatrix {
+		return fa.String
+	}
+	rows := make([]string, 0, len(fa.Matrix))
+	for _, row := range fa.Matrix {
+		cells := make([]string, 0, len(row))
+		for _, cell := range row {
+			val := cell.String
+			if _, err := strconv.ParseFloat(val, 64); err != nil && val != "" &&
+				val != "TRUE" && val != "FALSE" && !strings.HasPrefix(val, "#") {
+				val = "\"" + strings.Replace(val, "\"", "\"\"", -1) + "\""
+			}
+			cells = append(cells, val)
+		}
+		rows = append(rows, strings.Join(cells, ","))
+	}
+	return "{" + strings.Join(rows, ";") + "}"
+}
+
 // formulaFuncs is the type of the formula functions.
 type formulaFuncs struct{}
 
+// FormulaEvalStep directly maps a step of the formula evaluation trace. The
+// Type of the step is one of "name", "reference", "operator", "function" and
+// "subexpression". Expr is the evaluated expression, Args are the values of
+// the operands or function arguments, and Result is the value of the step.
+// Cell ranges are represented with the array constant notation, such as
+// {1,2;3,4}.
+type FormulaEvalStep struct {
+	Type   string
+	Expr   string
+	Args   []string
+	Result string
+}
+
+// CalcOptions directly maps the limits of the formula calculation. MaxCells
+// specifies the maximum number of cells which can be visited by the
+// calculation. MaxDepth specifies the maximum nesting depth of the functions
+// and subexpressions in a formula. MaxChainLength specifies the maximum
+// length of the dependency chain of the formula cells in the workbook-wide
+// calculation. Concurrency specifies the maximum number of goroutines which
+// evaluate the independent formula cells in the workbook-wide calculation,
+// default is the number of the logical CPUs. Zero value of the limits means
+// unlimited.
+type CalcOptions struct {
+	MaxCells       int
+	MaxDepth       int
+	MaxChainLength int
+	Concurrency    int
+}
+
+// calcContext defines the formula execution context.
+type calcContext struct {
+	context context.Context
+	opts    CalcOptions
+	cells   *int64
+	depth   int
+	abort   error
+	trace   bool
+	steps   []FormulaEvalStep
+}
+
+// newCalcContext provides a function to create the formula execution
+// context by given context and calculation options.
+func newCalcContext(ctx context.Context, opts ...CalcOptions) *calcContext {
+	c := &calcContext{context: ctx, cells: new(int64)}
+	for _, o := range opts {
+		c.opts = o
+	}
+	return c
+}
+
+// visitCell check the cancellation of the calculation and count the visited
+// cells by given number of cells. The error which stops the calculation will
+// be recorded in the execution context.
+func (ctx *calcContext) visitCell(n int) error {
+	if ctx.abort = ctx.context.Err(); ctx.abort != nil {
+		return ctx.abort
+	}
+	if visited := atomic.AddInt64(ctx.cells, int64(n)); ctx.opts.MaxCells > 0 && visited > int64(ctx.opts.MaxCells) {
+		ctx.abort = newCalcMaxCellsError(ctx.opts.MaxCells)
+	}
+	return ctx.abort
+}
+
+// enterToken check the cancellation of the calculation and track the nesting
+// depth of the functions and subexpressions by given token. The error which
+// stops the calculation will be recorded in the execution context.
+func (ctx *calcContext) enterToken(token efp.Token) error {
+	if ctx.abort = ctx.context.Err(); ctx.abort != nil {
+		return ctx.abort
+	}
+	if token.TType != efp.TokenTypeFunction && token.TType != efp.TokenTypeSubexpression {
+		return nil
+	}
+	switch token.TSubType {
+	case efp.TokenSubTypeStart:
+		if ctx.depth++; ctx.opts.MaxDepth > 0 && ctx.depth > ctx.opts.MaxDepth {
+			ctx.abort = newCalcMaxDepthError(ctx.opts.MaxDepth)
+		}
+	case efp.TokenSubTypeStop:
+		ctx.depth--
+	}
+	return ctx.abort
+}
+
+// addStep record a formula evaluation step when the trace is enabled.
+func (ctx *calcContext) addStep(step FormulaEvalStep) {
+	if ctx.trace {
+		ctx.steps = append(ctx.steps, step)
+	}
+}
+
+// traceFunction record the arguments and result of the function which stops
+// at the given token index.
+func (ctx *calcContext) traceFunction(tokens []efp.Token, stop int, argsList *list.List, result string) {
+	if !ctx.trace {
+		return
+	}
+	args := []string{}
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		args = append(args, arg.Value.(formulaArg).Value())
+	}
+	ctx.addStep(FormulaEvalStep{
+		Type:   "function",
+		Expr:   renderFormulaTokens(tokens[getStartTokenIndex(tokens, stop) : stop+1]),
+		Args:   args,
+		Result: result,
+	})
+}
+
+// traceSubexpression record the result of the subexpression if the given
+// token index is the end of a subexpression.
+func (ctx *calcContext) traceSubexpression(tokens []efp.Token, stop int, opdStack *Stack) {
+	token := tokens[stop]
+	if !ctx.trace || opdStack.Empty() ||
+		token.TType != efp.TokenTypeSubexpression || token.TSubType != efp.TokenSubTypeStop {
+		return
+	}
+	ctx.addStep(FormulaEvalStep{
+		Type:   "subexpression",
+		Expr:   renderFormulaTokens(tokens[getStartTokenIndex(tokens, stop) : stop+1]),
+		Result: opdStack.Peek().(efp.Token).TValue,
+	})
+}
+
+// getStartTokenIndex provides a function to get the index of the function
+// or subexpression start token by given end token index.
+func getStartTokenIndex(tokens []efp.Token, stop int) int {
+	var depth int
+	for i := stop; i >= 0; i-- {
+		switch tokens[i].TSubType {
+		case efp.TokenSubTypeStop:
+			depth++
+		case efp.TokenSubTypeStart:
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// renderFormulaTokens provides a function to get formula text by given
+// tokens.
+func renderFormulaTokens(tokens []efp.Token) string {
+	var output strings.Builder
+	for _, t := range tokens {
+		switch {
+		case t.TType == efp.TokenTypeFunction && t.TSubType == efp.TokenSubTypeStart:
+			output.WriteString(t.TValue + "(")
+		case t.TType == efp.TokenTypeSubexpression && t.TSubType == efp.TokenSubTypeStart:
+			output.WriteString("(")
+		case t.TSubType == efp.TokenSubTypeStop:
+			output.WriteString(")")
+		case t.TType == efp.TokenTypeOperand && t.TSubType == efp.TokenSubTypeText:
+			output.WriteString("\"" + strings.Replace(t.TValue, "\"", "\"\"", -1) + "\"")
+		case t.TType == efp.TokenTypeOperatorInfix && t.TSubType == efp.TokenSubTypeIntersection:
+			output.WriteString(" ")
+		default:
+			output.WriteString(t.TValue)
+		}
+	}
+	return output.String()
+}
+
 // CalcCellValue provides a function to get calculated cell value. This
 // feature is currently in working processing. Array formula, table formula
 // and some other formulas are not supported currently.
@@ -110,11 +300,65 @@ type formulaFuncs struct{}
 //    TAN, TANH, TRUNC
 //
 func (f *File) CalcCellValue(sheet, cell string) (result string, err error) {
+	return f.calcCellValue(newCalcContext(context.Background()), sheet, cell)
+}
+
+// CalcCellValueContext provides a function to get calculated cell value with
+// the given context and calculation limits. The calculation will be stopped
+// with the context error if the context is canceled or its deadline exceeded,
+// and be stopped with an error if the number of the visited cells or the
+// nesting depth of the formula exceeds the limits. It's safe to call this
+// function with the cell setter functions concurrently. For example,
+// calculate the cell Sheet1!A1 in one second, visiting no more than 100000
+// cells:
+//
+//    ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//    defer cancel()
+//    result, err := f.CalcCellValueContext(ctx, "Sheet1", "A1", excelize.CalcOptions{MaxCells: 100000})
+//
+func (f *File) CalcCellValueContext(ctx context.Context, sheet, cell string, opts ...CalcOptions) (result string, err error) {
+	return f.calcCellValue(newCalcContext(ctx, opts...), sheet, cell)
+}
+
+// EvaluateFormula provides a function to get the ordered evaluation trace of
+// the formula in the given cell, like the Evaluate Formula dialog of Excel.
+// Each step of the trace records the resolved references and defined names,
+// the operands and the result of the arithmetic operations and
+// subexpressions, and the arguments and the result of the functions. The
+// last step holds the calculated cell value. If the evaluation failed, the
+// steps evaluated before the failure will be returned with the error. For
+// example, get the evaluation trace of the formula =SUM(A1:A2)*2 on cell C1
+// of the worksheet named Sheet1:
+//
+//    steps, err := f.EvaluateFormula("Sheet1", "C1")
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    for _, step := range steps {
+//        fmt.Println(step.Type, step.Expr, step.Args, step.Result)
+//    }
+//
+// The output will be:
+//
+//    reference A1:A2 [] {1;2}
+//    function SUM(A1:A2) [{1;2}] 3
+//    operator 3*2 [3 2] 6
+//
+func (f *File) EvaluateFormula(sheet, cell string) ([]FormulaEvalStep, error) {
+	ctx := newCalcContext(context.Background())
+	ctx.trace = true
+	_, err := f.calcCellValue(ctx, sheet, cell)
+	return ctx.steps, err
+}
+
+// calcCellValue provides a function to get calculated cell value by given
+// formula execution context, worksheet name and cell coordinates.
+func (f *File) calcCellValue(ctx *calcContext, sheet, cell string) (result string, err error) {
 	var (
 		formula string
 		token   efp.Token
 	)
-	if formula, err = f.GetCellFormula(sheet, cell); err != nil {
+	if formula, err = f.getCellFormula(sheet, cell); err != nil {
 		return
 	}
 	ps := efp.ExcelParser()
@@ -122,13 +366,362 @@ func (f *File) CalcCellValue(sheet, cell string) (result string, err error) {
 	if tokens == nil {
 		return
 	}
-	if token, err = f.evalInfixExp(sheet, tokens); err != nil {
+	if token, err = f.evalInfixExp(ctx, sheet, tokens); err != nil {
 		return
 	}
 	result = token.TValue
 	return
 }
 
+// calcCell directly maps the formula cell of the workbook-wide calculation.
+type calcCell struct {
+	sheet, cell string
+	col, row    int
+	refs        []cellRange
+	dependents  []int
+	precedents  int
+	unresolved  bool
+}
+
+// CalcWorkbook provides a function to calculate all formula cells of the
+// workbook with the given context and calculation limits, and update the
+// cached values of the cells with the calculated results. The formula cells
+// will be evaluated in order of the dependency graph of the cells, and the
+// independent cells will be evaluated in parallel by the goroutines. The
+// cells with the references which can't be resolved before the calculation,
+// such as the references returned by the INDIRECT and OFFSET functions, will
+// be evaluated one by one after the other cells. The limit of the visited
+// cells is shared by all formula cells. The calculation
+// will be stopped if the context is canceled, the limits exceeded or the
+// circular reference found. For the other errors of the cells, the cached
+// values of the cells will be kept and the first error will be returned
+// after all cells have been evaluated. For example, calculate the workbook
+// with 4 goroutines:
+//
+//    err := f.CalcWorkbook(context.Background(), excelize.CalcOptions{Concurrency: 4})
+//
+func (f *File) CalcWorkbook(ctx context.Context, opts ...CalcOptions) error {
+	var options CalcOptions
+	for _, o := range opts {
+		options = o
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = runtime.NumCPU()
+	}
+	cells, err := f.getCalcCells()
+	if err != nil {
+		return err
+	}
+	levels, chain, err := getCalcLevels(cells)
+	if err != nil {
+		return err
+	}
+	if options.MaxChainLength > 0 && chain > options.MaxChainLength {
+		return newCalcMaxChainLengthError(options.MaxChainLength)
+	}
+	// load the external links before the parallel evaluation
+	f.getExternalLinks()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu            sync.Mutex
+		abort, first  error
+		visited       = new(int64)
+		setFirstError = func(c calcCell, err error, fatal bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			if fatal && abort == nil {
+				abort = err
+				cancel()
+			}
+			if first == nil {
+				first = fmt.Errorf("%s!%s: %v", c.sheet, c.cell, err)
+			}
+		}
+	)
+	for _, level := range levels {
+		queue, wg := make(chan int), new(sync.WaitGroup)
+		for w := 0; w < options.Concurrency && w < len(level); w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for idx := range queue {
+					c := cells[idx]
+					calcCtx := &calcContext{context: ctx, opts: options, cells: visited}
+					result, err := f.calcCellValue(calcCtx, c.sheet, c.cell)
+					if err != nil {
+						if calcCtx.abort != nil {
+							setFirstError(c, calcCtx.abort, true)
+							continue
+						}
+						if !isFormulaError(err.Error()) {
+							setFirstError(c, err, false)
+							continue
+						}
+						result = err.Error()
+					}
+					if err = f.setCellCachedValue(c.sheet, c.cell, result); err != nil {
+						setFirstError(c, err, true)
+					}
+				}
+			}()
+		}
+		for _, idx := range level {
+			queue <- idx
+		}
+		close(queue)
+		wg.Wait()
+		if abort != nil {
+			return abort
+		}
+	}
+	return first
+}
+
+// getCalcCells provides a function to get the formula cells and the
+// dependencies between them in the workbook.
+func (f *File) getCalcCells() ([]calcCell, error) {
+	var cells []calcCell
+	sheetMap, sheetCells := f.getSheetMap(), map[string][]int{}
+	for _, sheet := range f.GetSheetList() {
+		if !strings.HasPrefix(sheetMap[sheet], "xl/worksheets/") {
+			continue
+		}
+		ws, err := f.workSheetReadOnly(sheet)
+		if err != nil {
+			return cells, err
+		}
+		ws.Lock()
+		for _, row := range ws.SheetData.Row {
+			for _, c := range row.C {
+				if c.F == nil {
+					continue
+				}
+				col, row, err := CellNameToCoordinates(c.R)
+				if err != nil {
+					ws.Unlock()
+					return cells, err
+				}
+				sheetCells[sheet] = append(sheetCells[sheet], len(cells))
+				cells = append(cells, calcCell{sheet: sheet, cell: c.R, col: col, row: row})
+			}
+		}
+		ws.Unlock()
+	}
+	for i := range cells {
+		formula, err := f.getCellFormula(cells[i].sheet, cells[i].cell)
+		if err != nil {
+			return cells, err
+		}
+		cells[i].refs, cells[i].unresolved = f.getFormulaCellRanges(cells[i].sheet, formula, 0)
+		for _, ref := range cells[i].refs {
+			for _, j := range sheetCells[ref.From.Sheet] {
+				if cells[j].col >= ref.From.Col && cells[j].col <= ref.To.Col &&
+					cells[j].row >= ref.From.Row && cells[j].row <= ref.To.Row {
+					cells[j].dependents = append(cells[j].dependents, i)
+					cells[i].precedents++
+				}
+			}
+		}
+	}
+	return cells, nil
+}
+
+// formulaDynamicRefFunctions defined the functions which return the
+// references can't be resolved before the calculation.
+var formulaDynamicRefFunctions = map[string]bool{"INDIRECT": true, "OFFSET": true}
+
+// getFormulaCellRanges provides a function to get the referenced cell ranges
+// of the worksheets in the workbook by given worksheet name, formula and the
+// nesting depth of the defined names. The defined names will be resolved to
+// the referenced cell ranges, and the references of the external workbooks
+// will be ignored. Returns true if there are references which can't be
+// resolved.
+func (f *File) getFormulaCellRanges(sheet, formula string, depth int) ([]cellRange, bool) {
+	var (
+		ranges     []cellRange
+		unresolved bool
+	)
+	ps := efp.ExcelParser()
+	for _, token := range ps.Parse(formula) {
+		if token.TType == efp.TokenTypeFunction && token.TSubType == efp.TokenSubTypeStart &&
+			formulaDynamicRefFunctions[strings.ToUpper(token.TValue)] {
+			unresolved = true
+		}
+		if token.TSubType != efp.TokenSubTypeRange {
+			continue
+		}
+		ref, refSheet := strings.Replace(token.TValue, "$", "", -1), sheet
+		if i := strings.LastIndex(ref, "!"); i != -1 {
+			ref, refSheet = ref[i+1:], strings.Replace(strings.Trim(ref[:i], "'"), "''", "'", -1)
+		}
+		if strings.HasPrefix(refSheet, "[") {
+			continue
+		}
+		coordinates, ok := getFormulaRefCoordinates(ref)
+		if !ok {
+			refTo := f.getDefinedNameRefTo(token.TValue, sheet)
+			if refTo == "" || depth >= 16 {
+				unresolved = true
+				continue
+			}
+			refs, nested := f.getFormulaCellRanges(sheet, refTo, depth+1)
+			ranges, unresolved = append(ranges, refs...), unresolved || nested
+			continue
+		}
+		ranges = append(ranges, cellRange{
+			From: cellRef{Sheet: refSheet, Col: coordinates[0], Row: coordinates[1]},
+			To:   cellRef{Sheet: refSheet, Col: coordinates[2], Row: coordinates[3]},
+		})
+	}
+	return ranges, unresolved
+}
+
+// getFormulaRefCoordinates provides a function to get the sorted
+// coordinates of the cell, range, whole column or whole row reference in
+// the formula, returns false if the reference is not a cell reference.
+func getFormulaRefCoordinates(ref string) ([]int, bool) {
+	cells := strings.Split(ref, ":")
+	if len(cells) > 2 {
+		return nil, false
+	}
+	coordinates := []int{}
+	for _, cell := range cells {
+		col, row, err := CellNameToCoordinates(cell)
+		if err == nil {
+			coordinates = append(coordinates, col, row)
+			continue
+		}
+		if len(cells) != 2 {
+			return nil, false
+		}
+		if col, err = ColumnNameToNumber(cell); err == nil {
+			coordinates = append(coordinates, col, 0)
+			continue
+		}
+		if row, err = strconv.Atoi(cell); err == nil && row > 0 && row <= TotalRows {
+			coordinates = append(coordinates, 0, row)
+			continue
+		}
+		return nil, false
+	}
+	if len(coordinates) == 2 {
+		return append(coordinates, coordinates...), true
+	}
+	switch {
+	case coordinates[1] == 0 && coordinates[3] == 0 && coordinates[0] != 0 && coordinates[2] != 0:
+		// the whole column reference
+		coordinates[1], coordinates[3] = 1, TotalRows
+	case coordinates[0] == 0 && coordinates[2] == 0 && coordinates[1] != 0 && coordinates[3] != 0:
+		// the whole row reference
+		coordinates[0], coordinates[2] = 1, TotalColumns
+	case coordinates[0] == 0 || coordinates[1] == 0 || coordinates[2] == 0 || coordinates[3] == 0:
+		return nil, false
+	}
+	_ = sortCoordinates(coordinates)
+	return coordinates, true
+}
+
+// getCalcLevels provides a function to group the formula cells by the length
+// of the dependency chain, the cells in each level are independent of each
+// other and only depend on the cells in the previous levels. The cells with
+// unresolved references will be placed in separate levels after all the
+// other cells which don't depend on them. Returns the levels and the maximum
+// length of the dependency chain.
+func getCalcLevels(cells []calcCell) ([][]int, int, error) {
+	var (
+		levels           [][]int
+		level, deferred  []int
+		evaluated, chain int
+	)
+	precedents, lengths := make([]int, len(cells)), make([]int, len(cells))
+	ready := func(i int) {
+		if cells[i].unresolved {
+			deferred = append(deferred, i)
+			return
+		}
+		level = append(level, i)
+	}
+	for i := range cells {
+		if precedents[i] = cells[i].precedents; precedents[i] == 0 {
+			ready(i)
+		}
+	}
+	for len(level) > 0 || len(deferred) > 0 {
+		if len(level) == 0 {
+			level, deferred = []int{deferred[0]}, deferred[1:]
+		}
+		current := level
+		levels, evaluated, level = append(levels, current), evaluated+len(current), nil
+		for _, i := range current {
+			if lengths[i]++; lengths[i] > chain {
+				chain = lengths[i]
+			}
+			for _, j := range cells[i].dependents {
+				if lengths[i] > lengths[j] {
+					lengths[j] = lengths[i]
+				}
+				if precedents[j]--; precedents[j] == 0 {
+					ready(j)
+				}
+			}
+		}
+	}
+	if evaluated < len(cells) {
+		for i := range cells {
+			if precedents[i] > 0 {
+				return levels, chain, fmt.Errorf("circular reference in cell %s!%s", cells[i].sheet, cells[i].cell)
+			}
+		}
+	}
+	return levels, chain, nil
+}
+
+// isFormulaError provides a function to check if the given value is a
+// formula error.
+func isFormulaError(value string) bool {
+	for _, e := range []string{
+		formulaErrorDIV, formulaErrorNAME, formulaErrorNA, formulaErrorNUM,
+		formulaErrorVALUE, formulaErrorREF, formulaErrorNULL, formulaErrorSPILL,
+		formulaErrorCALC, formulaErrorGETTINGDATA,
+	} {
+		if value == e {
+			return true
+		}
+	}
+	return false
+}
+
+// setCellCachedValue provides a function to set the cached value of the
+// formula cell by given worksheet name, cell coordinates and calculated
+// result.
+func (f *File) setCellCachedValue(sheet, axis, value string) error {
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	cellData, _, _, err := f.prepareCell(ws, sheet, axis)
+	if err != nil {
+		return err
+	}
+	cellData.removeAnyAttrs("vm")
+	switch {
+	case value == "TRUE" || value == "FALSE":
+		cellData.T, cellData.V = setCellBool(value == "TRUE")
+	case isFormulaError(value):
+		cellData.T, cellData.V = "e", value
+	default:
+		if _, err = strconv.ParseFloat(value, 64); err == nil || value == "" {
+			cellData.T, cellData.V = setCellDefault(value)
+			return nil
+		}
+		cellData.T, cellData.V = "str", value
+	}
+	return nil
+}
+
 // getPriority calculate arithmetic operator priority.
 func getPriority(token efp.Token) (pri int) {
 	var priority = map[string]int{
@@ -163,18 +756,22 @@ func getPriority(token efp.Token) (pri int) {
 //
 // TODO: handle subtypes: Nothing, Text, Logical, Error, Concatenation, Intersection, Union
 //
-func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error) {
+func (f *File) evalInfixExp(ctx *calcContext, sheet string, tokens []efp.Token) (efp.Token, error) {
 	var err error
 	opdStack, optStack, opfStack, opfdStack, opftStack := NewStack(), NewStack(), NewStack(), NewStack(), NewStack()
 	argsList := list.New()
 	for i := 0; i < len(tokens); i++ {
 		token := tokens[i]
+		if err = ctx.enterToken(token); err != nil {
+			return efp.Token{}, err
+		}
 
 		// out of function stack
 		if opfStack.Len() == 0 {
-			if err = f.parseToken(sheet, token, opdStack, optStack); err != nil {
+			if err = f.parseToken(ctx, sheet, token, opdStack, optStack); err != nil {
 				return efp.Token{}, err
 			}
+			ctx.traceSubexpression(tokens, i, opdStack)
 		}
 
 		// function start
@@ -194,7 +791,7 @@ func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error)
 			if token.TSubType == efp.TokenSubTypeRange {
 				if !opftStack.Empty() {
 					// parse reference: must reference at here
-					result, err := f.parseReference(sheet, token.TValue)
+					result, err := f.parseReference(ctx, sheet, token.TValue)
 					if err != nil {
 						return efp.Token{TValue: formulaErrorNAME}, err
 					}
@@ -210,7 +807,7 @@ func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error)
 				}
 				if nextToken.TType == efp.TokenTypeArgument || nextToken.TType == efp.TokenTypeFunction {
 					// parse reference: reference or range at here
-					result, err := f.parseReference(sheet, token.TValue)
+					result, err := f.parseReference(ctx, sheet, token.TValue)
 					if err != nil {
 						return efp.Token{TValue: formulaErrorNAME}, err
 					}
@@ -223,16 +820,17 @@ func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error)
 			}
 
 			// check current token is opft
-			if err = f.parseToken(sheet, token, opfdStack, opftStack); err != nil {
+			if err = f.parseToken(ctx, sheet, token, opfdStack, opftStack); err != nil {
 				return efp.Token{}, err
 			}
+			ctx.traceSubexpression(tokens, i, opfdStack)
 
 			// current token is arg
 			if token.TType == efp.TokenTypeArgument {
 				for !opftStack.Empty() {
 					// calculate trigger
 					topOpt := opftStack.Peek().(efp.Token)
-					if err := calculate(opfdStack, topOpt); err != nil {
+					if err := calculate(ctx, opfdStack, topOpt); err != nil {
 						return efp.Token{}, err
 					}
 					opftStack.Pop()
@@ -263,7 +861,7 @@ func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error)
 				for !opftStack.Empty() {
 					// calculate trigger
 					topOpt := opftStack.Peek().(efp.Token)
-					if err := calculate(opfdStack, topOpt); err != nil {
+					if err := calculate(ctx, opfdStack, topOpt); err != nil {
 						return efp.Token{}, err
 					}
 					opftStack.Pop()
@@ -283,6 +881,7 @@ func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error)
 				if err != nil {
 					return efp.Token{}, err
 				}
+				ctx.traceFunction(tokens, i, argsList, result)
 				argsList.Init()
 				opfStack.Pop()
 				if opfStack.Len() > 0 { // still in function stack
@@ -295,7 +894,7 @@ func (f *File) evalInfixExp(sheet string, tokens []efp.Token) (efp.Token, error)
 	}
 	for optStack.Len() != 0 {
 		topOpt := optStack.Peek().(efp.Token)
-		if err = calculate(opdStack, topOpt); err != nil {
+		if err = calculate(ctx, opdStack, topOpt); err != nil {
 			return efp.Token{}, err
 		}
 		optStack.Pop()
@@ -390,7 +989,30 @@ func calcDivide(opdStack *Stack) error {
 }
 
 // calculate evaluate basic arithmetic operations.
-func calculate(opdStack *Stack, opt efp.Token) error {
+func calculate(ctx *calcContext, opdStack *Stack, opt efp.Token) (err error) {
+	if ctx.trace && isOperatorPrefixToken(opt) {
+		arity, operands := 2, []string{}
+		if opt.TType == efp.TokenTypeOperatorPrefix {
+			arity = 1
+		}
+		for e := opdStack.list.Back(); e != nil && len(operands) < arity; e = e.Prev() {
+			operands = append([]string{e.Value.(efp.Token).TValue}, operands...)
+		}
+		defer func() {
+			if err == nil && len(operands) == arity {
+				expr := opt.TValue + operands[0]
+				if arity == 2 {
+					expr = operands[0] + opt.TValue + operands[1]
+				}
+				ctx.addStep(FormulaEvalStep{
+					Type:   "operator",
+					Expr:   expr,
+					Args:   operands,
+					Result: opdStack.Peek().(efp.Token).TValue,
+				})
+			}
+		}()
+	}
 	if opt.TValue == "-" && opt.TType == efp.TokenTypeOperatorPrefix {
 		if opdStack.Len() < 1 {
 			return errors.New("formula not valid")
@@ -428,7 +1050,7 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 }
 
 // parseOperatorPrefixToken parse operator prefix token.
-func (f *File) parseOperatorPrefixToken(optStack, opdStack *Stack, token efp.Token) (err error) {
+func (f *File) parseOperatorPrefixToken(ctx *calcContext, optStack, opdStack *Stack, token efp.Token) (err error) {
 	if optStack.Len() == 0 {
 		optStack.Push(token)
 	} else {
@@ -440,7 +1062,7 @@ func (f *File) parseOperatorPrefixToken(optStack, opdStack *Stack, token efp.Tok
 		} else {
 			for tokenPriority <= topOptPriority {
 				optStack.Pop()
-				if err = calculate(opdStack, topOpt); err != nil {
+				if err = calculate(ctx, opdStack, topOpt); err != nil {
 					return
 				}
 				if optStack.Len() > 0 {
@@ -481,14 +1103,15 @@ func (f *File) getDefinedNameRefTo(definedNameName string, currentSheet string)
 
 // parseToken parse basic arithmetic operator priority and evaluate based on
 // operators and operands.
-func (f *File) parseToken(sheet string, token efp.Token, opdStack, optStack *Stack) error {
+func (f *File) parseToken(ctx *calcContext, sheet string, token efp.Token, opdStack, optStack *Stack) error {
 	// parse reference: must reference at here
 	if token.TSubType == efp.TokenSubTypeRange {
 		refTo := f.getDefinedNameRefTo(token.TValue, sheet)
 		if refTo != "" {
+			ctx.addStep(FormulaEvalStep{Type: "name", Expr: token.TValue, Result: refTo})
 			token.TValue = refTo
 		}
-		result, err := f.parseReference(sheet, token.TValue)
+		result, err := f.parseReference(ctx, sheet, token.TValue)
 		if err != nil {
 			return errors.New(formulaErrorNAME)
 		}
@@ -500,7 +1123,7 @@ func (f *File) parseToken(sheet string, token efp.Token, opdStack, optStack *Sta
 		token.TSubType = efp.TokenSubTypeNumber
 	}
 	if isOperatorPrefixToken(token) {
-		if err := f.parseOperatorPrefixToken(optStack, opdStack, token); err != nil {
+		if err := f.parseOperatorPrefixToken(ctx, optStack, opdStack, token); err != nil {
 			return err
 		}
 	}
@@ -510,7 +1133,7 @@ func (f *File) parseToken(sheet string, token efp.Token, opdStack, optStack *Sta
 	if token.TType == efp.TokenTypeSubexpression && token.TSubType == efp.TokenSubTypeStop { // )
 		for optStack.Peek().(efp.Token).TSubType != efp.TokenSubTypeStart && optStack.Peek().(efp.Token).TType != efp.TokenTypeSubexpression { // != (
 			topOpt := optStack.Peek().(efp.Token)
-			if err := calculate(opdStack, topOpt); err != nil {
+			if err := calculate(ctx, opdStack, topOpt); err != nil {
 				return err
 			}
 			optStack.Pop()
@@ -526,10 +1149,22 @@ func (f *File) parseToken(sheet string, token efp.Token, opdStack, optStack *Sta
 
 // parseReference parse reference and extract values by given reference
 // characters and default sheet name.
-func (f *File) parseReference(sheet, reference string) (arg formulaArg, err error) {
+func (f *File) parseReference(ctx *calcContext, sheet, reference string) (arg formulaArg, err error) {
+	expr := reference
 	reference = strings.Replace(reference, "$", "", -1)
 	refs, cellRanges, cellRefs := list.New(), list.New(), list.New()
+	cr, whole, err := f.parseWholeReference(sheet, reference)
+	if err != nil {
+		return
+	}
+	if whole {
+		cellRanges.PushBack(cr)
+		reference = ""
+	}
 	for _, ref := range strings.Split(reference, ":") {
+		if ref == "" {
+			continue
+		}
 		tokens := strings.Split(ref, "!")
 		cr := cellRef{}
 		if len(tokens) == 2 { // have a worksheet name
@@ -565,10 +1200,65 @@ func (f *File) parseReference(sheet, reference string) (arg formulaArg, err erro
 		cellRefs.PushBack(e.Value.(cellRef))
 		refs.Remove(e)
 	}
-	arg, err = f.rangeResolver(cellRefs, cellRanges)
+	if arg, err = f.rangeResolver(ctx, cellRefs, cellRanges); err != nil {
+		return
+	}
+	ctx.addStep(FormulaEvalStep{Type: "reference", Expr: expr, Result: arg.Value()})
 	return
 }
 
+// parseWholeReference provides a function to parse the whole column or
+// whole row reference, such as A:B or Sheet1!1:2, to the cell range by given
+// worksheet name and reference. The cell range will be limited to the used
+// range of the worksheet. The whole returns false if the reference is not a
+// whole column or row reference.
+func (f *File) parseWholeReference(sheet, reference string) (cr cellRange, whole bool, err error) {
+	if i := strings.LastIndex(reference, "!"); i != -1 {
+		sheet, reference = reference[:i], reference[i+1:]
+	}
+	coordinates, ok := getFormulaRefCoordinates(reference)
+	if !ok || strings.HasPrefix(sheet, "[") {
+		return
+	}
+	if _, _, e := CellNameToCoordinates(strings.Split(reference, ":")[0]); e == nil {
+		return
+	}
+	ws, err := f.workSheetReadOnly(sheet)
+	if err != nil {
+		return
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	var maxCol, maxRow int
+	for _, row := range ws.SheetData.Row {
+		if row.R > maxRow {
+			maxRow = row.R
+		}
+		for _, c := range row.C {
+			if col, _, err := CellNameToCoordinates(c.R); err == nil && col > maxCol {
+				maxCol = col
+			}
+		}
+	}
+	if coordinates[3] > maxRow {
+		coordinates[3] = maxRow
+	}
+	if coordinates[2] > maxCol {
+		coordinates[2] = maxCol
+	}
+	if coordinates[3] < coordinates[1] {
+		coordinates[3] = coordinates[1]
+	}
+	if coordinates[2] < coordinates[0] {
+		coordinates[2] = coordinates[0]
+	}
+	cr = cellRange{
+		From: cellRef{Sheet: sheet, Col: coordinates[0], Row: coordinates[1]},
+		To:   cellRef{Sheet: sheet, Col: coordinates[2], Row: coordinates[3]},
+	}
+	return cr, true, err
+}
+
 // prepareValueRange prepare value range.
 func prepareValueRange(cr cellRange, valueRange []int) {
 	if cr.From.Row < valueRange[0] || valueRange[0] == 0 {
@@ -604,7 +1294,7 @@ func prepareValueRef(cr cellRef, valueRange []int) {
 // rangeResolver extract value as string from given reference and range list.
 // This function will not ignore the empty cell. For example, A1:A2:A2:B3 will
 // be reference A1:B3.
-func (f *File) rangeResolver(cellRefs, cellRanges *list.List) (arg formulaArg, err error) {
+func (f *File) rangeResolver(ctx *calcContext, cellRefs, cellRanges *list.List) (arg formulaArg, err error) {
 	// value range order: from row, to row, from column, to column
 	valueRange := []int{0, 0, 0, 0}
 	var sheet string
@@ -633,13 +1323,16 @@ func (f *File) rangeResolver(cellRefs, cellRanges *list.List) (arg formulaArg, e
 	if cellRanges.Len() > 0 {
 		arg.Type = ArgMatrix
 		for row := valueRange[0]; row <= valueRange[1]; row++ {
+			if err = ctx.visitCell(valueRange[3] - valueRange[2] + 1); err != nil {
+				return
+			}
 			var matrixRow = []formulaArg{}
 			for col := valueRange[2]; col <= valueRange[3]; col++ {
 				var cell, value string
 				if cell, err = CoordinatesToCellName(col, row); err != nil {
 					return
 				}
-				if value, err = f.GetCellValue(sheet, cell); err != nil {
+				if value, err = f.getRefCellValue(sheet, cell); err != nil {
 					return
 				}
 				matrixRow = append(matrixRow, formulaArg{
@@ -655,10 +1348,13 @@ func (f *File) rangeResolver(cellRefs, cellRanges *list.List) (arg formulaArg, e
 	for temp := cellRefs.Front(); temp != nil; temp = temp.Next() {
 		cr := temp.Value.(cellRef)
 		var cell string
+		if err = ctx.visitCell(1); err != nil {
+			return
+		}
 		if cell, err = CoordinatesToCellName(cr.Col, cr.Row); err != nil {
 			return
 		}
-		if arg.String, err = f.GetCellValue(cr.Sheet, cell); err != nil {
+		if arg.String, err = f.getRefCellValue(cr.Sheet, cell); err != nil {
 			return
 		}
 		arg.Type = ArgString
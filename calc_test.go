@@ -1,7 +1,11 @@
 package excelize
 
 import (
+	"context"
+	"fmt"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -817,3 +821,187 @@ func TestCalcCellValueWithDefinedName(t *testing.T) {
 	// DefinedName with scope WorkSheet takes precedence over DefinedName with scope Workbook, so we should get B1 value
 	assert.Equal(t, "B1 value", result, "=defined_name1")
 }
+
+func TestEvaluateFormula(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{1, 4}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{2, 3}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "rate", RefersTo: "Sheet1!B1", Scope: "Workbook"}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=(SUM(A1:B2)+rate)*-2"))
+	steps, err := f.EvaluateFormula("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, []FormulaEvalStep{
+		{Type: "reference", Expr: "A1:B2", Result: "{1,4;2,3}"},
+		{Type: "function", Expr: "SUM(A1:B2)", Args: []string{"{1,4;2,3}"}, Result: "10"},
+		{Type: "name", Expr: "rate", Result: "Sheet1!B1"},
+		{Type: "reference", Expr: "Sheet1!B1", Result: "4"},
+		{Type: "operator", Expr: "10+4", Args: []string{"10", "4"}, Result: "14"},
+		{Type: "subexpression", Expr: "(SUM(A1:B2)+rate)", Result: "14"},
+		{Type: "operator", Expr: "-2", Args: []string{"2"}, Result: "-2"},
+		{Type: "operator", Expr: "14*-2", Args: []string{"14", "-2"}, Result: "-28"},
+	}, steps)
+	result, err := f.CalcCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "-28", result)
+
+	// Test get evaluation trace of the formula with error.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=ABS(A1)/(A1-1)"))
+	steps, err = f.EvaluateFormula("Sheet1", "C1")
+	assert.EqualError(t, err, formulaErrorDIV)
+	assert.Equal(t, []FormulaEvalStep{
+		{Type: "reference", Expr: "A1", Result: "1"},
+		{Type: "function", Expr: "ABS(A1)", Args: []string{"1"}, Result: "1"},
+		{Type: "reference", Expr: "A1", Result: "1"},
+		{Type: "operator", Expr: "1-1", Args: []string{"1", "1"}, Result: "0"},
+		{Type: "subexpression", Expr: "(A1-1)", Result: "0"},
+	}, steps)
+
+	// Test get evaluation trace on not exists worksheet.
+	_, err = f.EvaluateFormula("SheetN", "C1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+
+	// Test represent the matrix argument with the array constant notation.
+	assert.Equal(t, `{1,"a""b";TRUE,#N/A}`, formulaArg{Type: ArgMatrix, Matrix: [][]formulaArg{
+		{{String: "1", Type: ArgString}, {String: `a"b`, Type: ArgString}},
+		{{String: "TRUE", Type: ArgString}, {String: formulaErrorNA, Type: ArgString}},
+	}}.Value())
+}
+
+func TestCalcCellValueContext(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 10; row++ {
+		assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), row))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=SUM(A1:A10)"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B2", "=SUM(ABS(SUM(A1,(A2+1))))"))
+	result, err := f.CalcCellValueContext(context.Background(), "Sheet1", "B1", CalcOptions{MaxCells: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, "55", result)
+	// Test calculate with limits
+	_, err = f.CalcCellValueContext(context.Background(), "Sheet1", "B1", CalcOptions{MaxCells: 9})
+	assert.EqualError(t, err, "exceeds the maximum number of visited cells 9")
+	result, err = f.CalcCellValueContext(context.Background(), "Sheet1", "B2", CalcOptions{MaxDepth: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, "4", result)
+	_, err = f.CalcCellValueContext(context.Background(), "Sheet1", "B2", CalcOptions{MaxDepth: 3})
+	assert.EqualError(t, err, "exceeds the maximum calculation depth 3")
+	// Test calculate with canceled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = f.CalcCellValueContext(ctx, "Sheet1", "B1")
+	assert.Equal(t, context.Canceled, err)
+	// Test calculate with setting cell values concurrently
+	wg := new(sync.WaitGroup)
+	for i := 1; i <= 10; i++ {
+		wg.Add(2)
+		go func(row int) {
+			defer wg.Done()
+			assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), row*2))
+			assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("C%d", row), row))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := f.CalcCellValueContext(context.Background(), "Sheet1", "B1")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	result, err = f.CalcCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "110", result)
+}
+
+func TestCalcWorkbookReferences(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("My Sheet")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "=x*10"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "=A1+1"))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "x", RefersTo: "Sheet1!$A$2"}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "=SUM(A:A)"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "=SUM(2:2)"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "G1", "=A2+'My Sheet'!A1"))
+	assert.NoError(t, f.SetCellFormula("My Sheet", "A1", "=INDIRECT(\"Sheet1!A2\")*2"))
+	assert.NoError(t, f.SetCellFormula("My Sheet", "A2", "=A1+1"))
+	cells, err := f.getCalcCells()
+	assert.NoError(t, err)
+	levels, chain, err := getCalcLevels(cells)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, chain)
+	var order []string
+	for _, level := range levels {
+		var names []string
+		for _, i := range level {
+			names = append(names, cells[i].sheet+"!"+cells[i].cell)
+		}
+		order = append(order, strings.Join(names, ","))
+	}
+	assert.Equal(t, []string{"Sheet1!A2", "Sheet1!D1,Sheet1!E1,Sheet1!F1", "My Sheet!A1", "Sheet1!G1,My Sheet!A2"}, order)
+
+	// Test calculate the whole column and row references, the INDIRECT
+	// function is not supported by the calculation.
+	assert.NoError(t, f.SetCellFormula("My Sheet", "A1", "=Sheet1!A2*2"))
+	assert.NoError(t, f.SetCellFormula("My Sheet", "B1", "=SUM(Sheet1!1:2)"))
+	assert.NoError(t, f.CalcWorkbook(context.Background()))
+	for _, c := range [][]string{
+		{"Sheet1", "A2", "2"}, {"Sheet1", "D1", "20"}, {"Sheet1", "E1", "3"},
+		{"Sheet1", "F1", "2"}, {"Sheet1", "G1", "6"}, {"My Sheet", "A1", "4"},
+		{"My Sheet", "A2", "5"}, {"My Sheet", "B1", "34"},
+	} {
+		value, err := f.GetCellValue(c[0], c[1])
+		assert.NoError(t, err)
+		assert.Equal(t, c[2], value, c[0]+"!"+c[1])
+	}
+	assert.NoError(t, f.SetCellFormula("My Sheet", "C1", "=SUM(SheetN!A:A)"))
+	_, err = f.CalcCellValue("My Sheet", "C1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+
+	// Test get the unresolved references.
+	for _, formula := range []string{"=Missing+1", "=SUM(A:1)", "=OFFSET(A1,1,1)"} {
+		_, unresolved := f.getFormulaCellRanges("Sheet1", formula, 0)
+		assert.True(t, unresolved, formula)
+	}
+	ranges, unresolved := f.getFormulaCellRanges("Sheet1", "=SUM(1:3,$B$2)+[1]Sheet1!A1", 0)
+	assert.False(t, unresolved)
+	assert.Equal(t, []cellRange{
+		{From: cellRef{Sheet: "Sheet1", Col: 1, Row: 1}, To: cellRef{Sheet: "Sheet1", Col: TotalColumns, Row: 3}},
+		{From: cellRef{Sheet: "Sheet1", Col: 2, Row: 2}, To: cellRef{Sheet: "Sheet1", Col: 2, Row: 2}},
+	}, ranges)
+}
+
+func TestCalcWorkbook(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", 2))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=A1+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B2", "=A2*2"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=SUM(B1:B2)"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "=Sheet1!C1*2"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A2", "=ISBLANK(Sheet1!D1)"))
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A3", "=1/0"))
+	assert.NoError(t, f.CalcWorkbook(context.Background(), CalcOptions{Concurrency: 2}))
+	for _, c := range [][]string{
+		{"Sheet1", "B1", "2"}, {"Sheet1", "B2", "4"}, {"Sheet1", "C1", "6"},
+		{"Sheet2", "A1", "12"}, {"Sheet2", "A2", "1"}, {"Sheet2", "A3", "#DIV/0!"},
+	} {
+		value, err := f.GetCellValue(c[0], c[1])
+		assert.NoError(t, err)
+		assert.Equal(t, c[2], value, c[0]+"!"+c[1])
+	}
+	// Test calculate workbook with limits
+	assert.EqualError(t, f.CalcWorkbook(context.Background(), CalcOptions{MaxChainLength: 2}), "exceeds the maximum length of the dependency chain 2")
+	assert.NoError(t, f.CalcWorkbook(context.Background(), CalcOptions{MaxChainLength: 3, MaxDepth: 1}))
+	assert.EqualError(t, f.CalcWorkbook(context.Background(), CalcOptions{MaxCells: 3}), "exceeds the maximum number of visited cells 3")
+	// Test calculate workbook with canceled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, f.CalcWorkbook(ctx))
+	// Test calculate workbook with unsupported function
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A4", "=UNSUPPORT(A1)"))
+	assert.EqualError(t, f.CalcWorkbook(context.Background()), "Sheet2!A4: not support UNSUPPORT function")
+	// Test calculate workbook with circular reference
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A4", ""))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "=Sheet2!A1"))
+	assert.EqualError(t, f.CalcWorkbook(context.Background()), "circular reference in cell Sheet1!A1")
+}
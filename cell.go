@@ -35,14 +35,66 @@ const (
 // GetCellValue provides a function to get formatted value from cell by given
 // worksheet name and axis in XLSX file. If it is possible to apply a format
 // to the cell value, it will do so, if not then an error will be returned,
-// along with the raw value of the cell.
-func (f *File) GetCellValue(sheet, axis string) (string, error) {
+// along with the raw value of the cell. The raw value of the cell will be
+// returned without applying the number format if the RawCellValue of the
+// read options is true. For example, get the unformatted value of Sheet1!A1:
+//
+//    value, err := f.GetCellValue("Sheet1", "A1", excelize.ReadOptions{RawCellValue: true})
+//
+func (f *File) GetCellValue(sheet, axis string, opts ...ReadOptions) (string, error) {
 	return f.getCellStringFunc(sheet, axis, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
-		val, err := c.getValueFrom(f, f.sharedStringsReader())
+		val, err := c.getValueFrom(f, f.sharedStringsReadOnly(), parseReadOptions(opts...).RawCellValue)
 		return val, true, err
 	})
 }
 
+// GetCellTime provides a function to get the date and time value of the cell
+// by given worksheet name and axis, the serial number of the date will be
+// converted with the 1900 or 1904 date system of the workbook. For example,
+// get the date of Sheet1!A1:
+//
+//    t, err := f.GetCellTime("Sheet1", "A1")
+//
+func (f *File) GetCellTime(sheet, axis string) (time.Time, error) {
+	var t time.Time
+	_, err := f.getCellStringFunc(sheet, axis, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
+		var err error
+		t, err = f.cellTime(axis, c.T, c.V)
+		return "", true, err
+	})
+	if err == nil && t.IsZero() {
+		err = newInvalidCellTimeError(axis)
+	}
+	return t, err
+}
+
+// cellTime provides a function to convert the value of the cell to time by
+// given cell coordinates, cell type and raw value of the cell.
+func (f *File) cellTime(axis, typ, value string) (time.Time, error) {
+	switch typ {
+	case "", "n":
+		excelTime, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, newInvalidCellTimeError(axis)
+		}
+		return ExcelDateToTime(excelTime, f.date1904())
+	case "d":
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02", "15:04:05.999999999"} {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, newInvalidCellTimeError(axis)
+}
+
+// date1904 provides a function to check if the workbook uses the 1904 date
+// system.
+func (f *File) date1904() bool {
+	wb := f.workbookReadOnly()
+	return wb != nil && wb.WorkbookPr != nil && wb.WorkbookPr.Date1904
+}
+
 // SetCellValue provides a function to set value of a cell. The specified
 // coordinates should not be in the first row of the table. The following
 // shows the supported data types:
@@ -135,14 +187,18 @@ func (f *File) setCellTimeFunc(sheet, axis string, value time.Time) error {
 	if err != nil {
 		return err
 	}
+	xlsx.Lock()
 	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
+		xlsx.Unlock()
 		return err
 	}
 	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
 
 	var isNum bool
+	cellData.removeAnyAttrs("vm")
 	cellData.T, cellData.V, isNum, err = setCellTime(value)
+	xlsx.Unlock()
 	if err != nil {
 		return err
 	}
@@ -182,11 +238,14 @@ func (f *File) SetCellInt(sheet, axis string, value int) error {
 	if err != nil {
 		return err
 	}
+	xlsx.Lock()
+	defer xlsx.Unlock()
 	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
 	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.removeAnyAttrs("vm")
 	cellData.T, cellData.V = setCellInt(value)
 	return err
 }
@@ -203,11 +262,14 @@ func (f *File) SetCellBool(sheet, axis string, value bool) error {
 	if err != nil {
 		return err
 	}
+	xlsx.Lock()
+	defer xlsx.Unlock()
 	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
 	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.removeAnyAttrs("vm")
 	cellData.T, cellData.V = setCellBool(value)
 	return err
 }
@@ -236,11 +298,14 @@ func (f *File) SetCellFloat(sheet, axis string, value float64, prec, bitSize int
 	if err != nil {
 		return err
 	}
+	xlsx.Lock()
+	defer xlsx.Unlock()
 	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
 	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.removeAnyAttrs("vm")
 	cellData.T, cellData.V = setCellFloat(value, prec, bitSize)
 	return err
 }
@@ -257,11 +322,14 @@ func (f *File) SetCellStr(sheet, axis, value string) error {
 	if err != nil {
 		return err
 	}
+	xlsx.Lock()
+	defer xlsx.Unlock()
 	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
 	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.removeAnyAttrs("vm")
 	cellData.T, cellData.V = f.setCellString(value)
 	return err
 }
@@ -325,11 +393,14 @@ func (f *File) SetCellDefault(sheet, axis, value string) error {
 	if err != nil {
 		return err
 	}
+	xlsx.Lock()
+	defer xlsx.Unlock()
 	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
 	cellData.S = f.prepareCellStyle(xlsx, col, cellData.S)
+	cellData.removeAnyAttrs("vm")
 	cellData.T, cellData.V = setCellDefault(value)
 	return err
 }
@@ -340,8 +411,39 @@ func setCellDefault(value string) (t string, v string) {
 }
 
 // GetCellFormula provides a function to get formula from cell by given
-// worksheet name and axis in XLSX file.
-func (f *File) GetCellFormula(sheet, axis string) (string, error) {
+// worksheet name and axis in XLSX file. The formula will be converted to the
+// R1C1 reference style if it has been set by SetFormulaRefStyle, and be
+// converted to the localized formula if the Locale of the formula options
+// is specified. For example, get the formula of Sheet1!A3 in German:
+//
+//    locale := "de-DE"
+//    formula, err := f.GetCellFormula("Sheet1", "A3", excelize.FormulaOpts{Locale: &locale})
+//
+func (f *File) GetCellFormula(sheet, axis string, opts ...FormulaOpts) (string, error) {
+	formula, err := f.getCellFormula(sheet, axis)
+	if err != nil || formula == "" {
+		return formula, err
+	}
+	if f.formulaRefStyle == "R1C1" {
+		var origin string
+		if origin, err = f.getCellFormulaOrigin(sheet, axis); err != nil {
+			return "", err
+		}
+		if formula, err = FormulaToR1C1(formula, origin); err != nil {
+			return "", err
+		}
+	}
+	for _, o := range opts {
+		if o.Locale != nil {
+			return LocalizeFormula(formula, *o.Locale)
+		}
+	}
+	return formula, err
+}
+
+// getCellFormula provides a function to get formula which stored in the
+// spreadsheet from cell by given worksheet name and axis.
+func (f *File) getCellFormula(sheet, axis string) (string, error) {
 	return f.getCellStringFunc(sheet, axis, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
 		if c.F == nil {
 			return "", false, nil
@@ -353,23 +455,66 @@ func (f *File) GetCellFormula(sheet, axis string) (string, error) {
 	})
 }
 
-// FormulaOpts can be passed to SetCellFormula to use other formula types.
+// getCellFormulaOrigin provides a function to get the cell coordinates which
+// the relative references of the formula based on by given worksheet name
+// and axis. The shared formula is based on the top-left cell of the shared
+// formula range, and other formulas are based on the cell itself.
+func (f *File) getCellFormulaOrigin(sheet, axis string) (string, error) {
+	origin, err := f.getCellStringFunc(sheet, axis, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
+		if c.F == nil || c.F.T != STCellFormulaTypeShared {
+			return "", false, nil
+		}
+		origin := getSharedFormulaOrigin(x, c.F.Si)
+		return origin, origin != "", nil
+	})
+	if origin == "" {
+		origin = axis
+	}
+	return origin, err
+}
+
+// FormulaOpts can be passed to SetCellFormula to use other formula types, and
+// passed to SetCellFormula and GetCellFormula to use the localized formula.
 type FormulaOpts struct {
-	Type *string // Formula type
-	Ref  *string // Shared formula ref
+	Type   *string // Formula type
+	Ref    *string // Shared formula ref
+	Locale *string // Formula locale, such as de-DE
 }
 
 // SetCellFormula provides a function to set cell formula by given string and
-// worksheet name.
+// worksheet name. The localized formula will be converted to the en-US
+// formula if the Locale of the formula options is specified, and the formula
+// in R1C1 reference style will be converted to A1 reference style if it has
+// been set by SetFormulaRefStyle. For example, set the German formula on
+// Sheet1!A3:
+//
+//    locale := "de-DE"
+//    err := f.SetCellFormula("Sheet1", "A3", "=SUMME(A1;1,5)", excelize.FormulaOpts{Locale: &locale})
+//
 func (f *File) SetCellFormula(sheet, axis, formula string, opts ...FormulaOpts) error {
 	xlsx, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
+	for _, o := range opts {
+		if o.Locale != nil {
+			if formula, err = DelocalizeFormula(formula, *o.Locale); err != nil {
+				return err
+			}
+		}
+	}
+	if f.formulaRefStyle == "R1C1" {
+		if formula, err = FormulaToA1(formula, axis); err != nil {
+			return err
+		}
+	}
+	xlsx.Lock()
+	defer xlsx.Unlock()
 	cellData, _, _, err := f.prepareCell(xlsx, sheet, axis)
 	if err != nil {
 		return err
 	}
+	cellData.removeAnyAttrs("vm", "cm")
 	if formula == "" {
 		cellData.F = nil
 		f.deleteCalcChain(f.getSheetID(sheet), axis)
@@ -409,7 +554,7 @@ func (f *File) GetCellHyperLink(sheet, axis string) (bool, string, error) {
 		return false, "", err
 	}
 
-	xlsx, err := f.workSheetReader(sheet)
+	xlsx, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return false, "", err
 	}
@@ -602,6 +747,8 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 	if err != nil {
 		return err
 	}
+	ws.Lock()
+	defer ws.Unlock()
 	cellData, col, _, err := f.prepareCell(ws, sheet, cell)
 	if err != nil {
 		return err
@@ -647,6 +794,7 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 	sst.SI = append(sst.SI, si)
 	sst.Count++
 	sst.UniqueCount++
+	cellData.removeAnyAttrs("vm")
 	cellData.T, cellData.V = "s", strconv.Itoa(len(sst.SI)-1)
 	return err
 }
@@ -684,10 +832,9 @@ func (f *File) SetSheetRow(sheet, axis string, slice interface{}) error {
 	return err
 }
 
-// getCellInfo does common preparation for all SetCell* methods.
+// getCellInfo does common preparation for all SetCell* methods. The caller
+// should hold the lock of the worksheet until the cell has been updated.
 func (f *File) prepareCell(xlsx *xlsxWorksheet, sheet, cell string) (*xlsxC, int, int, error) {
-	xlsx.Lock()
-	defer xlsx.Unlock()
 	var err error
 	cell, err = f.mergeCellsParser(xlsx, cell)
 	if err != nil {
@@ -706,10 +853,12 @@ func (f *File) prepareCell(xlsx *xlsxWorksheet, sheet, cell string) (*xlsxC, int
 // getCellStringFunc does common value extraction workflow for all GetCell*
 // methods. Passed function implements specific part of required logic.
 func (f *File) getCellStringFunc(sheet, axis string, fn func(x *xlsxWorksheet, c *xlsxC) (string, bool, error)) (string, error) {
-	xlsx, err := f.workSheetReader(sheet)
+	xlsx, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return "", err
 	}
+	xlsx.Lock()
+	defer xlsx.Unlock()
 	axis, err = f.mergeCellsParser(xlsx, axis)
 	if err != nil {
 		return "", err
@@ -719,9 +868,6 @@ func (f *File) getCellStringFunc(sheet, axis string, fn func(x *xlsxWorksheet, c
 		return "", err
 	}
 
-	xlsx.Lock()
-	defer xlsx.Unlock()
-
 	lastRowNum := 0
 	if l := len(xlsx.SheetData.Row); l > 0 {
 		lastRowNum = xlsx.SheetData.Row[l-1].R
@@ -758,10 +904,19 @@ func (f *File) getCellStringFunc(sheet, axis string, fn func(x *xlsxWorksheet, c
 // it is possible to apply a format to the cell value, it will do so, if not
 // then an error will be returned, along with the raw value of the cell.
 func (f *File) formattedValue(s int, v string) string {
+	return f.formatValue(s, false, v)
+}
+
+// formatValue provides a function to return a value after formatted by given
+// style index, the value will not be formatted if the raw is true.
+func (f *File) formatValue(s int, raw bool, v string) string {
+	if raw {
+		return v
+	}
 	if s == 0 {
 		return v
 	}
-	styleSheet := f.stylesReader()
+	styleSheet := f.stylesReadOnly()
 	ok := builtInNumFmtFunc[*styleSheet.CellXfs.Xf[s].NumFmtID]
 	if ok != nil {
 		return ok(*styleSheet.CellXfs.Xf[s].NumFmtID, v)
@@ -838,6 +993,19 @@ func isOverlap(rect1, rect2 []int) bool {
 		cellInRef([]int{rect2[2], rect2[3]}, rect1)
 }
 
+// getSharedFormulaOrigin provides a function to get the top-left cell of the
+// shared formula range by given worksheet and the shared formula index.
+func getSharedFormulaOrigin(ws *xlsxWorksheet, si string) string {
+	for _, r := range ws.SheetData.Row {
+		for _, c := range r.C {
+			if c.F != nil && c.F.Ref != "" && c.F.T == STCellFormulaTypeShared && c.F.Si == si {
+				return strings.Split(c.F.Ref, ":")[0]
+			}
+		}
+	}
+	return ""
+}
+
 // getSharedForumula find a cell contains the same formula as another cell,
 // the "shared" value can be used for the t attribute and the si attribute can
 // be used to refer to the cell containing the formula. Two formulas are
@@ -136,6 +136,84 @@ func TestGetCellValue(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetCellValueOptions(t *testing.T) {
+	f := NewFile()
+	date := time.Date(2020, 10, 18, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", date))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 3.1415))
+	style, err := f.NewStyle(`{"number_format":2}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10/18/20 12:00", val)
+	val, err = f.GetCellValue("Sheet1", "A1", ReadOptions{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "44122.5", val)
+	val, err = f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.14", val)
+	val, err = f.GetCellValue("Sheet1", "B1", ReadOptions{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "3.1415", val)
+
+	rows, err := f.GetRows("Sheet1", ReadOptions{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"44122.5", "3.1415"}}, rows)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"10/18/20 12:00", "3.14"}}, rows)
+	cols, err := f.GetCols("Sheet1", ReadOptions{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"44122.5"}, {"3.1415"}}, cols)
+	cols, err = f.GetCols("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"10/18/20 12:00"}, {"3.14"}}, cols)
+}
+
+func TestGetCellTime(t *testing.T) {
+	f := NewFile()
+	date := time.Date(2020, 10, 18, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", date))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "text"))
+	cellTime, err := f.GetCellTime("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, date, cellTime)
+	// Test get cell time with 1904 date system.
+	f.WorkBook.WorkbookPr = &xlsxWorkbookPr{Date1904: true}
+	cellTime, err = f.GetCellTime("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, date.AddDate(4, 0, 1), cellTime)
+	// Test get cell time of the date type cell in ISO 8601 format.
+	f.Sheet["xl/worksheets/sheet1.xml"].SheetData.Row[0].C[0] = xlsxC{R: "A1", T: "d", V: "2020-10-18T12:00:00"}
+	cellTime, err = f.GetCellTime("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, date, cellTime)
+	f.Sheet["xl/worksheets/sheet1.xml"].SheetData.Row[0].C[0] = xlsxC{R: "A1", T: "d", V: "date"}
+	_, err = f.GetCellTime("Sheet1", "A1")
+	assert.EqualError(t, err, "cell A1 is not a date or time value")
+
+	_, err = f.GetCellTime("Sheet1", "B1")
+	assert.EqualError(t, err, "cell B1 is not a date or time value")
+	_, err = f.GetCellTime("Sheet1", "C1")
+	assert.EqualError(t, err, "cell C1 is not a date or time value")
+	_, err = f.GetCellTime("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+
+	// Test get cell time from the rows iterator.
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", date))
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	cells, err := rows.Cells()
+	assert.NoError(t, err)
+	cellTime, err = rows.CellTime(cells[0])
+	assert.NoError(t, err)
+	assert.Equal(t, date, cellTime)
+}
+
 func TestGetCellFormula(t *testing.T) {
 	// Test get cell formula on not exist worksheet.
 	f := NewFile()
@@ -146,6 +224,20 @@ func TestGetCellFormula(t *testing.T) {
 	assert.NoError(t, f.SetCellValue("Sheet1", "A1", true))
 	_, err = f.GetCellFormula("Sheet1", "A1")
 	assert.NoError(t, err)
+
+	// Test set and get cell formula with formula locale.
+	locale := "de-DE"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "=SUMME(A1;1,5)", FormulaOpts{Locale: &locale}))
+	formula, err := f.GetCellFormula("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "=SUM(A1,1.5)", formula)
+	formula, err = f.GetCellFormula("Sheet1", "B1", FormulaOpts{Locale: &locale})
+	assert.NoError(t, err)
+	assert.Equal(t, "=SUMME(A1;1,5)", formula)
+	locale = "xx"
+	assert.EqualError(t, f.SetCellFormula("Sheet1", "B1", "=SUM(1)", FormulaOpts{Locale: &locale}), "unsupported formula locale xx")
+	_, err = f.GetCellFormula("Sheet1", "B1", FormulaOpts{Locale: &locale})
+	assert.EqualError(t, err, "unsupported formula locale xx")
 }
 
 func ExampleFile_SetCellFloat() {
@@ -41,7 +41,9 @@ type Cols struct {
 }
 
 // GetCols return all the columns in a sheet by given worksheet name (case
-// sensitive). For example:
+// sensitive). The raw value of the cells will be returned without applying
+// the number format if the RawCellValue of the read options is true. For
+// example:
 //
 //    cols, err := f.GetCols("Sheet1")
 //    if err != nil {
@@ -55,14 +57,14 @@ type Cols struct {
 //        fmt.Println()
 //    }
 //
-func (f *File) GetCols(sheet string) ([][]string, error) {
+func (f *File) GetCols(sheet string, opts ...ReadOptions) ([][]string, error) {
 	cols, err := f.Cols(sheet)
 	if err != nil {
 		return nil, err
 	}
 	results := make([][]string, 0, 64)
 	for cols.Next() {
-		col, _ := cols.Rows()
+		col, _ := cols.Rows(opts...)
 		results = append(results, col)
 	}
 	return results, nil
@@ -79,18 +81,21 @@ func (cols *Cols) Error() error {
 	return cols.err
 }
 
-// Rows return the current column's row values.
-func (cols *Cols) Rows() ([]string, error) {
+// Rows return the current column's row values. The raw value of the cells
+// will be returned without applying the number format if the RawCellValue of
+// the read options is true.
+func (cols *Cols) Rows(opts ...ReadOptions) ([]string, error) {
 	var (
 		err              error
 		inElement        string
 		cellCol, cellRow int
 		rows             []string
+		raw              = parseReadOptions(opts...).RawCellValue
 	)
 	if cols.stashCol >= cols.curCol {
 		return rows, err
 	}
-	d := cols.f.sharedStringsReader()
+	d := cols.f.sharedStringsReadOnly()
 	decoder := cols.f.xmlNewDecoder(bytes.NewReader(cols.sheetXML))
 	for {
 		token, _ := decoder.Token()
@@ -125,7 +130,7 @@ func (cols *Cols) Rows() ([]string, error) {
 				if cellCol == cols.curCol {
 					colCell := xlsxC{}
 					_ = decoder.DecodeElement(&colCell, &startElement)
-					val, _ := colCell.getValueFrom(cols.f, d)
+					val, _ := colCell.getValueFrom(cols.f, d, raw)
 					rows = append(rows, val)
 				}
 			}
@@ -168,7 +173,9 @@ func (f *File) Cols(sheet string) (*Cols, error) {
 		cellCol, curRow, row int
 		err                  error
 	)
-	cols.sheetXML = f.readXML(name)
+	if cols.sheetXML, err = f.readBytes(name); err != nil {
+		return nil, err
+	}
 	decoder := f.xmlNewDecoder(bytes.NewReader(cols.sheetXML))
 	for {
 		token, _ := decoder.Token()
@@ -224,7 +231,7 @@ func (f *File) GetColVisible(sheet, col string) (bool, error) {
 		return visible, err
 	}
 
-	xlsx, err := f.workSheetReader(sheet)
+	xlsx, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return false, err
 	}
@@ -313,7 +320,7 @@ func (f *File) GetColOutlineLevel(sheet, col string) (uint8, error) {
 	if err != nil {
 		return level, err
 	}
-	xlsx, err := f.workSheetReader(sheet)
+	xlsx, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return 0, err
 	}
@@ -616,7 +623,7 @@ func (f *File) positionObjectPixels(sheet string, col, row, x1, y1, width, heigh
 // getColWidth provides a function to get column width in pixels by given
 // sheet name and column index.
 func (f *File) getColWidth(sheet string, col int) int {
-	xlsx, _ := f.workSheetReader(sheet)
+	xlsx, _ := f.workSheetReadOnly(sheet)
 	if xlsx.Cols != nil {
 		var width float64
 		for _, v := range xlsx.Cols.Col {
@@ -639,7 +646,7 @@ func (f *File) GetColWidth(sheet, col string) (float64, error) {
 	if err != nil {
 		return defaultColWidthPixels, err
 	}
-	xlsx, err := f.workSheetReader(sheet)
+	xlsx, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return defaultColWidthPixels, err
 	}
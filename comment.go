@@ -314,8 +314,8 @@ func (f *File) decodeVMLDrawingReader(path string) *decodeVmlDrawing {
 	var err error
 
 	if f.DecodeVMLDrawing[path] == nil {
-		c, ok := f.XLSX[path]
-		if ok {
+		if _, ok := f.XLSX[path]; ok {
+			c := f.readXML(path)
 			f.DecodeVMLDrawing[path] = new(decodeVmlDrawing)
 			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(c))).
 				Decode(f.DecodeVMLDrawing[path]); err != nil && err != io.EOF {
@@ -343,8 +343,8 @@ func (f *File) commentsReader(path string) *xlsxComments {
 	var err error
 
 	if f.Comments[path] == nil {
-		content, ok := f.XLSX[path]
-		if ok {
+		if _, ok := f.XLSX[path]; ok {
+			content := f.readXML(path)
 			f.Comments[path] = new(xlsxComments)
 			if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
 				Decode(f.Comments[path]); err != nil && err != io.EOF {
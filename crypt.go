@@ -23,6 +23,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"hash"
+	"io"
 	"math/rand"
 	"reflect"
 	"strings"
@@ -160,6 +161,21 @@ func Decrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 
 // Encrypt API encrypt data with the password.
 func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
+	var encryptedPackage, buf bytes.Buffer
+	encryptionInfoBuffer, err := encryptPackage(&encryptedPackage, bytes.NewReader(raw), int64(len(raw)), opt)
+	if err != nil {
+		return
+	}
+	err = writeCompoundFile(&buf, encryptionInfoBuffer, &encryptedPackage)
+	packageBuf = buf.Bytes()
+	return
+}
+
+// encryptPackage provides a function to encrypt the package read from the
+// reader chunk by chunk with the password by given size of the package, and
+// write the encrypted package to the writer. It returns the encryption info
+// of the encrypted package.
+func encryptPackage(w io.Writer, r io.Reader, size int64, opt *Options) (encryptionInfoBuffer []byte, err error) {
 	// Generate a random key to use to encrypt the document. Excel uses 32 bytes. We'll use the password to encrypt this key.
 	packageKey, _ := randomBytes(32)
 	keyDataSaltValue, _ := randomBytes(16)
@@ -187,22 +203,20 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 		},
 	}
 
+	// Generate a random array of bytes to use in HMAC. The docs say to use the same length as the key salt, but Excel seems to use 64.
+	hmacKey, _ := randomBytes(64)
+	h := hmac.New(sha512.New, hmacKey)
+
 	// Package Encryption
 
-	// Encrypt package using the package key.
-	encryptedPackage, err := cryptPackage(true, packageKey, raw, encryptionInfo)
-	if err != nil {
+	// Encrypt package using the package key, and create the HMAC of the encrypted package.
+	if err = encryptPackageStream(io.MultiWriter(w, h), r, size, packageKey, encryptionInfo); err != nil {
 		return
 	}
 
 	// Data Integrity
 
 	// Create the data integrity fields used by clients for integrity checks.
-	// Generate a random array of bytes to use in HMAC. The docs say to use the same length as the key salt, but Excel seems to use 64.
-	hmacKey, _ := randomBytes(64)
-	if err != nil {
-		return
-	}
 	// Create an initialization vector using the package encryption info and the appropriate block key.
 	hmacKeyIV, err := createIV(blockKeyHmacKey, encryptionInfo)
 	if err != nil {
@@ -210,11 +224,6 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	}
 	// Use the package key and the IV to encrypt the HMAC key.
 	encryptedHmacKey, err := crypt(true, encryptionInfo.KeyData.CipherAlgorithm, encryptionInfo.KeyData.CipherChaining, packageKey, hmacKeyIV, hmacKey)
-	// Create the HMAC.
-	h := hmac.New(sha512.New, append(hmacKey, encryptedPackage...))
-	for _, buf := range [][]byte{hmacKey, encryptedPackage} {
-		h.Write(buf)
-	}
 	hmacValue := h.Sum(nil)
 	// Generate an initialization vector for encrypting the resulting HMAC value.
 	hmacValueIV, err := createIV(blockKeyHmacValue, encryptionInfo)
@@ -267,14 +276,50 @@ func Encrypt(raw []byte, opt *Options) (packageBuf []byte, err error) {
 	}
 	encryptionInfo.KeyEncryptors.KeyEncryptor[0].EncryptedKey.EncryptedVerifierHashValue = base64.StdEncoding.EncodeToString(encryptedVerifierHashValue)
 	// Marshal the encryption info buffer.
-	encryptionInfoBuffer, err := xml.Marshal(encryptionInfo)
-	if err != nil {
-		return
+	return xml.Marshal(encryptionInfo)
+}
+
+// encryptPackageStream provides a function to encrypt the package read from
+// the reader chunk by chunk, and write the size of the package and the
+// encrypted chunks to the writer by given package key and encryption info.
+func encryptPackageStream(w io.Writer, r io.Reader, size int64, packageKey []byte, encryption Encryption) error {
+	if _, err := w.Write(createUInt32LEBuffer(int(size), 8)); err != nil {
+		return err
+	}
+	chunk := make([]byte, packageEncryptionChunkSize)
+	for i := 0; ; i++ {
+		n, err := io.ReadFull(r, chunk)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		// Pad the chunk if it is not an integer multiple of the block size
+		inputChunk := chunk[:n]
+		if remainder := n % encryption.KeyData.BlockSize; remainder != 0 {
+			inputChunk = append(inputChunk, make([]byte, encryption.KeyData.BlockSize-remainder)...)
+		}
+		iv, err := createIV(i, encryption)
+		if err != nil {
+			return err
+		}
+		outputChunk, err := crypt(true, encryption.KeyData.CipherAlgorithm, encryption.KeyData.CipherChaining, packageKey, iv, inputChunk)
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(outputChunk); err != nil {
+			return err
+		}
 	}
+}
+
+// writeCompoundFile provides a function to write the compound file binary
+// which contains the encryption info and the encrypted package to the
+// writer.
+func writeCompoundFile(w io.Writer, encryptionInfo []byte, encryptedPackage io.Reader) error {
 	// TODO: Create a new CFB.
-	_, _ = encryptedPackage, encryptionInfoBuffer
-	err = errors.New("not support encryption currently")
-	return
+	return errors.New("not support encryption currently")
 }
 
 // extractPart extract data from storage by specified part name.
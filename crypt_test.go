@@ -10,6 +10,8 @@
 package excelize
 
 import (
+	"bytes"
+	"encoding/base64"
 	"path/filepath"
 	"testing"
 
@@ -21,3 +23,22 @@ func TestEncrypt(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualError(t, f.SaveAs(filepath.Join("test", "TestEncrypt.xlsx"), Options{Password: "password"}), "not support encryption currently")
 }
+
+func TestEncryptPackageStream(t *testing.T) {
+	raw := bytes.Repeat([]byte("excelize"), 1000)
+	packageKey := make([]byte, 32)
+	encryption := Encryption{KeyData: KeyData{
+		BlockSize:       16,
+		CipherAlgorithm: "AES",
+		CipherChaining:  "ChainingModeCBC",
+		HashAlgorithm:   "SHA512",
+		SaltValue:       base64.StdEncoding.EncodeToString(make([]byte, 16)),
+	}}
+	var buf bytes.Buffer
+	assert.NoError(t, encryptPackageStream(&buf, bytes.NewReader(raw), int64(len(raw)), packageKey, encryption))
+	expected, err := cryptPackage(true, packageKey, append([]byte(nil), raw...), encryption)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, buf.Bytes())
+	// Test encrypt the package with the write error.
+	assert.EqualError(t, encryptPackageStream(errWriter{}, bytes.NewReader(raw), int64(len(raw)), packageKey, encryption), "write error")
+}
@@ -0,0 +1,250 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVOptions directly maps the options of the ImportCSV and ExportCSV.
+//
+// Comma specifies the field delimiter, default is ','. Use '\t' for the TSV
+// files.
+//
+// Comment specifies the comment character of the lines to be ignored on
+// import.
+//
+// LazyQuotes specifies a quote may appear in an unquoted field and a
+// non-doubled quote may appear in a quoted field on import.
+//
+// Charset specifies the character encoding of the imported file, which will
+// be decoded by the CharsetReader of the workbook, default is UTF-8.
+//
+// InferTypes specifies to convert the imported fields to numbers, booleans
+// and dates, otherwise all fields will be imported as strings. The numbers
+// with leading zeros such as "007" are kept as strings.
+//
+// QuoteAll specifies to quote all fields on export, otherwise the fields
+// will be quoted only if necessary.
+//
+// UseCRLF specifies to use \r\n as the line terminator on export.
+//
+// RawCellValue specifies to export the raw cell values instead of the
+// formatted values.
+type CSVOptions struct {
+	Comma        rune
+	Comment      rune
+	LazyQuotes   bool
+	Charset      string
+	InferTypes   bool
+	QuoteAll     bool
+	UseCRLF      bool
+	RawCellValue bool
+}
+
+// csvDateLayouts defined the layouts of the date and time values which can
+// be inferred on import, and whether the layout contains the time.
+var csvDateLayouts = []struct {
+	layout string
+	time   bool
+}{
+	{time.RFC3339Nano, true},
+	{"2006-01-02 15:04:05", true},
+	{"2006-01-02T15:04:05", true},
+	{"2006-01-02", false},
+}
+
+// parseCSVOptions provides a function to parse the options of the CSV
+// functions and set the default delimiter.
+func parseCSVOptions(opts ...CSVOptions) CSVOptions {
+	var options CSVOptions
+	for _, o := range opts {
+		options = o
+	}
+	if options.Comma == 0 {
+		options.Comma = ','
+	}
+	return options
+}
+
+// ImportCSV provides a function to import the CSV or TSV data to the
+// worksheet by given worksheet name, reader and options. The data will be
+// streamed to the worksheet through the StreamWriter, so the existing
+// content of the worksheet will be replaced. Note that the empty lines of
+// the data are ignored. For example, import a TSV file encoded in GBK to
+// Sheet1 with type inference:
+//
+//    file, err := os.Open("data.tsv")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    defer file.Close()
+//    err = f.ImportCSV("Sheet1", file, excelize.CSVOptions{
+//        Comma:      '\t',
+//        Charset:    "gbk",
+//        InferTypes: true,
+//    })
+//
+func (f *File) ImportCSV(sheet string, r io.Reader, opts ...CSVOptions) error {
+	options := parseCSVOptions(opts...)
+	var err error
+	if options.Charset != "" && !strings.EqualFold(options.Charset, "utf-8") {
+		if r, err = f.CharsetReader(options.Charset, r); err != nil {
+			return err
+		}
+	}
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	var dateStyle, timeStyle int
+	if options.InferTypes {
+		if dateStyle, err = f.NewStyle(&Style{NumFmt: 14}); err != nil {
+			return err
+		}
+		if timeStyle, err = f.NewStyle(&Style{NumFmt: 22}); err != nil {
+			return err
+		}
+	}
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.Comma, reader.Comment = options.Comma, options.Comment
+	reader.LazyQuotes, reader.FieldsPerRecord, reader.ReuseRecord = options.LazyQuotes, -1, true
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		values := make([]interface{}, len(record))
+		for i, field := range record {
+			if values[i] = field; options.InferTypes {
+				values[i] = inferCSVValue(field, dateStyle, timeStyle)
+			}
+		}
+		axis, err := CoordinatesToCellName(1, row)
+		if err != nil {
+			return err
+		}
+		if err = sw.SetRow(axis, values); err != nil {
+			return err
+		}
+	}
+	return sw.Flush()
+}
+
+// inferCSVValue provides a function to convert the field of the CSV to the
+// number, boolean or date value with given date and time style ID. The
+// special values NaN and infinity will be kept as the strings.
+func inferCSVValue(field string, dateStyle, timeStyle int) interface{} {
+	value := strings.TrimSpace(field)
+	if value == "" {
+		return field
+	}
+	if strings.EqualFold(value, "true") || strings.EqualFold(value, "false") {
+		return strings.EqualFold(value, "true")
+	}
+	digits := strings.TrimLeft(value, "+-")
+	if len(digits) > 1 && digits[0] == '0' && digits[1] != '.' {
+		return field
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n
+	}
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return field
+		}
+		return n
+	}
+	for _, d := range csvDateLayouts {
+		if t, err := time.Parse(d.layout, value); err == nil {
+			if d.time {
+				return Cell{StyleID: timeStyle, Value: t}
+			}
+			return Cell{StyleID: dateStyle, Value: t}
+		}
+	}
+	return field
+}
+
+// ExportCSV provides a function to export the worksheet as CSV or TSV data
+// by given worksheet name, writer and options. The formatted cell values
+// will be exported by default, and the output is encoded in UTF-8. For
+// example, export the raw cell values of Sheet1 as TSV:
+//
+//    err := f.ExportCSV("Sheet1", os.Stdout, excelize.CSVOptions{
+//        Comma:        '\t',
+//        RawCellValue: true,
+//    })
+//
+func (f *File) ExportCSV(sheet string, w io.Writer, opts ...CSVOptions) error {
+	options := parseCSVOptions(opts...)
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	bw := bufio.NewWriter(w)
+	writer := csv.NewWriter(bw)
+	writer.Comma, writer.UseCRLF = options.Comma, options.UseCRLF
+	for rows.Next() {
+		record, err := rows.Columns(ReadOptions{RawCellValue: options.RawCellValue})
+		if err != nil {
+			return err
+		}
+		if options.QuoteAll {
+			err = writeQuotedCSVRecord(bw, record, options)
+		} else {
+			err = writer.Write(record)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err = rows.Error(); err != nil {
+		return err
+	}
+	if writer.Flush(); writer.Error() != nil {
+		return writer.Error()
+	}
+	return bw.Flush()
+}
+
+// writeQuotedCSVRecord provides a function to write the CSV record with all
+// fields quoted.
+func writeQuotedCSVRecord(w *bufio.Writer, record []string, options CSVOptions) error {
+	for i, field := range record {
+		if i > 0 {
+			if _, err := w.WriteRune(options.Comma); err != nil {
+				return err
+			}
+		}
+		if _, err := w.WriteString(`"` + strings.Replace(field, `"`, `""`, -1) + `"`); err != nil {
+			return err
+		}
+	}
+	lineEnding := "\n"
+	if options.UseCRLF {
+		lineEnding = "\r\n"
+	}
+	_, err := w.WriteString(lineEnding)
+	return err
+}
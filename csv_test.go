@@ -0,0 +1,119 @@
+package excelize
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportCSV(t *testing.T) {
+	f := NewFile()
+	data := "Name,Code,Price,Active,Date,Updated\n" +
+		"\"Widget, large\",007,12.5,TRUE,2020-10-18,2020-10-18 12:30:00\n" +
+		"Gadget,42,-3,false,not a date,2020-10-18T12:30:00Z\n" +
+		"\n" +
+		"Short\n"
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader(data), CSVOptions{InferTypes: true}))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Name", "Code", "Price", "Active", "Date", "Updated"},
+		{"Widget, large", "007", "12.5", "1", "10-18-20", "10/18/20 12:30"},
+		{"Gadget", "42", "-3", "0", "not a date", "10/18/20 12:30"},
+		{"Short"},
+	}, rows)
+	for axis, expected := range map[string]string{"B2": "str", "C2": "", "D2": "b", "B3": ""} {
+		ws, err := f.workSheetReader("Sheet1")
+		assert.NoError(t, err)
+		col, row, err := CellNameToCoordinates(axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ws.SheetData.Row[row-1].C[col-1].T, axis)
+	}
+	updated, err := f.GetCellTime("Sheet1", "F2")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 10, 18, 12, 30, 0, 0, time.UTC), updated.Round(time.Second))
+
+	// Test import CSV with the special float values as the strings.
+	f = NewFile()
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("NaN,Inf,-Infinity,+inf,1e400\n"), CSVOptions{InferTypes: true}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"NaN", "Inf", "-Infinity", "+inf", "1e400"}}, rows)
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	for _, c := range ws.SheetData.Row[0].C {
+		assert.Equal(t, "str", c.T, c.R)
+	}
+
+	// Test import TSV without type inference.
+	f = NewFile()
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("# comment\nA\t1\nB\t2\n"), CSVOptions{Comma: '\t', Comment: '#'}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A", "1"}, {"B", "2"}}, rows)
+	ws, err = f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "str", ws.SheetData.Row[0].C[1].T)
+
+	// Test import CSV with charset.
+	f = NewFile()
+	assert.NoError(t, f.ImportCSV("Sheet1", bytes.NewReader([]byte{'c', 'a', 'f', 0xe9, '\n'}), CSVOptions{Charset: "iso-8859-1"}))
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "café", value)
+
+	// Test import CSV with invalid arguments.
+	assert.EqualError(t, f.ImportCSV("SheetN", strings.NewReader("")), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ImportCSV("Sheet1", strings.NewReader(""), CSVOptions{Charset: "unknown"}), `unsupported charset: "unknown"`)
+	assert.EqualError(t, f.ImportCSV("Sheet1", strings.NewReader("a\"b\n")), `parse error on line 1, column 2: bare " in non-quoted-field`)
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("a\"b\n"), CSVOptions{LazyQuotes: true}))
+	assert.EqualError(t, f.ImportCSV("Sheet1", iotestErrReader{}), "read error")
+}
+
+func TestExportCSV(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Note", "Date"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Widget, large", `say "hi"`, time.Date(2020, 10, 18, 12, 0, 0, 0, time.UTC)}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B4", 1.5))
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf))
+	assert.Equal(t, "Name,Note,Date\n\"Widget, large\",\"say \"\"hi\"\"\",10/18/20 12:00\n\n,1.5\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{Comma: '\t', RawCellValue: true, UseCRLF: true}))
+	assert.Equal(t, "Name\tNote\tDate\r\nWidget, large\t\"say \"\"hi\"\"\"\t44122.5\r\n\r\n\t1.5\r\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{QuoteAll: true}))
+	assert.Equal(t, "\"Name\",\"Note\",\"Date\"\n\"Widget, large\",\"say \"\"hi\"\"\",\"10/18/20 12:00\"\n\n\"\",\"1.5\"\n", buf.String())
+
+	// Test export and import round trip, the empty lines are ignored on import.
+	buf.Reset()
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf))
+	f2 := NewFile()
+	assert.NoError(t, f2.ImportCSV("Sheet1", &buf))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	imported, err := f2.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, append(rows[:2], rows[3:]...), imported)
+
+	// Test export CSV with invalid arguments.
+	assert.EqualError(t, f.ExportCSV("SheetN", &buf), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ExportCSV("Sheet1", iotestErrWriter{}), "write error")
+	assert.EqualError(t, f.ExportCSV("Sheet1", iotestErrWriter{}, CSVOptions{QuoteAll: true}), "write error")
+	assert.EqualError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{Comma: '"'}), "csv: invalid field or comment delimiter")
+}
+
+type iotestErrReader struct{}
+
+func (iotestErrReader) Read(p []byte) (int, error) { return 0, errors.New("read error") }
+
+type iotestErrWriter struct{}
+
+func (iotestErrWriter) Write(p []byte) (int, error) { return 0, errors.New("write error") }
@@ -0,0 +1,837 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The types of the differences reported by Diff.
+const (
+	DiffTypeAdded    = "added"
+	DiffTypeRemoved  = "removed"
+	DiffTypeModified = "modified"
+)
+
+// diffAlignLimit defined the maximum number of the compared pairs for
+// aligning the rows or columns by the longest common subsequence, the rows
+// or columns beyond the limit will be aligned by the position.
+const diffAlignLimit = 1 << 22
+
+// WorkbookDiff directly maps the differences between two workbooks.
+// AddedSheets and RemovedSheets specify the names of the worksheets which
+// only exist in the new or old workbook. Sheets specifies the differences of
+// the worksheets which exist in both workbooks, and DefinedNames specifies
+// the differences of the defined names.
+type WorkbookDiff struct {
+	AddedSheets   []string
+	RemovedSheets []string
+	Sheets        []SheetDiff
+	DefinedNames  []DefinedNameDiff
+}
+
+// SheetDiff directly maps the differences of a worksheet. InsertedRows and
+// InsertedCols specify the row and column numbers in the new worksheet,
+// RemovedRows and RemovedCols specify the row and column numbers in the old
+// worksheet. The cells in the inserted or removed rows and columns are not
+// reported in Cells.
+type SheetDiff struct {
+	Sheet        string
+	InsertedRows []int
+	RemovedRows  []int
+	InsertedCols []int
+	RemovedCols  []int
+	Cells        []CellDiff
+	MergeCells   []MergeCellDiff
+	Tables       []TableDiff
+	Comments     []CommentDiff
+}
+
+// CellDiff directly maps the difference of a cell. OldAxis and NewAxis
+// specify the coordinates of the cell in the old and new worksheets, which
+// are different if the rows or columns before the cell have been inserted or
+// removed. OldStyle and NewStyle are the style indexes in each workbook, the
+// style is compared by the formatting instead of the index.
+type CellDiff struct {
+	Type         string
+	OldAxis      string
+	NewAxis      string
+	OldValue     string
+	NewValue     string
+	OldFormula   string
+	NewFormula   string
+	OldStyle     int
+	NewStyle     int
+	StyleChanged bool
+}
+
+// MergeCellDiff directly maps the added or removed merged cells, Ref
+// specifies the range in the new worksheet for the added merged cells, and
+// in the old worksheet for the removed merged cells.
+type MergeCellDiff struct {
+	Type string
+	Ref  string
+}
+
+// TableDiff directly maps the difference of a table, the tables are matched
+// by the name.
+type TableDiff struct {
+	Type   string
+	Name   string
+	OldRef string
+	NewRef string
+}
+
+// CommentDiff directly maps the difference of a comment, the comments are
+// matched by the cell.
+type CommentDiff struct {
+	Type      string
+	OldRef    string
+	NewRef    string
+	OldAuthor string
+	NewAuthor string
+	OldText   string
+	NewText   string
+}
+
+// DefinedNameDiff directly maps the difference of a defined name, the
+// defined names are matched by the name and scope.
+type DefinedNameDiff struct {
+	Type        string
+	Name        string
+	Scope       string
+	OldRefersTo string
+	NewRefersTo string
+}
+
+// Empty returns true if there are no differences between the workbooks.
+func (d *WorkbookDiff) Empty() bool {
+	return len(d.AddedSheets) == 0 && len(d.RemovedSheets) == 0 &&
+		len(d.Sheets) == 0 && len(d.DefinedNames) == 0
+}
+
+// String returns the text representation of the differences between the
+// workbooks, one difference per line.
+func (d *WorkbookDiff) String() string {
+	var lines []string
+	for _, sheet := range d.AddedSheets {
+		lines = append(lines, fmt.Sprintf("%s: sheet added", sheet))
+	}
+	for _, sheet := range d.RemovedSheets {
+		lines = append(lines, fmt.Sprintf("%s: sheet removed", sheet))
+	}
+	for _, dn := range d.DefinedNames {
+		lines = append(lines, dn.String())
+	}
+	for _, sheet := range d.Sheets {
+		if text := sheet.String(); text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// String returns the text representation of the differences of the
+// worksheet, one difference per line.
+func (d SheetDiff) String() string {
+	var lines []string
+	for _, row := range d.InsertedRows {
+		lines = append(lines, fmt.Sprintf("%s: row %d inserted", d.Sheet, row))
+	}
+	for _, row := range d.RemovedRows {
+		lines = append(lines, fmt.Sprintf("%s: row %d removed", d.Sheet, row))
+	}
+	for _, col := range d.InsertedCols {
+		name, _ := ColumnNumberToName(col)
+		lines = append(lines, fmt.Sprintf("%s: column %s inserted", d.Sheet, name))
+	}
+	for _, col := range d.RemovedCols {
+		name, _ := ColumnNumberToName(col)
+		lines = append(lines, fmt.Sprintf("%s: column %s removed", d.Sheet, name))
+	}
+	for _, cell := range d.Cells {
+		lines = append(lines, d.Sheet+"!"+cell.String())
+	}
+	for _, mergeCell := range d.MergeCells {
+		lines = append(lines, fmt.Sprintf("%s!%s: merged cells %s", d.Sheet, mergeCell.Ref, mergeCell.Type))
+	}
+	for _, table := range d.Tables {
+		lines = append(lines, d.Sheet+"!"+table.String())
+	}
+	for _, comment := range d.Comments {
+		lines = append(lines, d.Sheet+"!"+comment.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffLocation returns the location of the difference by given old and new
+// references.
+func diffLocation(oldRef, newRef string) string {
+	if oldRef == "" || oldRef == newRef {
+		return newRef
+	}
+	if newRef == "" {
+		return oldRef
+	}
+	return oldRef + " -> " + newRef
+}
+
+// String returns the text representation of the difference of the cell.
+func (d CellDiff) String() string {
+	changes := []string{}
+	if d.OldValue != d.NewValue {
+		changes = append(changes, fmt.Sprintf("value %q -> %q", d.OldValue, d.NewValue))
+	}
+	if d.OldFormula != d.NewFormula {
+		changes = append(changes, fmt.Sprintf("formula %q -> %q", d.OldFormula, d.NewFormula))
+	}
+	if d.StyleChanged {
+		changes = append(changes, fmt.Sprintf("style %d -> %d", d.OldStyle, d.NewStyle))
+	}
+	return fmt.Sprintf("%s: cell %s, %s", diffLocation(d.OldAxis, d.NewAxis), d.Type, strings.Join(changes, ", "))
+}
+
+// String returns the text representation of the difference of the table.
+func (d TableDiff) String() string {
+	text := fmt.Sprintf("%s: table %s %s", diffLocation(d.OldRef, d.NewRef), d.Name, d.Type)
+	if d.Type == DiffTypeModified {
+		text += fmt.Sprintf(", range %s -> %s", d.OldRef, d.NewRef)
+	}
+	return text
+}
+
+// String returns the text representation of the difference of the comment.
+func (d CommentDiff) String() string {
+	text := fmt.Sprintf("%s: comment %s", diffLocation(d.OldRef, d.NewRef), d.Type)
+	if d.OldAuthor != d.NewAuthor {
+		text += fmt.Sprintf(", author %q -> %q", d.OldAuthor, d.NewAuthor)
+	}
+	if d.OldText != d.NewText {
+		text += fmt.Sprintf(", text %q -> %q", d.OldText, d.NewText)
+	}
+	return text
+}
+
+// String returns the text representation of the difference of the defined
+// name.
+func (d DefinedNameDiff) String() string {
+	name := d.Name
+	if d.Scope != "" && d.Scope != "Workbook" {
+		name = d.Scope + "!" + d.Name
+	}
+	return fmt.Sprintf("%s: defined name %s, refers to %q -> %q", name, d.Type, d.OldRefersTo, d.NewRefersTo)
+}
+
+// diffCell directly maps the compared content of a cell. The r1c1 is the
+// formula in R1C1 reference style, which is used to compare the formulas
+// regardless of the positions of the cells.
+type diffCell struct {
+	value, formula, r1c1 string
+	style                int
+}
+
+// diffSheet directly maps the cells of a worksheet for comparing.
+type diffSheet struct {
+	cells      map[int]map[int]diffCell
+	rows, cols int
+}
+
+// differ directly maps the state of comparing two workbooks.
+type differ struct {
+	files  [2]*File
+	styles [2]map[int]string
+}
+
+// Diff provides a function to compare two workbooks and returns the
+// structured differences of the worksheets and defined names. The worksheets
+// are matched by the name. The rows and columns of the worksheets are
+// aligned by the content to find the inserted and removed rows and columns,
+// and then the raw value, formula and style of the aligned cells are
+// compared. The formulas are compared in R1C1 reference style, so the
+// relative references shifted by the inserted or removed rows and columns
+// are not reported. The merged cells, tables and comments of the worksheets
+// are compared after the alignment. For example, print the differences
+// between two workbooks:
+//
+//    diff, err := excelize.Diff(a, b)
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    if !diff.Empty() {
+//        fmt.Println(diff)
+//    }
+//
+func Diff(a, b *File) (*WorkbookDiff, error) {
+	d := &differ{files: [2]*File{a, b}, styles: [2]map[int]string{{}, {}}}
+	result := &WorkbookDiff{DefinedNames: diffDefinedNames(a.GetDefinedName(), b.GetDefinedName())}
+	oldSheets, newSheets := a.GetSheetList(), b.GetSheetList()
+	oldComments, newComments := a.GetComments(), b.GetComments()
+	for _, sheet := range oldSheets {
+		if b.GetSheetIndex(sheet) == -1 {
+			result.RemovedSheets = append(result.RemovedSheets, sheet)
+			continue
+		}
+		if !strings.HasPrefix(a.sheetMap[trimSheetName(sheet)], "xl/worksheets/") ||
+			!strings.HasPrefix(b.sheetMap[trimSheetName(sheet)], "xl/worksheets/") {
+			continue
+		}
+		sheetDiff, err := d.diffSheet(sheet, oldComments[sheet], newComments[sheet])
+		if err != nil {
+			return result, err
+		}
+		if sheetDiff != nil {
+			result.Sheets = append(result.Sheets, *sheetDiff)
+		}
+	}
+	for _, sheet := range newSheets {
+		if a.GetSheetIndex(sheet) == -1 {
+			result.AddedSheets = append(result.AddedSheets, sheet)
+		}
+	}
+	return result, nil
+}
+
+// loadDiffSheet provides a function to read the cells of the worksheet for
+// comparing by given workbook and worksheet name.
+func loadDiffSheet(f *File, sheet string) (*diffSheet, error) {
+	s := &diffSheet{cells: make(map[int]map[int]diffCell)}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		cells, err := rows.Cells()
+		if err != nil {
+			return nil, err
+		}
+		for _, cell := range cells {
+			col, row, err := CellNameToCoordinates(cell.Axis)
+			if err != nil {
+				return nil, err
+			}
+			if s.cells[row] == nil {
+				s.cells[row] = make(map[int]diffCell)
+			}
+			c := diffCell{value: cell.Value, formula: cell.Formula, r1c1: cell.Formula, style: cell.StyleID}
+			if c.formula != "" {
+				if r1c1, err := convertFormulaRefStyle(c.formula, col, row, true); err == nil {
+					c.r1c1 = r1c1
+				}
+			}
+			s.cells[row][col] = c
+			if row > s.rows {
+				s.rows = row
+			}
+			if col > s.cols {
+				s.cols = col
+			}
+		}
+	}
+	return s, rows.Error()
+}
+
+// key returns the compared content of the cell by given row and column
+// number.
+func (s *diffSheet) key(row, col int) string {
+	c, ok := s.cells[row][col]
+	if !ok {
+		return ""
+	}
+	return c.value + "\x00" + c.r1c1
+}
+
+// rowKeys returns the content of the cells in the rows by the columns. The
+// non-empty cells of the row in the column order will be used if the
+// columns are not specified, and the content will be sorted for matching the
+// rows regardless of the column positions.
+func (s *diffSheet) rowKeys(cols []int) [][]string {
+	keys := make([][]string, s.rows)
+	for row := 1; row <= s.rows; row++ {
+		if cols != nil {
+			keys[row-1] = make([]string, len(cols))
+			for i, col := range cols {
+				keys[row-1][i] = s.key(row, col)
+			}
+			continue
+		}
+		for col := range s.cells[row] {
+			keys[row-1] = append(keys[row-1], s.key(row, col))
+		}
+		sort.Strings(keys[row-1])
+	}
+	return keys
+}
+
+// colKeys returns the content of the cells in the columns by the rows.
+func (s *diffSheet) colKeys(rows []int) [][]string {
+	keys := make([][]string, s.cols)
+	for col := 1; col <= s.cols; col++ {
+		keys[col-1] = make([]string, len(rows))
+		for i, row := range rows {
+			keys[col-1][i] = s.key(row, col)
+		}
+	}
+	return keys
+}
+
+// similar provides a function to check if the rows or columns are similar
+// by given content of the cells, at least half of the non-empty cells should
+// be equal. The cells are compared by the position if the positional is
+// true, otherwise the sorted content are compared regardless of the
+// positions.
+func similar(a, b []string, positional bool) bool {
+	var equal, total int
+	if positional {
+		for i := range a {
+			if a[i] == "" && b[i] == "" {
+				continue
+			}
+			if total++; a[i] == b[i] {
+				equal++
+			}
+		}
+		return equal*2 >= total
+	}
+	if total = len(a); len(b) > total {
+		total = len(b)
+	}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			equal, i, j = equal+1, i+1, j+1
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return equal*2 >= total
+}
+
+// alignment directly maps the aligned rows or columns of two worksheets.
+// The pairs are the numbers of the matched rows or columns in the old and
+// new worksheets.
+type alignment struct {
+	pairs             [][2]int
+	removed, inserted []int
+}
+
+// numbers returns the numbers of the matched rows or columns in the old
+// worksheet by given index 0, or in the new worksheet by given index 1.
+func (a *alignment) numbers(idx int) []int {
+	nums := make([]int, len(a.pairs))
+	for i, p := range a.pairs {
+		nums[i] = p[idx]
+	}
+	return nums
+}
+
+// mapping returns the map of the matched numbers from the old worksheet to
+// the new worksheet.
+func (a *alignment) mapping() map[int]int {
+	m := make(map[int]int, len(a.pairs))
+	for _, p := range a.pairs {
+		m[p[0]] = p[1]
+	}
+	return m
+}
+
+// align provides a function to align the rows or columns by given content
+// of the old and new worksheets. The similar rows or columns are matched by
+// the longest common subsequence, and the unmatched rows or columns between
+// the matches are paired by the position, the rest of them are inserted or
+// removed.
+func align(a, b [][]string, positional bool) *alignment {
+	var (
+		result         = &alignment{}
+		prefix, suffix int
+	)
+	for prefix < len(a) && prefix < len(b) && similar(a[prefix], b[prefix], positional) {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && similar(a[len(a)-1-suffix], b[len(b)-1-suffix], positional) {
+		suffix++
+	}
+	for i := 0; i < prefix; i++ {
+		result.pairs = append(result.pairs, [2]int{i + 1, i + 1})
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	var matches [][2]int
+	if n, m := len(midA), len(midB); n > 0 && m > 0 && n*m <= diffAlignLimit {
+		// the length of the longest common subsequence of the suffixes
+		lcs := make([]int32, (n+1)*(m+1))
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				l := lcs[(i+1)*(m+1)+j]
+				if lcs[i*(m+1)+j+1] > l {
+					l = lcs[i*(m+1)+j+1]
+				}
+				if lcs[(i+1)*(m+1)+j+1]+1 > l && similar(midA[i], midB[j], positional) {
+					l = lcs[(i+1)*(m+1)+j+1] + 1
+				}
+				lcs[i*(m+1)+j] = l
+			}
+		}
+		for i, j := 0, 0; i < n && j < m; {
+			switch {
+			case lcs[i*(m+1)+j] == lcs[(i+1)*(m+1)+j+1]+1 && similar(midA[i], midB[j], positional):
+				matches = append(matches, [2]int{i, j})
+				i, j = i+1, j+1
+			case lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]:
+				i++
+			default:
+				j++
+			}
+		}
+	}
+	matches = append(matches, [2]int{len(midA), len(midB)})
+	i, j := 0, 0
+	for k, match := range matches {
+		for ; i < match[0] && j < match[1]; i, j = i+1, j+1 {
+			result.pairs = append(result.pairs, [2]int{prefix + i + 1, prefix + j + 1})
+		}
+		for ; i < match[0]; i++ {
+			result.removed = append(result.removed, prefix+i+1)
+		}
+		for ; j < match[1]; j++ {
+			result.inserted = append(result.inserted, prefix+j+1)
+		}
+		if k < len(matches)-1 {
+			result.pairs = append(result.pairs, [2]int{prefix + i + 1, prefix + j + 1})
+			i, j = i+1, j+1
+		}
+	}
+	for k := 0; k < suffix; k++ {
+		result.pairs = append(result.pairs, [2]int{len(a) - suffix + k + 1, len(b) - suffix + k + 1})
+	}
+	return result
+}
+
+// styleKey provides a function to get the formatting of the style by given
+// workbook index and style index, which is used to compare the styles of
+// different workbooks.
+func (d *differ) styleKey(idx, styleID int) string {
+	if key, ok := d.styles[idx][styleID]; ok {
+		return key
+	}
+	var (
+		key   string
+		style struct {
+			NumFmt     string
+			Font       *xlsxFont
+			Fill       *xlsxFill
+			Border     *xlsxBorder
+			Alignment  *xlsxAlignment
+			Protection *xlsxProtection
+		}
+		ss = d.files[idx].stylesReadOnly()
+	)
+	if ss != nil && ss.CellXfs != nil && styleID >= 0 && styleID < len(ss.CellXfs.Xf) {
+		xf := ss.CellXfs.Xf[styleID]
+		if xf.NumFmtID != nil {
+			style.NumFmt = builtInNumFmt[*xf.NumFmtID]
+			if style.NumFmt == "" {
+				style.NumFmt = strconv.Itoa(*xf.NumFmtID)
+			}
+			if ss.NumFmts != nil {
+				for _, numFmt := range ss.NumFmts.NumFmt {
+					if numFmt.NumFmtID == *xf.NumFmtID {
+						style.NumFmt = numFmt.FormatCode
+					}
+				}
+			}
+		}
+		if xf.FontID != nil && ss.Fonts != nil && *xf.FontID >= 0 && *xf.FontID < len(ss.Fonts.Font) {
+			style.Font = ss.Fonts.Font[*xf.FontID]
+		}
+		if xf.FillID != nil && ss.Fills != nil && *xf.FillID >= 0 && *xf.FillID < len(ss.Fills.Fill) {
+			style.Fill = ss.Fills.Fill[*xf.FillID]
+		}
+		if xf.BorderID != nil && ss.Borders != nil && *xf.BorderID >= 0 && *xf.BorderID < len(ss.Borders.Border) {
+			style.Border = ss.Borders.Border[*xf.BorderID]
+		}
+		style.Alignment, style.Protection = xf.Alignment, xf.Protection
+		content, _ := json.Marshal(style)
+		key = string(content)
+	}
+	d.styles[idx][styleID] = key
+	return key
+}
+
+// diffSheet provides a function to compare the worksheet by given worksheet
+// name and comments of the worksheet in each workbook, returns nil if there
+// are no differences.
+func (d *differ) diffSheet(sheet string, oldComments, newComments []Comment) (*SheetDiff, error) {
+	a, err := loadDiffSheet(d.files[0], sheet)
+	if err != nil {
+		return nil, err
+	}
+	b, err := loadDiffSheet(d.files[1], sheet)
+	if err != nil {
+		return nil, err
+	}
+	// pad the worksheets to the same size with the empty rows and columns,
+	// align the rows by the content without the column positions first,
+	// then align the columns by the matched rows, and realign the rows by
+	// the matched columns
+	oldRows, oldCols, newRows, newCols := a.rows, a.cols, b.rows, b.cols
+	if newRows > a.rows {
+		a.rows = newRows
+	}
+	if newCols > a.cols {
+		a.cols = newCols
+	}
+	b.rows, b.cols = a.rows, a.cols
+	rows := align(a.rowKeys(nil), b.rowKeys(nil), false)
+	cols := align(a.colKeys(rows.numbers(0)), b.colKeys(rows.numbers(1)), true)
+	rows = align(a.rowKeys(cols.numbers(0)), b.rowKeys(cols.numbers(1)), true)
+	result := &SheetDiff{
+		Sheet:        sheet,
+		InsertedRows: trimDiffPadding(rows.inserted, newRows),
+		RemovedRows:  trimDiffPadding(rows.removed, oldRows),
+		InsertedCols: trimDiffPadding(cols.inserted, newCols),
+		RemovedCols:  trimDiffPadding(cols.removed, oldCols),
+	}
+	for _, row := range rows.pairs {
+		for _, col := range cols.pairs {
+			oldCell, oldOK := a.cells[row[0]][col[0]]
+			newCell, newOK := b.cells[row[1]][col[1]]
+			if !oldOK && !newOK {
+				continue
+			}
+			cellDiff := CellDiff{
+				Type:       DiffTypeModified,
+				OldValue:   oldCell.value,
+				NewValue:   newCell.value,
+				OldFormula: oldCell.formula,
+				NewFormula: newCell.formula,
+				OldStyle:   oldCell.style,
+				NewStyle:   newCell.style,
+			}
+			cellDiff.StyleChanged = d.styleKey(0, oldCell.style) != d.styleKey(1, newCell.style)
+			if cellDiff.OldValue == cellDiff.NewValue && oldCell.r1c1 == newCell.r1c1 && !cellDiff.StyleChanged {
+				continue
+			}
+			if !oldOK {
+				cellDiff.Type = DiffTypeAdded
+			} else if !newOK {
+				cellDiff.Type = DiffTypeRemoved
+			}
+			cellDiff.OldAxis, _ = CoordinatesToCellName(col[0], row[0])
+			cellDiff.NewAxis, _ = CoordinatesToCellName(col[1], row[1])
+			result.Cells = append(result.Cells, cellDiff)
+		}
+	}
+	refMapper := &diffRefMapper{rows: rows.mapping(), cols: cols.mapping(), maxRow: a.rows, maxCol: a.cols}
+	if result.MergeCells, err = d.diffMergeCells(sheet, refMapper); err != nil {
+		return nil, err
+	}
+	if result.Tables, err = d.diffTables(sheet, refMapper); err != nil {
+		return nil, err
+	}
+	result.Comments = diffComments(oldComments, newComments, refMapper)
+	if len(result.InsertedRows) == 0 && len(result.RemovedRows) == 0 &&
+		len(result.InsertedCols) == 0 && len(result.RemovedCols) == 0 &&
+		len(result.Cells) == 0 && len(result.MergeCells) == 0 &&
+		len(result.Tables) == 0 && len(result.Comments) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// trimDiffPadding provides a function to remove the padded empty rows or
+// columns by given inserted or removed numbers and the original size of the
+// worksheet.
+func trimDiffPadding(nums []int, size int) []int {
+	var trimmed []int
+	for _, num := range nums {
+		if num <= size {
+			trimmed = append(trimmed, num)
+		}
+	}
+	return trimmed
+}
+
+// diffRefMapper directly maps the matched row and column numbers from the
+// old worksheet to the new worksheet, the rows and columns after the padded
+// worksheets are kept.
+type diffRefMapper struct {
+	rows, cols     map[int]int
+	maxRow, maxCol int
+}
+
+// mapRef provides a function to convert the cell or range reference in the
+// old worksheet to the new worksheet, returns an empty string if the rows or
+// columns of the reference have been removed.
+func (m *diffRefMapper) mapRef(ref string) string {
+	cells := strings.Split(ref, ":")
+	for i, cell := range cells {
+		col, row, err := CellNameToCoordinates(strings.Replace(cell, "$", "", -1))
+		if err != nil {
+			return ""
+		}
+		newCol, colOK := m.cols[col]
+		if col > m.maxCol {
+			newCol, colOK = col, true
+		}
+		newRow, rowOK := m.rows[row]
+		if row > m.maxRow {
+			newRow, rowOK = row, true
+		}
+		if !colOK || !rowOK {
+			return ""
+		}
+		if cells[i], err = CoordinatesToCellName(newCol, newRow); err != nil {
+			return ""
+		}
+	}
+	return strings.Join(cells, ":")
+}
+
+// diffMergeCells provides a function to compare the merged cells of the
+// worksheet by given worksheet name and the reference mapper.
+func (d *differ) diffMergeCells(sheet string, m *diffRefMapper) ([]MergeCellDiff, error) {
+	oldMergeCells, err := d.files[0].GetMergeCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+	newMergeCells, err := d.files[1].GetMergeCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []MergeCellDiff
+	mapped := make(map[string]bool)
+	for _, mergeCell := range oldMergeCells {
+		mapped[m.mapRef(mergeCell[0])] = true
+	}
+	refs := make(map[string]bool)
+	for _, mergeCell := range newMergeCells {
+		refs[mergeCell[0]] = true
+	}
+	for _, mergeCell := range oldMergeCells {
+		if !refs[m.mapRef(mergeCell[0])] {
+			diffs = append(diffs, MergeCellDiff{Type: DiffTypeRemoved, Ref: mergeCell[0]})
+		}
+	}
+	for _, mergeCell := range newMergeCells {
+		if !mapped[mergeCell[0]] {
+			diffs = append(diffs, MergeCellDiff{Type: DiffTypeAdded, Ref: mergeCell[0]})
+		}
+	}
+	return diffs, nil
+}
+
+// diffTables provides a function to compare the tables of the worksheet by
+// given worksheet name and the reference mapper.
+func (d *differ) diffTables(sheet string, m *diffRefMapper) ([]TableDiff, error) {
+	var tables [2][]*xlsxTable
+	for i, f := range d.files {
+		ws, err := f.workSheetReadOnly(sheet)
+		if err != nil {
+			return nil, err
+		}
+		if tables[i], err = f.getSheetTables(f.sheetMap[trimSheetName(sheet)], ws); err != nil {
+			return nil, err
+		}
+	}
+	var diffs []TableDiff
+	newTables := make(map[string]*xlsxTable)
+	for _, table := range tables[1] {
+		newTables[strings.ToLower(table.Name)] = table
+	}
+	oldTables := make(map[string]bool)
+	for _, table := range tables[0] {
+		oldTables[strings.ToLower(table.Name)] = true
+		newTable, ok := newTables[strings.ToLower(table.Name)]
+		if !ok {
+			diffs = append(diffs, TableDiff{Type: DiffTypeRemoved, Name: table.Name, OldRef: table.Ref})
+			continue
+		}
+		if m.mapRef(table.Ref) != newTable.Ref {
+			diffs = append(diffs, TableDiff{Type: DiffTypeModified, Name: table.Name, OldRef: table.Ref, NewRef: newTable.Ref})
+		}
+	}
+	for _, table := range tables[1] {
+		if !oldTables[strings.ToLower(table.Name)] {
+			diffs = append(diffs, TableDiff{Type: DiffTypeAdded, Name: table.Name, NewRef: table.Ref})
+		}
+	}
+	return diffs, nil
+}
+
+// diffComments provides a function to compare the comments of the
+// worksheet by given comments in the old and new worksheets and the
+// reference mapper.
+func diffComments(oldComments, newComments []Comment, m *diffRefMapper) []CommentDiff {
+	var diffs []CommentDiff
+	newRefs := make(map[string]Comment)
+	for _, comment := range newComments {
+		newRefs[comment.Ref] = comment
+	}
+	matched := make(map[string]bool)
+	for _, comment := range oldComments {
+		newComment, ok := newRefs[m.mapRef(comment.Ref)]
+		if !ok {
+			diffs = append(diffs, CommentDiff{Type: DiffTypeRemoved, OldRef: comment.Ref, OldAuthor: comment.Author, OldText: comment.Text})
+			continue
+		}
+		matched[newComment.Ref] = true
+		if comment.Author != newComment.Author || comment.Text != newComment.Text {
+			diffs = append(diffs, CommentDiff{
+				Type: DiffTypeModified, OldRef: comment.Ref, NewRef: newComment.Ref,
+				OldAuthor: comment.Author, NewAuthor: newComment.Author,
+				OldText: comment.Text, NewText: newComment.Text,
+			})
+		}
+	}
+	for _, comment := range newComments {
+		if !matched[comment.Ref] {
+			diffs = append(diffs, CommentDiff{Type: DiffTypeAdded, NewRef: comment.Ref, NewAuthor: comment.Author, NewText: comment.Text})
+		}
+	}
+	return diffs
+}
+
+// diffDefinedNames provides a function to compare the defined names by
+// given defined names of the old and new workbooks.
+func diffDefinedNames(oldNames, newNames []DefinedName) []DefinedNameDiff {
+	var diffs []DefinedNameDiff
+	key := func(dn DefinedName) string { return dn.Scope + "!" + strings.ToLower(dn.Name) }
+	newKeys := make(map[string]DefinedName)
+	for _, dn := range newNames {
+		newKeys[key(dn)] = dn
+	}
+	oldKeys := make(map[string]bool)
+	for _, dn := range oldNames {
+		oldKeys[key(dn)] = true
+		newName, ok := newKeys[key(dn)]
+		if !ok {
+			diffs = append(diffs, DefinedNameDiff{Type: DiffTypeRemoved, Name: dn.Name, Scope: dn.Scope, OldRefersTo: dn.RefersTo})
+			continue
+		}
+		if dn.RefersTo != newName.RefersTo {
+			diffs = append(diffs, DefinedNameDiff{Type: DiffTypeModified, Name: dn.Name, Scope: dn.Scope, OldRefersTo: dn.RefersTo, NewRefersTo: newName.RefersTo})
+		}
+	}
+	for _, dn := range newNames {
+		if !oldKeys[key(dn)] {
+			diffs = append(diffs, DefinedNameDiff{Type: DiffTypeAdded, Name: dn.Name, Scope: dn.Scope, NewRefersTo: dn.RefersTo})
+		}
+	}
+	return diffs
+}
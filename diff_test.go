@@ -0,0 +1,184 @@
+package excelize
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := NewFile()
+	for axis, value := range map[string]interface{}{
+		"A1": "Name", "B1": "Qty", "C1": "Price",
+		"A2": "apple", "B2": 1, "C2": 2,
+		"A3": "pear", "B3": 3, "C3": 4,
+		"A4": "total",
+	} {
+		assert.NoError(t, a.SetCellValue("Sheet1", axis, value))
+	}
+	assert.NoError(t, a.SetCellFormula("Sheet1", "B4", "SUM(B2:B3)"))
+	assert.NoError(t, a.MergeCell("Sheet1", "E1", "F1"))
+	assert.NoError(t, a.AddTable("Sheet1", "A1", "C3", `{"table_name":"Table1"}`))
+	assert.NoError(t, a.AddComment("Sheet1", "A2", `{"author":"Excelize","text":"note"}`))
+	assert.NoError(t, a.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$B$2"}))
+	assert.NoError(t, a.SetDefinedName(&DefinedName{Name: "Old", RefersTo: "Sheet1!$A$1"}))
+	a.NewSheet("Sheet2")
+
+	// Insert the row 3 and the column B, and change the other content.
+	b := NewFile()
+	for axis, value := range map[string]interface{}{
+		"A1": "Name", "B1": "Code", "C1": "Qty", "D1": "Price",
+		"A2": "apple", "B2": "a1", "C2": 1, "D2": 2,
+		"A3": "banana", "B3": "b1", "C3": 5, "D3": 6,
+		"A4": "pear", "B4": "p1", "C4": 30, "D4": 4,
+		"A5": "total",
+	} {
+		assert.NoError(t, b.SetCellValue("Sheet1", axis, value))
+	}
+	assert.NoError(t, b.SetCellFormula("Sheet1", "C5", "SUM(C2:C4)"))
+	style, err := b.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, b.SetCellStyle("Sheet1", "C2", "C2", style))
+	assert.NoError(t, b.MergeCell("Sheet1", "F1", "G1"))
+	assert.NoError(t, b.MergeCell("Sheet1", "A7", "B7"))
+	assert.NoError(t, b.AddTable("Sheet1", "A1", "D5", `{"table_name":"Table1"}`))
+	assert.NoError(t, b.AddTable("Sheet1", "I1", "J2", `{"table_name":"Table2"}`))
+	assert.NoError(t, b.AddComment("Sheet1", "A2", `{"author":"Excelize","text":"changed"}`))
+	assert.NoError(t, b.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$C$2"}))
+	assert.NoError(t, b.SetDefinedName(&DefinedName{Name: "New", RefersTo: "Sheet1!$A$1"}))
+	b.NewSheet("Sheet3")
+
+	diff, err := Diff(a, b)
+	assert.NoError(t, err)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []string{"Sheet3"}, diff.AddedSheets)
+	assert.Equal(t, []string{"Sheet2"}, diff.RemovedSheets)
+	assert.Equal(t, []DefinedNameDiff{
+		{Type: DiffTypeModified, Name: "Amount", Scope: "Workbook", OldRefersTo: "Sheet1!$B$2", NewRefersTo: "Sheet1!$C$2"},
+		{Type: DiffTypeRemoved, Name: "Old", Scope: "Workbook", OldRefersTo: "Sheet1!$A$1"},
+		{Type: DiffTypeAdded, Name: "New", Scope: "Workbook", NewRefersTo: "Sheet1!$A$1"},
+	}, diff.DefinedNames)
+	if !assert.Len(t, diff.Sheets, 1) {
+		t.FailNow()
+	}
+	sheet := diff.Sheets[0]
+	assert.Equal(t, "Sheet1", sheet.Sheet)
+	assert.Equal(t, []int{3}, sheet.InsertedRows)
+	assert.Empty(t, sheet.RemovedRows)
+	assert.Equal(t, []int{2}, sheet.InsertedCols)
+	assert.Empty(t, sheet.RemovedCols)
+	assert.Equal(t, []CellDiff{
+		{Type: DiffTypeAdded, OldAxis: "H1", NewAxis: "I1", NewValue: "Column1"},
+		{Type: DiffTypeAdded, OldAxis: "I1", NewAxis: "J1", NewValue: "Column2"},
+		{Type: DiffTypeModified, OldAxis: "B2", NewAxis: "C2", OldValue: "1", NewValue: "1", NewStyle: style, StyleChanged: true},
+		{Type: DiffTypeModified, OldAxis: "B3", NewAxis: "C4", OldValue: "3", NewValue: "30"},
+		{Type: DiffTypeModified, OldAxis: "B4", NewAxis: "C5", OldFormula: "SUM(B2:B3)", NewFormula: "SUM(C2:C4)"},
+	}, sheet.Cells)
+	assert.Equal(t, []MergeCellDiff{{Type: DiffTypeAdded, Ref: "A7:B7"}}, sheet.MergeCells)
+	assert.Equal(t, []TableDiff{
+		{Type: DiffTypeModified, Name: "Table1", OldRef: "A1:C3", NewRef: "A1:D5"},
+		{Type: DiffTypeAdded, Name: "Table2", NewRef: "I1:J2"},
+	}, sheet.Tables)
+	assert.Equal(t, []CommentDiff{
+		{Type: DiffTypeModified, OldRef: "A2", NewRef: "A2", OldAuthor: "Excelize", NewAuthor: "Excelize", OldText: "Excelizenote", NewText: "Excelizechanged"},
+	}, sheet.Comments)
+	assert.Equal(t, `Sheet3: sheet added
+Sheet2: sheet removed
+Amount: defined name modified, refers to "Sheet1!$B$2" -> "Sheet1!$C$2"
+Old: defined name removed, refers to "Sheet1!$A$1" -> ""
+New: defined name added, refers to "" -> "Sheet1!$A$1"
+Sheet1: row 3 inserted
+Sheet1: column B inserted
+Sheet1!H1 -> I1: cell added, value "" -> "Column1"
+Sheet1!I1 -> J1: cell added, value "" -> "Column2"
+Sheet1!B2 -> C2: cell modified, style 0 -> 1
+Sheet1!B3 -> C4: cell modified, value "3" -> "30"
+Sheet1!B4 -> C5: cell modified, formula "SUM(B2:B3)" -> "SUM(C2:C4)"
+Sheet1!A7:B7: merged cells added
+Sheet1!A1:C3 -> A1:D5: table Table1 modified, range A1:C3 -> A1:D5
+Sheet1!I1:J2: table Table2 added
+Sheet1!A2: comment modified, text "Excelizenote" -> "Excelizechanged"`, diff.String())
+
+	// Test diff the removed rows and columns, the appended row and the removed
+	// cell.
+	diff, err = Diff(b, a)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3}, diff.Sheets[0].RemovedRows)
+	assert.Equal(t, []int{2}, diff.Sheets[0].RemovedCols)
+	assert.NoError(t, a.SetCellValue("Sheet1", "A6", "added"))
+	assert.NoError(t, b.SetCellValue("Sheet1", "E2", "removed"))
+	diff, err = Diff(b, a)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6}, diff.Sheets[0].InsertedRows)
+	assert.Contains(t, diff.Sheets[0].Cells, CellDiff{Type: DiffTypeRemoved, OldAxis: "E2", NewAxis: "D2", OldValue: "removed"})
+}
+
+func TestDiffShiftedFormulas(t *testing.T) {
+	// Test diff the large worksheet with an inserted row, the relative
+	// references of the formulas below the inserted row are shifted.
+	a, b := NewFile(), NewFile()
+	for row := 1; row <= 3000; row++ {
+		newRow := row
+		if row > 1500 {
+			newRow++
+		}
+		for _, c := range []struct {
+			f   *File
+			row int
+		}{{a, row}, {b, newRow}} {
+			assert.NoError(t, c.f.SetCellValue("Sheet1", "A"+strconv.Itoa(c.row), row))
+			assert.NoError(t, c.f.SetCellFormula("Sheet1", "B"+strconv.Itoa(c.row), "A"+strconv.Itoa(c.row)+"*2"))
+		}
+	}
+	assert.NoError(t, b.SetCellValue("Sheet1", "A1501", "inserted"))
+	diff, err := Diff(a, b)
+	assert.NoError(t, err)
+	if !assert.Len(t, diff.Sheets, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, []int{1501}, diff.Sheets[0].InsertedRows)
+	assert.Empty(t, diff.Sheets[0].RemovedRows)
+	assert.Empty(t, diff.Sheets[0].Cells)
+
+	// Test diff the formula with the changed relative reference.
+	assert.NoError(t, b.SetCellFormula("Sheet1", "B1502", "A1501*2"))
+	diff, err = Diff(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, []CellDiff{
+		{Type: DiffTypeModified, OldAxis: "B1501", NewAxis: "B1502", OldFormula: "A1501*2", NewFormula: "A1501*2"},
+	}, diff.Sheets[0].Cells)
+}
+
+func TestDiffEqual(t *testing.T) {
+	a, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	b, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	diff, err := Diff(a, b)
+	assert.NoError(t, err)
+	assert.True(t, diff.Empty())
+	assert.Equal(t, "", diff.String())
+
+	// Test diff the same style with different style indexes.
+	a, b = NewFile(), NewFile()
+	_, err = a.NewStyle(&Style{Font: &Font{Italic: true}})
+	assert.NoError(t, err)
+	styleA, err := a.NewStyle(&Style{Font: &Font{Bold: true}, NumFmt: 14})
+	assert.NoError(t, err)
+	styleB, err := b.NewStyle(&Style{Font: &Font{Bold: true}, NumFmt: 14})
+	assert.NoError(t, err)
+	assert.NotEqual(t, styleA, styleB)
+	assert.NoError(t, a.SetCellStyle("Sheet1", "A1", "A1", styleA))
+	assert.NoError(t, b.SetCellStyle("Sheet1", "A1", "A1", styleB))
+	diff, err = Diff(a, b)
+	assert.NoError(t, err)
+	assert.True(t, diff.Empty())
+
+	// Test diff with the invalid worksheet.
+	b.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A"`)
+	delete(b.Sheet, "xl/worksheets/sheet1.xml")
+	_, err = Diff(a, b)
+	assert.Error(t, err)
+}
@@ -11,7 +11,28 @@
 
 package excelize
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnzipSizeLimit defined the error message on the total uncompressed
+	// size of the spreadsheet package exceeds the limit.
+	ErrUnzipSizeLimit = errors.New("unzip size exceeds the limit")
+	// ErrUnzipPartSizeLimit defined the error message on the uncompressed
+	// size of a part in the spreadsheet package exceeds the limit.
+	ErrUnzipPartSizeLimit = errors.New("unzip part size exceeds the limit")
+	// ErrMaxParts defined the error message on the number of the parts in
+	// the spreadsheet package exceeds the limit.
+	ErrMaxParts = errors.New("the number of parts exceeds the limit")
+	// ErrMaxCells defined the error message on the number of cells in a
+	// worksheet exceeds the limit.
+	ErrMaxCells = errors.New("the number of cells exceeds the limit")
+	// ErrMacroFreeFormat defined the error message on saving the spreadsheet
+	// which contains the macros with the macro-free file extension.
+	ErrMacroFreeFormat = errors.New("macros can't be saved in the macro-free file format")
+)
 
 func newInvalidColumnNameError(col string) error {
 	return fmt.Errorf("invalid column name %q", col)
@@ -25,6 +46,46 @@ func newInvalidCellNameError(cell string) error {
 	return fmt.Errorf("invalid cell name %q", cell)
 }
 
+func newInvalidRangeError(rng string) error {
+	return fmt.Errorf("invalid cell range %q", rng)
+}
+
+func newInvalidCellTimeError(cell string) error {
+	return fmt.Errorf("cell %s is not a date or time value", cell)
+}
+
 func newInvalidExcelDateError(dateValue float64) error {
 	return fmt.Errorf("invalid date value %f, negative values are not supported supported", dateValue)
 }
+
+func newCalcMaxCellsError(max int) error {
+	return fmt.Errorf("exceeds the maximum number of visited cells %d", max)
+}
+
+func newCalcMaxDepthError(max int) error {
+	return fmt.Errorf("exceeds the maximum calculation depth %d", max)
+}
+
+func newCalcMaxChainLengthError(max int) error {
+	return fmt.Errorf("exceeds the maximum length of the dependency chain %d", max)
+}
+
+func newUnzipSizeLimitError(limit int64) error {
+	return fmt.Errorf("%w %d bytes", ErrUnzipSizeLimit, limit)
+}
+
+func newUnzipPartSizeLimitError(part string, limit int64) error {
+	return fmt.Errorf("%w %d bytes: %s", ErrUnzipPartSizeLimit, limit, part)
+}
+
+func newMaxPartsError(parts, limit int) error {
+	return fmt.Errorf("%w %d: %d parts", ErrMaxParts, limit, parts)
+}
+
+func newMaxCellsError(part string, limit int) error {
+	return fmt.Errorf("%w %d: %s", ErrMaxCells, limit, part)
+}
+
+func newMacroFreeFormatError(ext string) error {
+	return fmt.Errorf("%w %s", ErrMacroFreeFormat, ext)
+}
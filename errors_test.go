@@ -23,3 +23,7 @@ func TestNewInvalidCellNameError(t *testing.T) {
 func TestNewInvalidExcelDateError(t *testing.T) {
 	assert.EqualError(t, newInvalidExcelDateError(-1), "invalid date value -1.000000, negative values are not supported supported")
 }
+
+func TestNewInvalidRangeError(t *testing.T) {
+	assert.EqualError(t, newInvalidRangeError("A1:B2:C3"), "invalid cell range \"A1:B2:C3\"")
+}
@@ -14,6 +14,7 @@ package excelize
 
 import (
 	"archive/zip"
+	"bufio"
 	"bytes"
 	"encoding/xml"
 	"errors"
@@ -25,11 +26,17 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/net/html/charset"
 )
 
-// File define a populated spreadsheet file struct.
+// File define a populated spreadsheet file struct. The workbook, worksheets,
+// styles and shared strings of the opened spreadsheet which have only been
+// read will be copied from the source spreadsheet package when saving. The
+// structures of these parts will be serialized after they have been modified
+// by the functions of the File, or been replaced in the WorkBook, Sheet,
+// Styles and SharedStrings fields.
 type File struct {
 	sync.Mutex
 	options          *Options
@@ -53,13 +60,109 @@ type File struct {
 	Relationships    map[string]*xlsxRelationships
 	XLSX             map[string][]byte
 	CharsetReader    charsetTranscoderFn
+	externalLinks    map[string]*xlsxExternalLink
+	externalBooks    map[string]*File
+	externalResolver externalLinkResolverFn
+	formulaRefStyle  string
+	zipParts         map[string]*zip.File
+	tempFiles        map[string]string
+	unzipSizeLimit   int64
+	maxCells         int
+	packageCloser    io.Closer
+	sstIndex         *sharedStringsIndex
+	xlsbParts        map[string]*xlsbSheetPart
+	unknownElements  map[string][]unknownElement
+	unmodified       map[string]interface{}
+	unmodifiedLock   sync.Mutex
+	partsLock        sync.Mutex
+	fieldStyles      map[string]int
 }
 
 type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, err error)
 
-// Options define the options for open spreadsheet.
+type externalLinkResolverFn func(book, sheet, cell string) (value string, err error)
+
+// Options define the options for open and save spreadsheet.
+//
+// Password specifies the password of the spreadsheet in plain text.
+//
+// LazyLoad specifies if the OpenFile keep the spreadsheet file open and
+// inflate the parts of the spreadsheet package on first access, instead of
+// loading the whole package into memory. The File.Close should be called to
+// close the file.
+//
+// UnzipXMLSizeLimit specifies the memory limit on unzipping worksheet in
+// bytes, worksheet XML will be extracted to the system temporary directory
+// when the file size is over this value. The File.Close should be called to
+// remove the temporary files.
+//
+// Strict specifies if save the spreadsheet with the namespaces of the Strict
+// Open XML (ISO/IEC 29500 Strict). The spreadsheet with the Strict
+// namespaces will always be read as the Transitional spreadsheet.
+//
+// CompressionLevel specifies the level of the deflate compression when
+// saving the spreadsheet, range from flate.HuffmanOnly to
+// flate.BestCompression. The default compression level will be used if the
+// value is zero.
+//
+// Reproducible specifies if save the spreadsheet with byte-identical output
+// for identical content. The parts will be written in a stable order
+// following the content types declarations, and the zip entries will be
+// written with fixed timestamps.
+//
+// CreatedTime and ModifiedTime specifies the created and modified time of
+// the document core properties when saving the spreadsheet. The ModifiedTime
+// will also be used as the timestamp of the zip entries with the
+// Reproducible option.
+//
+// UnzipSizeLimit and UnzipPartSizeLimit specifies the limits of the total
+// uncompressed size of the spreadsheet package and the uncompressed size of
+// each part in bytes. MaxParts specifies the limit of the number of the parts
+// in the spreadsheet package. MaxCellsPerSheet specifies the limit of the
+// number of cells in each worksheet. The spreadsheet which exceeds the limits
+// will be rejected with the ErrUnzipSizeLimit, ErrUnzipPartSizeLimit,
+// ErrMaxParts and ErrMaxCells errors, and the limits will not be checked if
+// the value is zero.
 type Options struct {
-	Password string
+	Password           string
+	LazyLoad           bool
+	UnzipXMLSizeLimit  int64
+	Strict             bool
+	CompressionLevel   int
+	Reproducible       bool
+	CreatedTime        time.Time
+	ModifiedTime       time.Time
+	UnzipSizeLimit     int64
+	UnzipPartSizeLimit int64
+	MaxParts           int
+	MaxCellsPerSheet   int
+}
+
+// parseOptions provides a function to get the last options by given options
+// list, returns the default options if the list is empty.
+func parseOptions(opts ...Options) Options {
+	var options Options
+	for _, o := range opts {
+		options = o
+	}
+	return options
+}
+
+// ReadOptions defined the options for reading the cell values.
+// RawCellValue specifies if get the raw value of the cells without applying
+// the number format.
+type ReadOptions struct {
+	RawCellValue bool
+}
+
+// parseReadOptions provides a function to get the options for reading the
+// cell values, the last one will be used if multiple options are given.
+func parseReadOptions(opts ...ReadOptions) ReadOptions {
+	var options ReadOptions
+	for _, o := range opts {
+		options = o
+	}
+	return options
 }
 
 // OpenFile take the name of an spreadsheet file and returns a populated spreadsheet file struct
@@ -70,17 +173,52 @@ type Options struct {
 //        return
 //    }
 //
+// Open a large spreadsheet without loading the entire package into memory,
+// and extract the worksheets larger than 16 MB into temporary files:
+//
+//    f, err := excelize.OpenFile("Book1.xlsx", excelize.Options{LazyLoad: true, UnzipXMLSizeLimit: 16 << 20})
+//    if err != nil {
+//        return
+//    }
+//    defer f.Close()
+//
+// The OpenDocument Spreadsheet (.ods) file will be detected by the mimetype
+// of the package and converted to the spreadsheet. The Excel 97-2003 (.xls)
+// workbook will be detected by the workbook stream of the compound file and
+// converted to the spreadsheet for reading. The Excel binary workbook (.xlsb)
+// will be detected by the binary workbook part of the package and converted
+// to the spreadsheet for reading, the binary worksheets will be converted on
+// first access, and could be streamed by the rows iterator with the LazyLoad
+// option.
 func OpenFile(filename string, opt ...Options) (*File, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-	f, err := OpenReader(file, opt...)
+	var lazy bool
+	for _, o := range opt {
+		lazy = o.LazyLoad
+	}
+	if !lazy {
+		defer file.Close()
+		f, err := OpenReader(file, opt...)
+		if err != nil {
+			return nil, err
+		}
+		f.Path = filename
+		return f, nil
+	}
+	stat, err := file.Stat()
 	if err != nil {
+		file.Close()
 		return nil, err
 	}
-	f.Path = filename
+	f, err := OpenReaderAt(file, stat.Size(), opt...)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	f.Path, f.packageCloser = filename, file
 	return f, nil
 }
 
@@ -98,6 +236,11 @@ func newFile() *File {
 		VMLDrawing:       make(map[string]*vmlDrawing),
 		Relationships:    make(map[string]*xlsxRelationships),
 		CharsetReader:    charset.NewReaderLabel,
+		externalLinks:    make(map[string]*xlsxExternalLink),
+		externalBooks:    make(map[string]*File),
+		zipParts:         make(map[string]*zip.File),
+		tempFiles:        make(map[string]string),
+		unknownElements:  make(map[string][]unknownElement),
 	}
 }
 
@@ -108,8 +251,57 @@ func OpenReader(r io.Reader, opt ...Options) (*File, error) {
 	if err != nil {
 		return nil, err
 	}
+	f, err := openReaderAt(bytes.NewReader(b), int64(len(b)), opt...)
+	if err != nil {
+		return nil, err
+	}
+	if err = f.loadParts(); err != nil {
+		return nil, err
+	}
+	return f, f.initFile()
+}
+
+// OpenReaderAt read data from io.ReaderAt by given size of the data, and
+// return a populated spreadsheet file. The parts of the spreadsheet package
+// will be inflated from the reader on first access, so the reader should be
+// kept available until the file has been saved or isn't needed any more.
+func OpenReaderAt(r io.ReaderAt, size int64, opt ...Options) (*File, error) {
+	f, err := openReaderAt(r, size, opt...)
+	if err != nil {
+		return nil, err
+	}
+	return f, f.initFile()
+}
+
+// openReaderAt provides a function to read the part list of the spreadsheet
+// package from io.ReaderAt by given size of the data and options, the
+// encrypted spreadsheet will be decrypted in memory.
+func openReaderAt(r io.ReaderAt, size int64, opt ...Options) (*File, error) {
 	f := newFile()
-	if bytes.Contains(b, oleIdentifier) {
+	for _, o := range opt {
+		f.unzipSizeLimit, f.maxCells = o.UnzipXMLSizeLimit, o.MaxCellsPerSheet
+	}
+	header := make([]byte, len(oleIdentifier))
+	if _, err := r.ReadAt(header, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if bytes.Equal(header, oleIdentifier) {
+		b, err := ioutil.ReadAll(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, err
+		}
+		stream, err := getXLSWorkbookStream(b)
+		if err != nil {
+			return nil, err
+		}
+		if stream != nil {
+			xls, err := openXLS(stream, parseOptions(opt...))
+			if err != nil {
+				return nil, err
+			}
+			xls.unzipSizeLimit, xls.maxCells, xls.options = f.unzipSizeLimit, f.maxCells, f.options
+			return xls, nil
+		}
 		for _, o := range opt {
 			f.options = &o
 		}
@@ -117,22 +309,44 @@ func OpenReader(r io.Reader, opt ...Options) (*File, error) {
 		if err != nil {
 			return nil, fmt.Errorf("decrypted file failed")
 		}
+		r, size = bytes.NewReader(b), int64(len(b))
 	}
-	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	zr, err := zip.NewReader(r, size)
 	if err != nil {
 		return nil, err
 	}
-
-	file, sheetCount, err := ReadZipReader(zr)
-	if err != nil {
+	if err = checkZipReader(zr, parseOptions(opt...)); err != nil {
 		return nil, err
 	}
-	f.SheetCount, f.XLSX = sheetCount, file
+	if isODSPackage(zr) {
+		ods, err := openODS(zr, parseOptions(opt...))
+		if err != nil {
+			return nil, err
+		}
+		ods.unzipSizeLimit, ods.maxCells, ods.options = f.unzipSizeLimit, f.maxCells, f.options
+		return ods, nil
+	}
+	if isXLSBPackage(zr) {
+		xlsb, err := openXLSB(zr, parseOptions(opt...))
+		if err != nil {
+			return nil, err
+		}
+		xlsb.unzipSizeLimit, xlsb.maxCells, xlsb.options = f.unzipSizeLimit, f.maxCells, f.options
+		return xlsb, nil
+	}
+	f.XLSX, f.SheetCount = f.readZipReaderLazy(zr)
+	f.unmodified = make(map[string]interface{})
+	return f, nil
+}
+
+// initFile provides a function to read the workbook level parts after the
+// spreadsheet package has been opened.
+func (f *File) initFile() error {
 	f.CalcChain = f.calcChainReader()
 	f.sheetMap = f.getSheetMap()
-	f.Styles = f.stylesReader()
+	f.Styles = f.stylesReadOnly()
 	f.Theme = f.themeReader()
-	return f, nil
+	return nil
 }
 
 // CharsetTranscoder Set user defined codepage transcoder function for open
@@ -162,8 +376,26 @@ func (f *File) setDefaultTimeStyle(sheet, axis string, format int) error {
 }
 
 // workSheetReader provides a function to get the pointer to the structure
-// after deserialization by given worksheet name.
-func (f *File) workSheetReader(sheet string) (xlsx *xlsxWorksheet, err error) {
+// after deserialization by given worksheet name. The worksheet will be
+// serialized when saving the spreadsheet.
+func (f *File) workSheetReader(sheet string) (*xlsxWorksheet, error) {
+	return f.readWorkSheet(sheet, true)
+}
+
+// workSheetReadOnly provides a function to get the pointer to the structure
+// after deserialization by given worksheet name for reading only. The
+// worksheet which has not been modified will be copied from the source
+// spreadsheet package when saving. The worksheet which has been extracted to
+// the temporary file is decoded from the file on each call and will not be
+// kept in memory.
+func (f *File) workSheetReadOnly(sheet string) (*xlsxWorksheet, error) {
+	return f.readWorkSheet(sheet, false)
+}
+
+// readWorkSheet provides a function to get the pointer to the structure
+// after deserialization by given worksheet name, and mark the worksheet as
+// modified if the modify is true.
+func (f *File) readWorkSheet(sheet string, modify bool) (xlsx *xlsxWorksheet, err error) {
 	f.Lock()
 	defer f.Unlock()
 	var (
@@ -175,38 +407,99 @@ func (f *File) workSheetReader(sheet string) (xlsx *xlsxWorksheet, err error) {
 		err = fmt.Errorf("sheet %s is not exist", sheet)
 		return
 	}
-	if xlsx = f.Sheet[name]; f.Sheet[name] == nil {
-		if strings.HasPrefix(name, "xl/chartsheets") {
-			err = fmt.Errorf("sheet %s is chart sheet", sheet)
-			return
+	if xlsx = f.Sheet[name]; xlsx != nil {
+		if modify {
+			f.setModified(name)
 		}
-		xlsx = new(xlsxWorksheet)
-		if _, ok := f.xmlAttr[name]; !ok {
-			d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(name))))
-			f.xmlAttr[name] = append(f.xmlAttr[name], getRootElement(d)...)
-		}
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(name)))).
-			Decode(xlsx); err != nil && err != io.EOF {
-			err = fmt.Errorf("xml decode error: %s", err)
+		return
+	}
+	if strings.HasPrefix(name, "xl/chartsheets") {
+		err = fmt.Errorf("sheet %s is chart sheet", sheet)
+		return
+	}
+	if !modify {
+		if xlsx, ok, err = f.decodeSpilledWorkSheet(name); ok || err != nil {
 			return
 		}
-		err = nil
-		if f.checked == nil {
-			f.checked = make(map[string]bool)
+	}
+	var content []byte
+	if content, err = f.readBytes(name); err != nil {
+		return
+	}
+	if xlsx, err = f.decodeWorkSheet(name, content); err != nil {
+		err = fmt.Errorf("xml decode error: %s", err)
+		return
+	}
+	if f.checked == nil {
+		f.checked = make(map[string]bool)
+	}
+	if ok = f.checked[name]; !ok {
+		if err = f.checkCellsLimit(name, xlsx); err != nil {
+			return
 		}
-		if ok = f.checked[name]; !ok {
-			checkSheet(xlsx)
-			if err = checkRow(xlsx); err != nil {
-				return
-			}
-			f.checked[name] = true
+		checkSheet(xlsx)
+		if err = checkRow(xlsx); err != nil {
+			return
 		}
-		f.Sheet[name] = xlsx
+		f.checked[name] = true
+	}
+	f.Sheet[name] = xlsx
+	if !modify {
+		f.setUnmodified(name, xlsx)
 	}
-
 	return
 }
 
+// decodeWorkSheet provides a function to deserialize the worksheet by given
+// part name and content of the part. The namespaces of the root element and
+// the unknown elements of the worksheet will be kept for serializing, the
+// caller should hold the lock of the File.
+func (f *File) decodeWorkSheet(name string, content []byte) (*xlsxWorksheet, error) {
+	content = namespaceStrictToTransitional(content)
+	xlsx := new(xlsxWorksheet)
+	if _, ok := f.xmlAttr[name]; !ok {
+		d := f.xmlNewDecoder(bytes.NewReader(content))
+		f.xmlAttr[name] = addNamespacePrefixes(append(f.xmlAttr[name], getRootElement(d)...))
+	}
+	if err := f.xmlNewDecoder(bytes.NewReader(content)).
+		Decode(xlsx); err != nil && err != io.EOF {
+		return xlsx, err
+	}
+	f.setUnknownElements(name, content, xlsx.AnyElements)
+	return xlsx, nil
+}
+
+// decodeSpilledWorkSheet provides a function to decode the worksheet which
+// has been extracted to the temporary file by given part name. The worksheet
+// is decoded from the file directly, so reading the large worksheet doesn't
+// load the whole part into memory. It returns false if the worksheet hasn't
+// been extracted, or it uses the Strict namespaces which should be converted
+// in memory.
+func (f *File) decodeSpilledWorkSheet(name string) (*xlsxWorksheet, bool, error) {
+	path, ok, err := f.spilledPart(name)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	fi, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer fi.Close()
+	r := bufio.NewReaderSize(fi, 16<<10)
+	if head, _ := r.Peek(16 << 10); bytes.Contains(head, []byte(strictNamespacePrefix)) {
+		return nil, false, nil
+	}
+	xlsx := new(xlsxWorksheet)
+	if err = f.xmlNewDecoder(r).Decode(xlsx); err != nil && err != io.EOF {
+		return nil, false, fmt.Errorf("xml decode error: %s", err)
+	}
+	if err = f.checkCellsLimit(name, xlsx); err != nil {
+		return nil, false, err
+	}
+	checkSheet(xlsx)
+	return xlsx, true, checkRow(xlsx)
+}
+
 // checkSheet provides a function to fill each row element and make that is
 // continuous in a worksheet of XML.
 func checkSheet(xlsx *xlsxWorksheet) {
@@ -236,6 +529,28 @@ func checkSheet(xlsx *xlsxWorksheet) {
 	xlsx.SheetData = sheetData
 }
 
+// checkCellsLimit provides a function to check the number of cells in the
+// worksheet by the MaxCellsPerSheet of the options. The cells which will be
+// filled before the last cell of each row are also counted.
+func (f *File) checkCellsLimit(name string, xlsx *xlsxWorksheet) error {
+	if f.maxCells <= 0 {
+		return nil
+	}
+	var cells int
+	for _, r := range xlsx.SheetData.Row {
+		n := len(r.C)
+		if n > 0 && r.C[n-1].R != "" {
+			if col, _, err := CellNameToCoordinates(r.C[n-1].R); err == nil && col > n {
+				n = col
+			}
+		}
+		if cells += n; cells > f.maxCells {
+			return newMaxCellsError(name, f.maxCells)
+		}
+	}
+	return nil
+}
+
 // addRels provides a function to add relationships by given XML path,
 // relationship type, target and target mode.
 func (f *File) addRels(relPath, relType, target, targetMode string) int {
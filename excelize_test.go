@@ -1,9 +1,11 @@
 package excelize
 
 import (
+	"archive/zip"
 	"bytes"
 	"compress/gzip"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"image/color"
 	_ "image/gif"
@@ -61,6 +63,9 @@ func TestOpenFile(t *testing.T) {
 	assert.EqualError(t, f.SetCellInt("Sheet3", "A23", 10), "sheet Sheet3 is not exist")
 	assert.EqualError(t, f.SetCellStr("Sheet3", "b230", "10"), "sheet Sheet3 is not exist")
 	assert.EqualError(t, f.SetCellStr("Sheet10", "b230", "10"), "sheet Sheet10 is not exist")
+	// Test set worksheet name with the unchanged name.
+	f.SetSheetName("Sheet2", "Sheet2")
+	assert.NoError(t, f.SetCellStr("Sheet2", "C11", "Knowns"))
 
 	// Test set cell string value with illegal row number.
 	assert.EqualError(t, f.SetCellStr("Sheet1", "A", "10"), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
@@ -247,6 +252,160 @@ func TestOpenReader(t *testing.T) {
 	assert.EqualError(t, err, "zip: unsupported compression algorithm")
 }
 
+func TestOpenFileLazyLoad(t *testing.T) {
+	expected, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	expectedRows, err := expected.GetRows("Sheet2")
+	assert.NoError(t, err)
+
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"), Options{LazyLoad: true, UnzipXMLSizeLimit: 2048})
+	assert.NoError(t, err)
+	assert.Nil(t, f.XLSX["xl/worksheets/sheet2.xml"])
+	assert.NotNil(t, f.XLSX["xl/workbook.xml"])
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRows, rows)
+	assert.Len(t, f.tempFiles, 0)
+	// Test the worksheet which size exceeds the limit has been extracted to a temporary file.
+	val, err := f.GetCellValue("Sheet2", "C2")
+	assert.NoError(t, err)
+	assert.Equal(t, "HP", val)
+	assert.Nil(t, f.XLSX["xl/worksheets/sheet2.xml"])
+	assert.Len(t, f.tempFiles, 1)
+	tempFile := f.tempFiles["xl/worksheets/sheet2.xml"]
+	_, err = os.Stat(tempFile)
+	assert.NoError(t, err)
+	// Test the worksheet extracted to the temporary file is not kept in memory.
+	assert.Nil(t, f.Sheet["xl/worksheets/sheet2.xml"])
+	_, ok := f.unmodified["xl/worksheets/sheet2.xml"]
+	assert.False(t, ok)
+	val, err = f.GetCellValue("Sheet1", "B19")
+	assert.NoError(t, err)
+	assert.Equal(t, "237", val)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestOpenFileLazyLoad.xlsx")))
+	assert.NoError(t, f.Close())
+	_, err = os.Stat(tempFile)
+	assert.True(t, os.IsNotExist(err))
+	assert.Len(t, f.tempFiles, 0)
+
+	f, err = OpenFile(filepath.Join("test", "TestOpenFileLazyLoad.xlsx"))
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRows, rows)
+
+	// Test save lazy loaded spreadsheet with origin path.
+	f, err = OpenFile(filepath.Join("test", "TestOpenFileLazyLoad.xlsx"), Options{LazyLoad: true})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "lazy"))
+	assert.NoError(t, f.Save())
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestOpenFileLazyLoad.xlsx"))
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRows, rows)
+	val, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "lazy", val)
+
+	// Test open spreadsheet from io.ReaderAt.
+	b, err := ioutil.ReadFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	f, err = OpenReaderAt(bytes.NewReader(b), int64(len(b)))
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRows, rows)
+	assert.NoError(t, f.Close())
+	_, err = OpenReaderAt(bytes.NewReader(oleIdentifier), int64(len(oleIdentifier)))
+	assert.EqualError(t, err, "decrypted file failed")
+	_, err = OpenReaderAt(strings.NewReader(""), 0)
+	assert.EqualError(t, err, "zip: not a valid zip file")
+
+	// Test read the worksheet with the removed temporary file.
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"), Options{LazyLoad: true, UnzipXMLSizeLimit: 2048})
+	assert.NoError(t, err)
+	_, err = f.GetCellValue("Sheet2", "C2")
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove(f.tempFiles["xl/worksheets/sheet2.xml"]))
+	_, err = f.GetCellValue("Sheet2", "C2")
+	assert.True(t, os.IsNotExist(err))
+	assert.True(t, os.IsNotExist(f.SetCellValue("Sheet2", "C2", "HP")))
+	_, err = f.Cols("Sheet2")
+	assert.True(t, os.IsNotExist(err))
+	assert.True(t, os.IsNotExist(f.Close()))
+
+	// Test open not exist spreadsheet with lazy load.
+	_, err = OpenFile(filepath.Join("test", "NotExist.xlsx"), Options{LazyLoad: true})
+	assert.Error(t, err)
+}
+
+func TestOpenReaderLimits(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 10; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(row), &[]interface{}{1, 2, 3}))
+	}
+	// Add a highly compressed part to simulate the zip bomb.
+	f.XLSX["xl/media/bomb.bin"] = make([]byte, 1<<20)
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	b := buf.Bytes()
+
+	_, err = OpenReader(bytes.NewReader(b), Options{MaxParts: 5})
+	assert.True(t, errors.Is(err, ErrMaxParts))
+	assert.EqualError(t, err, "the number of parts exceeds the limit 5: 10 parts")
+	_, err = OpenReader(bytes.NewReader(b), Options{UnzipPartSizeLimit: 1 << 19})
+	assert.True(t, errors.Is(err, ErrUnzipPartSizeLimit))
+	assert.EqualError(t, err, "unzip part size exceeds the limit 524288 bytes: xl/media/bomb.bin")
+	_, err = OpenReader(bytes.NewReader(b), Options{UnzipSizeLimit: 1 << 20})
+	assert.True(t, errors.Is(err, ErrUnzipSizeLimit))
+	_, err = OpenReaderAt(bytes.NewReader(b), int64(len(b)), Options{UnzipSizeLimit: 1 << 20})
+	assert.True(t, errors.Is(err, ErrUnzipSizeLimit))
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	assert.NoError(t, err)
+	_, _, err = ReadZipReader(zr, Options{MaxParts: 5})
+	assert.True(t, errors.Is(err, ErrMaxParts))
+	_, err = OpenReader(bytes.NewReader(b), Options{MaxParts: 10, UnzipPartSizeLimit: 1 << 20, UnzipSizeLimit: 2 << 20})
+	assert.NoError(t, err)
+
+	// Test the number of cells in the worksheet exceeds the limit.
+	f, err = OpenReader(bytes.NewReader(b), Options{MaxCellsPerSheet: 29})
+	assert.NoError(t, err)
+	_, err = f.GetCellValue("Sheet1", "A1")
+	assert.True(t, errors.Is(err, ErrMaxCells))
+	assert.EqualError(t, err, "the number of cells exceeds the limit 29: xl/worksheets/sheet1.xml")
+	f, err = OpenReaderAt(bytes.NewReader(b), int64(len(b)), Options{MaxCellsPerSheet: 29})
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet1")
+	assert.True(t, errors.Is(err, ErrMaxCells))
+	assert.Len(t, rows, 9)
+	f, err = OpenReader(bytes.NewReader(b), Options{MaxCellsPerSheet: 30})
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 10)
+
+	// Test the sparse cell references are counted as filled cells.
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "XFD1", 1))
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(buf, Options{MaxCellsPerSheet: 1000})
+	assert.NoError(t, err)
+	_, err = f.GetCellValue("Sheet1", "A1")
+	assert.True(t, errors.Is(err, ErrMaxCells))
+
+	// Test the inflated part exceeds the declared uncompressed size.
+	zr, err = zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	assert.NoError(t, err)
+	for _, zf := range zr.File {
+		zf.UncompressedSize64 = 16
+	}
+	_, _, err = ReadZipReader(zr)
+	assert.EqualError(t, err, "zip: not a valid zip file")
+}
+
 func TestBrokenFile(t *testing.T) {
 	// Test write file with broken file struct.
 	f := File{}
@@ -0,0 +1,251 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RegisterExternalWorkbook provides a function to register the workbook
+// which referenced by the formulas of this workbook with the given external
+// link name. The name is the file name of the external workbook in the
+// formula, or the target of the external link in the workbook. After
+// registered, the formula calculation will read values from the registered
+// workbook instead of the cached values stored in the workbook. For example,
+// register the opened Budget.xlsx for the formula ='[Budget.xlsx]Sheet1'!A1
+// or [1]Sheet1!A1 which link to it:
+//
+//    budget, err := excelize.OpenFile("Budget.xlsx")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    f.RegisterExternalWorkbook("Budget.xlsx", budget)
+//    result, err := f.CalcCellValue("Sheet1", "A1")
+//
+// Register a nil workbook to remove the registered workbook with the given
+// name.
+//
+func (f *File) RegisterExternalWorkbook(name string, wb *File) {
+	if f.externalBooks == nil {
+		f.externalBooks = make(map[string]*File)
+	}
+	if wb == nil {
+		delete(f.externalBooks, name)
+		return
+	}
+	f.externalBooks[name] = wb
+}
+
+// ExternalLinkResolver set user defined function to resolve the cell value
+// of the external workbook by given external workbook name, worksheet name
+// and cell coordinates when the formula calculation references a workbook
+// that has not been registered by RegisterExternalWorkbook. For example:
+//
+//    f.ExternalLinkResolver(func(book, sheet, cell string) (string, error) {
+//        return db.QueryCellValue(book, sheet, cell)
+//    })
+//
+func (f *File) ExternalLinkResolver(fn externalLinkResolverFn) *File {
+	f.externalResolver = fn
+	return f
+}
+
+// externalLinkReader provides a function to get the pointer to the structure
+// after deserialization of xl/externalLinks/externalLink%d.xml.
+func (f *File) externalLinkReader(path string) *xlsxExternalLink {
+	f.Lock()
+	defer f.Unlock()
+	var err error
+	if f.externalLinks == nil {
+		f.externalLinks = make(map[string]*xlsxExternalLink)
+	}
+	if f.externalLinks[path] == nil {
+		if _, ok := f.XLSX[path]; !ok {
+			return nil
+		}
+		content := f.readXML(path)
+		f.externalLinks[path] = new(xlsxExternalLink)
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+			Decode(f.externalLinks[path]); err != nil && err != io.EOF {
+			log.Printf("xml decode error: %s", err)
+		}
+	}
+	return f.externalLinks[path]
+}
+
+// getExternalLinks provides a function to get the part path and the target
+// of the external workbook in order of the external references of the
+// workbook.
+func (f *File) getExternalLinks() (paths, targets []string) {
+	wb := f.workbookReadOnly()
+	rels := f.relsReader("xl/_rels/workbook.xml.rels")
+	if wb.ExternalReferences == nil || rels == nil {
+		return
+	}
+	for _, ref := range wb.ExternalReferences.ExternalReference {
+		var linkPath, target string
+		for _, rel := range rels.Relationships {
+			if rel.ID == ref.RID {
+				linkPath = "xl/" + strings.TrimPrefix(strings.TrimPrefix(rel.Target, "/"), "xl/")
+			}
+		}
+		if link := f.externalLinkReader(linkPath); link != nil && link.ExternalBook != nil {
+			linkRels := f.relsReader(path.Dir(linkPath) + "/_rels/" + path.Base(linkPath) + ".rels")
+			if linkRels != nil {
+				for _, rel := range linkRels.Relationships {
+					if rel.ID == link.ExternalBook.RID {
+						target = rel.Target
+					}
+				}
+			}
+		}
+		paths, targets = append(paths, linkPath), append(targets, target)
+	}
+	return
+}
+
+// getExternalBookNames provides a function to get the names of the
+// registered external workbooks in order of the external links by given
+// targets of the external links, the names which don't match any target will
+// be sorted by the name after them.
+func (f *File) getExternalBookNames(targets []string) []string {
+	names := make([]string, 0, len(f.externalBooks))
+	for name := range f.externalBooks {
+		names = append(names, name)
+	}
+	order := func(name string) int {
+		for i, target := range targets {
+			if matchExternalBook(name, target) {
+				return i
+			}
+		}
+		return len(targets)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if oi, oj := order(names[i]), order(names[j]); oi != oj {
+			return oi < oj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// matchExternalBook provides a function to check if the external workbook
+// name in the formula matches the given external link target.
+func matchExternalBook(name, target string) bool {
+	base := func(s string) string {
+		return path.Base(strings.Replace(s, "\\", "/", -1))
+	}
+	return name == target || strings.EqualFold(base(name), base(target))
+}
+
+// splitExternalSheetName provides a function to split the external workbook
+// name and the worksheet name by given sheet name of the reference, such as
+// [Budget.xlsx]Sheet1 or [1]Sheet1. The ok returns false if the reference
+// doesn't point to an external workbook.
+func splitExternalSheetName(sheet string) (book, name string, ok bool) {
+	start, end := strings.Index(sheet, "["), strings.Index(sheet, "]")
+	if start == -1 || end < start {
+		return
+	}
+	return sheet[:start] + sheet[start+1:end], sheet[end+1:], true
+}
+
+// getExternalCellValue provides a function to get the cell value of the
+// external workbook by given external workbook name or index, worksheet name
+// and cell coordinates. The value will be read from the registered workbook
+// first, then the user defined external link resolver, and fall back to the
+// cached value stored in the external link part.
+func (f *File) getExternalCellValue(book, sheet, cell string) (string, error) {
+	paths, targets := f.getExternalLinks()
+	idx := -1
+	if i, err := strconv.Atoi(book); err == nil {
+		if i < 1 || i > len(targets) {
+			return "", fmt.Errorf("external link %s is not exist", book)
+		}
+		idx, book = i-1, targets[i-1]
+	}
+	if wb, ok := f.externalBooks[book]; ok {
+		return wb.GetCellValue(sheet, cell)
+	}
+	for _, name := range f.getExternalBookNames(targets) {
+		if matchExternalBook(name, book) {
+			return f.externalBooks[name].GetCellValue(sheet, cell)
+		}
+	}
+	if f.externalResolver != nil {
+		return f.externalResolver(book, sheet, cell)
+	}
+	for i := 0; idx == -1 && i < len(targets); i++ {
+		if matchExternalBook(book, targets[i]) {
+			idx = i
+		}
+	}
+	if idx == -1 {
+		return "", fmt.Errorf("external link %s is not exist", book)
+	}
+	return f.getExternalCachedValue(paths[idx], sheet, cell)
+}
+
+// getExternalCachedValue provides a function to get the cached cell value of
+// the external workbook by given external link part path, worksheet name and
+// cell coordinates.
+func (f *File) getExternalCachedValue(linkPath, sheet, cell string) (string, error) {
+	link := f.externalLinkReader(linkPath)
+	if link == nil || link.ExternalBook == nil || link.ExternalBook.SheetNames == nil {
+		return "", fmt.Errorf("sheet %s is not exist", sheet)
+	}
+	sheetID := -1
+	for i, name := range link.ExternalBook.SheetNames.SheetName {
+		if strings.EqualFold(name.Val, sheet) {
+			sheetID = i
+		}
+	}
+	if sheetID == -1 {
+		return "", fmt.Errorf("sheet %s is not exist", sheet)
+	}
+	if link.ExternalBook.SheetDataSet == nil {
+		return "", nil
+	}
+	for _, sheetData := range link.ExternalBook.SheetDataSet.SheetData {
+		if sheetData.SheetID != sheetID {
+			continue
+		}
+		for _, row := range sheetData.Row {
+			for _, c := range row.Cell {
+				if c.R == cell {
+					return c.V, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// getRefCellValue provides a function to get the cell value which referenced
+// by the formula by given worksheet name and cell coordinates. The worksheet
+// name may include the external workbook name or index, such as
+// [Budget.xlsx]Sheet1 or [1]Sheet1.
+func (f *File) getRefCellValue(sheet, cell string) (string, error) {
+	if book, name, ok := splitExternalSheetName(sheet); ok {
+		return f.getExternalCellValue(book, name, cell)
+	}
+	return f.GetCellValue(sheet, cell)
+}
@@ -0,0 +1,112 @@
+package excelize
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func prepareExternalLinkTestBook() *File {
+	f := NewFile()
+	f.WorkBook.ExternalReferences = &xlsxExternalReferences{
+		ExternalReference: []xlsxExternalReference{{RID: "rId9"}},
+	}
+	f.Relationships["xl/_rels/workbook.xml.rels"].Relationships = append(
+		f.Relationships["xl/_rels/workbook.xml.rels"].Relationships,
+		xlsxRelationship{ID: "rId9", Target: "externalLinks/externalLink1.xml"})
+	f.XLSX["xl/externalLinks/externalLink1.xml"] = []byte(`<externalLink xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><externalBook r:id="rId1"><sheetNames><sheetName val="Sheet1"/><sheetName val="Data"/></sheetNames><sheetDataSet><sheetData sheetId="0"/><sheetData sheetId="1"><row r="1"><cell r="A1"><v>100</v></cell><cell r="B1" t="str"><v>text</v></cell></row><row r="2"><cell r="A2"><v>200</v></cell></row></sheetData></sheetDataSet></externalBook></externalLink>`)
+	f.XLSX["xl/externalLinks/_rels/externalLink1.xml.rels"] = []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/externalLinkPath" Target="file:///C:\Reports\Budget.xlsx" TargetMode="External"/></Relationships>`)
+	return f
+}
+
+func TestExternalLinkCachedValue(t *testing.T) {
+	f := prepareExternalLinkTestBook()
+	for formula, expected := range map[string]string{
+		"=[1]Data!A1":               "100",
+		"='[1]Data'!$A$2*2":         "400",
+		"=SUM([1]Data!A1:A2)":       "300",
+		"='[Budget.xlsx]Data'!A1+1": "101",
+		"=[1]Data!C3":               "",
+		"=[1]Sheet1!A1":             "",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A1", formula))
+		result, err := f.CalcCellValue("Sheet1", "A1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	value, err := f.getRefCellValue("[budget.xlsx]Data", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "text", value)
+	// Test get cell value with invalid external link.
+	for sheet, expected := range map[string]string{
+		"[2]Data":           "external link 2 is not exist",
+		"[Other.xlsx]Data":  "external link Other.xlsx is not exist",
+		"[1]SheetN":         "sheet SheetN is not exist",
+		"[Budget.xlsx]Data": "",
+	} {
+		_, err := f.getRefCellValue(sheet, "A1")
+		if expected == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.EqualError(t, err, expected)
+	}
+	// Test get cell value without the external references.
+	_, err = NewFile().getRefCellValue("[1]Sheet1", "A1")
+	assert.EqualError(t, err, "external link 1 is not exist")
+}
+
+func TestRegisterExternalWorkbook(t *testing.T) {
+	f := prepareExternalLinkTestBook()
+	budget := NewFile()
+	budget.NewSheet("Data")
+	assert.NoError(t, budget.SetCellValue("Data", "A1", 50))
+	assert.NoError(t, budget.SetCellValue("Data", "A2", 60))
+	f.RegisterExternalWorkbook("Budget.xlsx", budget)
+	for _, formula := range []string{"=[1]Data!A1+[1]Data!A2", "=SUM('[Budget.xlsx]Data'!A1:A2)"} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "A1", formula))
+		result, err := f.CalcCellValue("Sheet1", "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, "110", result, formula)
+	}
+	// Test resolve the external workbook with full path.
+	f.RegisterExternalWorkbook("Budget.xlsx", nil)
+	f.RegisterExternalWorkbook(`file:///C:\Reports\Budget.xlsx`, budget)
+	value, err := f.getRefCellValue("[1]Data", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "60", value)
+	_, err = f.getRefCellValue("[1]SheetN", "A2")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+
+	// Test resolve the external workbook in order of the external links.
+	other := NewFile()
+	assert.NoError(t, other.SetCellValue("Sheet1", "A1", "other"))
+	f.RegisterExternalWorkbook(`file:///C:\Reports\Budget.xlsx`, nil)
+	f.RegisterExternalWorkbook(`D:\Budget.xlsx`, budget)
+	f.RegisterExternalWorkbook(`E:\Budget.xlsx`, other)
+	for i := 0; i < 10; i++ {
+		value, err = f.getRefCellValue("[1]Data", "A2")
+		assert.NoError(t, err)
+		assert.Equal(t, "60", value)
+	}
+	f.externalBooks = map[string]*File{"A.xlsx": nil, "B.xlsx": nil, "C.xlsx": nil}
+	assert.Equal(t, []string{"B.xlsx", "A.xlsx", "C.xlsx"}, f.getExternalBookNames([]string{"x/B.xlsx", "y/A.xlsx"}))
+
+	// Test resolve the external workbook by user defined resolver.
+	f = prepareExternalLinkTestBook()
+	f.ExternalLinkResolver(func(book, sheet, cell string) (string, error) {
+		if sheet != "Data" {
+			return "", errors.New("unknown sheet")
+		}
+		return book + "/" + cell, nil
+	})
+	value, err = f.getRefCellValue("[Other.xlsx]Data", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Other.xlsx/B2", value)
+	value, err = f.getRefCellValue("[1]Data", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, `file:///C:\Reports\Budget.xlsx/B2`, value)
+	_, err = f.getRefCellValue("[1]Sheet1", "B2")
+	assert.EqualError(t, err, "unknown sheet")
+}
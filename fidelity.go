@@ -0,0 +1,240 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+)
+
+// xlsxAnyAttrs directly maps the attributes of the element which have not
+// been modeled by the structure, such as the x14ac:dyDescent of the row. The
+// attributes will be kept with the qualified names and written back
+// unchanged.
+type xlsxAnyAttrs []xml.Attr
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface to keep the
+// attribute with the qualified name. The namespace of the attribute will be
+// resolved to the prefix declared in the element or the well-known prefix,
+// the attribute will be kept with the namespace if the prefix is unknown.
+func (attrs *xlsxAnyAttrs) UnmarshalXMLAttr(attr xml.Attr) error {
+	switch attr.Name.Space {
+	case "":
+		if attr.Name.Local == "xmlns" {
+			return nil
+		}
+	case "xmlns":
+		attr.Name = xml.Name{Local: "xmlns:" + attr.Name.Local}
+	default:
+		prefix := namespacePrefixes[attr.Name.Space]
+		for _, a := range *attrs {
+			if strings.HasPrefix(a.Name.Local, "xmlns:") && a.Value == attr.Name.Space {
+				prefix = strings.TrimPrefix(a.Name.Local, "xmlns:")
+			}
+		}
+		if prefix != "" {
+			attr.Name = xml.Name{Local: prefix + ":" + attr.Name.Local}
+		}
+	}
+	*attrs = append(*attrs, attr)
+	return nil
+}
+
+// xlsxAnyElement directly maps the child element of the part root element
+// which has not been modeled by the structure, such as the
+// mc:AlternateContent and xr:revisionPtr of the workbook. The raw XML of the
+// element is kept by the File and will be spliced into the serialized part,
+// so the element itself will not be marshalled.
+type xlsxAnyElement struct {
+	XMLName xml.Name
+}
+
+// MarshalXML implements the xml.Marshaler interface, nothing will be written
+// for the element.
+func (xlsxAnyElement) MarshalXML(*xml.Encoder, xml.StartElement) error { return nil }
+
+// unknownElement defined the raw XML of the root child element which has not
+// been modeled, and the names of the preceding modeled sibling elements
+// which are used to locate the element when serializing the part.
+type unknownElement struct {
+	prev []string
+	raw  []byte
+}
+
+// namespacePrefixes defined the well-known prefixes of the namespaces which
+// declared by the template of the root element.
+var namespacePrefixes = func() map[string]string {
+	prefixes := map[string]string{NameSpaceXML: "xml"}
+	d := xml.NewDecoder(strings.NewReader("<root" + templateNamespaceIDMap))
+	if token, _ := d.RawToken(); token != nil {
+		for _, attr := range token.(xml.StartElement).Attr {
+			if attr.Name.Space == "xmlns" {
+				prefixes[attr.Value] = attr.Name.Local
+			}
+		}
+	}
+	return prefixes
+}()
+
+// addNamespacePrefixes provides a function to declare the well-known prefix
+// for the namespace which has been declared with a different prefix in the
+// root element, so that the attributes kept by the xlsxAnyAttrs with the
+// well-known prefixes could be resolved.
+func addNamespacePrefixes(attrs []xml.Attr) []xml.Attr {
+	declared := make(map[string]bool)
+	for _, attr := range attrs {
+		if attr.Name.Space == "xmlns" {
+			declared[attr.Name.Local] = true
+		}
+	}
+	for _, attr := range attrs {
+		if attr.Name.Space != "xmlns" {
+			continue
+		}
+		if prefix, ok := namespacePrefixes[attr.Value]; ok && prefix != "xml" && !declared[prefix] {
+			declared[prefix] = true
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Space: "xmlns", Local: prefix}, Value: attr.Value})
+		}
+	}
+	return attrs
+}
+
+// setUnknownElements provides a function to keep the raw XML of the root
+// child elements which have not been modeled by given part name, part
+// content and the elements decoded by the xlsxAnyElement in the document
+// order.
+func (f *File) setUnknownElements(name string, content []byte, elements []xlsxAnyElement) {
+	if len(elements) == 0 {
+		delete(f.unknownElements, name)
+		return
+	}
+	var (
+		unknown []unknownElement
+		prev    []string
+		depth   int
+		d       = f.xmlNewDecoder(bytes.NewReader(content))
+	)
+	for {
+		start := d.InputOffset()
+		token, err := d.Token()
+		if err != nil {
+			break
+		}
+		switch el := token.(type) {
+		case xml.StartElement:
+			if depth++; depth != 2 {
+				continue
+			}
+			if err = d.Skip(); err != nil {
+				return
+			}
+			depth--
+			if len(unknown) < len(elements) && el.Name == elements[len(unknown)].XMLName {
+				unknown = append(unknown, unknownElement{
+					prev: append([]string(nil), prev...),
+					raw:  append([]byte(nil), content[start:d.InputOffset()]...),
+				})
+				continue
+			}
+			prev = append(prev, el.Name.Local)
+		case xml.EndElement:
+			depth--
+		}
+	}
+	f.unknownElements[name] = unknown
+}
+
+// spliceUnknownElements provides a function to write back the root child
+// elements which have not been modeled into the serialized part by given
+// part name and content. Each element will be placed after the nearest
+// preceding sibling element which still exists in the part, or at the
+// beginning of the root element.
+func (f *File) spliceUnknownElements(name string, content []byte) []byte {
+	unknown := f.unknownElements[name]
+	if len(unknown) == 0 {
+		return content
+	}
+	type child struct {
+		name string
+		end  int64
+	}
+	var (
+		children []child
+		rootEnd  int64
+		depth    int
+		d        = xml.NewDecoder(bytes.NewReader(content))
+	)
+	for {
+		token, err := d.Token()
+		if err != nil {
+			if err != io.EOF {
+				return content
+			}
+			break
+		}
+		switch el := token.(type) {
+		case xml.StartElement:
+			if depth++; depth == 1 {
+				rootEnd = d.InputOffset()
+				continue
+			}
+			if err = d.Skip(); err != nil {
+				return content
+			}
+			depth--
+			children = append(children, child{name: el.Name.Local, end: d.InputOffset()})
+		case xml.EndElement:
+			depth--
+		}
+	}
+	offsets := make([]int64, len(unknown))
+	for i, el := range unknown {
+		offsets[i] = rootEnd
+	find:
+		for j := len(el.prev) - 1; j >= 0; j-- {
+			var nth, last int
+			for _, name := range el.prev[:j+1] {
+				if name == el.prev[j] {
+					nth++
+				}
+			}
+			for _, c := range children {
+				if c.name != el.prev[j] {
+					continue
+				}
+				if offsets[i], last = c.end, last+1; last == nth {
+					break find
+				}
+			}
+			if last > 0 {
+				break
+			}
+		}
+	}
+	idx := make([]int, len(unknown))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return offsets[idx[i]] < offsets[idx[j]] })
+	var buf bytes.Buffer
+	var pos int64
+	for _, i := range idx {
+		buf.Write(content[pos:offsets[i]])
+		buf.Write(unknown[i].raw)
+		pos = offsets[i]
+	}
+	buf.Write(content[pos:])
+	return buf.Bytes()
+}
@@ -0,0 +1,224 @@
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTripCorpus defined the parts which contain the elements and
+// attributes not modeled by the structures, each fragment should be kept
+// unchanged in the part after the round-trip.
+var roundTripCorpus = []struct {
+	name      string
+	parts     map[string]string
+	fragments map[string][]string
+}{
+	{
+		name: "WorksheetExtensions",
+		parts: map[string]string{
+			"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" mc:Ignorable="x14ac xr" xmlns:x14ac="http://schemas.microsoft.com/office/spreadsheetml/2009/9/ac" xmlns:xr="http://schemas.microsoft.com/office/spreadsheetml/2014/revision" xr:uid="{00000000-0001-0000-0000-000000000000}"><dimension ref="A1:B1"/><sheetViews><sheetView tabSelected="1" workbookViewId="0"/></sheetViews><sheetFormatPr defaultRowHeight="15" x14ac:dyDescent="0.25"/><sheetData><row r="1" spans="1:2" x14ac:dyDescent="0.25"><c r="A1" cm="1"><f t="array" ref="A1" aca="false">1</f><v>1</v></c><c r="B1" t="e" vm="1"><v>#VALUE!</v></c></row></sheetData><conditionalFormatting sqref="A1"><cfRule type="dataBar" priority="1"><dataBar><cfvo type="min"/><cfvo type="max"/><color rgb="FF638EC6"/></dataBar><extLst><ext uri="{B025F937-C7B1-47D3-B67F-A62EFF666E3E}" xmlns:x14="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"><x14:id>{1}</x14:id></ext></extLst></cfRule></conditionalFormatting><pageMargins left="0.7" right="0.7" top="0.75" bottom="0.75" header="0.3" footer="0.3"/><mc:AlternateContent><mc:Choice Requires="x14"><x:unknown xmlns:x="urn:unknown"/></mc:Choice></mc:AlternateContent><extLst><ext uri="{78C0D931-6437-407d-A8EE-F0AAD7539E65}" xmlns:x14="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"><x14:conditionalFormattings><x14:conditionalFormatting xmlns:xm="http://schemas.microsoft.com/office/excel/2006/main"><x14:cfRule type="dataBar" id="{1}"><x14:dataBar minLength="0" maxLength="100"/></x14:cfRule><xm:sqref>A1</xm:sqref></x14:conditionalFormatting></x14:conditionalFormattings></ext><ext uri="{A8765BA9-456A-4dab-B4F3-ACF838C121DE}" xmlns:x14="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"><x14:slicerList><x14:slicer r:id="rId1"/></x14:slicerList></ext></extLst></worksheet>`,
+		},
+		fragments: map[string][]string{
+			"xl/worksheets/sheet1.xml": {
+				`xr:uid="{00000000-0001-0000-0000-000000000000}"`,
+				`x14ac:dyDescent="0.25"></sheetFormatPr>`,
+				`<row r="1" spans="1:2" x14ac:dyDescent="0.25">`,
+				`<c r="A1" cm="1"><f t="array" ref="A1" aca="false">1</f>`,
+				`vm="1"`,
+				`</pageMargins><mc:AlternateContent><mc:Choice Requires="x14"><x:unknown xmlns:x="urn:unknown"/></mc:Choice></mc:AlternateContent><extLst>`,
+				`<x14:id>{1}</x14:id>`,
+				`<x14:cfRule type="dataBar" id="{1}"><x14:dataBar minLength="0" maxLength="100"/></x14:cfRule><xm:sqref>A1</xm:sqref>`,
+				`<x14:slicerList><x14:slicer r:id="rId1"/></x14:slicerList>`,
+			},
+		},
+	},
+	{
+		name: "WorkbookExtensions",
+		parts: map[string]string{
+			"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" mc:Ignorable="x15 xr xr6 xr10 xr2" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main" xmlns:xr="http://schemas.microsoft.com/office/spreadsheetml/2014/revision" xmlns:xr6="http://schemas.microsoft.com/office/spreadsheetml/2016/revision6" xmlns:xr10="http://schemas.microsoft.com/office/spreadsheetml/2016/revision10" xmlns:xr2="http://schemas.microsoft.com/office/spreadsheetml/2015/revision2"><fileVersion appName="xl" lastEdited="7" lowestEdited="7" rupBuild="22228"/><fileSharing readOnlyRecommended="1"/><workbookPr defaultThemeVersion="166925"/><mc:AlternateContent xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"><mc:Choice Requires="x15"><x15ac:absPath url="C:\Users\" xmlns:x15ac="http://schemas.microsoft.com/office/spreadsheetml/2010/11/ac"/></mc:Choice></mc:AlternateContent><xr:revisionPtr revIDLastSave="0" documentId="13_ncr:1_{00000000-0000-0000-0000-000000000000}" xr6:coauthVersionLast="45" xr6:coauthVersionMax="45" xr10:uidLastSave="{00000000-0000-0000-0000-000000000000}"/><bookViews><workbookView xWindow="-120" yWindow="-120" windowWidth="29040" windowHeight="15840" xr2:uid="{00000000-000D-0000-FFFF-FFFF00000000}"/></bookViews><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets><calcPr calcId="191029"/><extLst><ext uri="{BBE1A952-AA13-448e-AADC-164F8A28A991}" xmlns:x14="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"><x14:slicerCaches><x14:slicerCache r:id="rId5"/></x14:slicerCaches></ext><ext uri="{140A7094-0E35-4892-8432-C4D2E57EDEB5}" xmlns:x15="http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"><x15:workbookPr chartTrackingRefBase="1"/></ext></extLst></workbook>`,
+		},
+		fragments: map[string][]string{
+			"xl/workbook.xml": {
+				`</fileVersion><fileSharing readOnlyRecommended="1"/><workbookPr`,
+				`</workbookPr><mc:AlternateContent xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006"><mc:Choice Requires="x15"><x15ac:absPath url="C:\Users\" xmlns:x15ac="http://schemas.microsoft.com/office/spreadsheetml/2010/11/ac"/></mc:Choice></mc:AlternateContent><xr:revisionPtr revIDLastSave="0" documentId="13_ncr:1_{00000000-0000-0000-0000-000000000000}" xr6:coauthVersionLast="45" xr6:coauthVersionMax="45" xr10:uidLastSave="{00000000-0000-0000-0000-000000000000}"/><bookViews>`,
+				`xr2:uid="{00000000-000D-0000-FFFF-FFFF00000000}"`,
+				`<x14:slicerCaches><x14:slicerCache r:id="rId5"/></x14:slicerCaches>`,
+				`<x15:workbookPr chartTrackingRefBase="1"/>`,
+			},
+		},
+	},
+	{
+		name: "CustomXMLParts",
+		parts: map[string]string{
+			"[Content_Types].xml":              `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/theme/theme1.xml" ContentType="application/vnd.openxmlformats-officedocument.theme+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/><Override PartName="/customXml/itemProps1.xml" ContentType="application/vnd.openxmlformats-officedocument.customXmlProperties+xml"/><Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/><Override PartName="/docProps/app.xml" ContentType="application/vnd.openxmlformats-officedocument.extended-properties+xml"/></Types>`,
+			"xl/_rels/workbook.xml.rels":       `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme" Target="theme/theme1.xml"/><Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId5" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml" Target="../customXml/item1.xml"/><Relationship Id="rId6" Type="http://schemas.example.com/unknown" Target="https://example.com/" TargetMode="External"/></Relationships>`,
+			"customXml/item1.xml":              `<b:Sources SelectedStyle="\APASixthEditionOfficeOnline.xsl" xmlns:b="http://schemas.openxmlformats.org/officeDocument/2006/bibliography" xmlns="http://schemas.openxmlformats.org/officeDocument/2006/bibliography"></b:Sources>`,
+			"customXml/itemProps1.xml":         `<ds:datastoreItem ds:itemID="{00000000-0000-0000-0000-000000000000}" xmlns:ds="http://schemas.openxmlformats.org/officeDocument/2006/customXml"><ds:schemaRefs><ds:schemaRef ds:uri="http://schemas.openxmlformats.org/officeDocument/2006/bibliography"/></ds:schemaRefs></ds:datastoreItem>`,
+			"customXml/_rels/item1.xml.rels":   `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXmlProps" Target="itemProps1.xml"/></Relationships>`,
+			"xl/slicers/slicer1.xml":           `<slicers xmlns="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"><slicer name="Name" cache="Slicer_Name" caption="Name" rowHeight="241300"/></slicers>`,
+			"xl/slicerCaches/slicerCache1.xml": `<slicerCacheDefinition xmlns="http://schemas.microsoft.com/office/spreadsheetml/2009/9/main" name="Slicer_Name" sourceName="Name"/>`,
+		},
+		fragments: map[string][]string{
+			"[Content_Types].xml":        {`PartName="/customXml/itemProps1.xml"`},
+			"xl/_rels/workbook.xml.rels": {`Target="../customXml/item1.xml"`, `Type="http://schemas.example.com/unknown"`, `TargetMode="External"`},
+		},
+	},
+}
+
+func TestRoundTripCorpus(t *testing.T) {
+	for _, c := range roundTripCorpus {
+		t.Run(c.name, func(t *testing.T) {
+			b := buildPackage(t, c.parts)
+			for i := 0; i < 2; i++ {
+				f, err := OpenReader(bytes.NewReader(b))
+				assert.NoError(t, err)
+				assert.NoError(t, f.SetCellValue("Sheet1", "C1", "round-trip"))
+				// Modify the workbook to serialize it with the unknown elements.
+				f.SetActiveSheet(0)
+				assert.Equal(t, "Sheet1", f.GetSheetName(0))
+				buf, err := f.WriteToBuffer()
+				assert.NoError(t, err)
+				b = buf.Bytes()
+			}
+			parts := readZipBytes(t, b)
+			for name, fragments := range c.fragments {
+				for _, fragment := range fragments {
+					assert.Contains(t, string(parts[name]), fragment, name)
+				}
+			}
+			// The parts which have not been modeled should be kept byte-for-byte.
+			for name, content := range c.parts {
+				if _, ok := c.fragments[name]; !ok && !strings.HasPrefix(name, "xl/w") {
+					assert.Equal(t, content, string(parts[name]), name)
+				}
+			}
+			for name, content := range parts {
+				if isXMLPart(name) {
+					assert.NoError(t, xml.Unmarshal(content, new(struct{})), name)
+				}
+			}
+			f, err := OpenReader(bytes.NewReader(b))
+			assert.NoError(t, err)
+			val, err := f.GetCellValue("Sheet1", "C1")
+			assert.NoError(t, err)
+			assert.Equal(t, "round-trip", val)
+		})
+	}
+}
+
+func TestUnknownElements(t *testing.T) {
+	f := NewFile()
+	f.unknownElements["xl/workbook.xml"] = []unknownElement{
+		{prev: []string{"fileVersion", "workbookPr"}, raw: []byte("<a/>")},
+		{prev: []string{"sheets", "sheets"}, raw: []byte("<b/>")},
+		{raw: []byte("<c/>")},
+	}
+	f.WorkBook.FileVersion, f.WorkBook.WorkbookPr, f.WorkBook.CalcPr = nil, nil, nil
+	output, err := xml.Marshal(f.WorkBook)
+	assert.NoError(t, err)
+	// Test place the element after the nearest existing preceding element.
+	assert.Equal(t, `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><a/><c/><bookViews><workbookView windowHeight="8010" windowWidth="14805" xWindow="0" yWindow="0"></workbookView></bookViews><sheets><sheet name="Sheet1" sheetId="1" xmlns:relationships="http://schemas.openxmlformats.org/officeDocument/2006/relationships" relationships:id="rId1"></sheet></sheets><b/></workbook>`,
+		string(f.spliceUnknownElements("xl/workbook.xml", output)))
+	f.WorkBook.FileVersion = &xlsxFileVersion{AppName: "xl"}
+	output, err = xml.Marshal(f.WorkBook)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(f.spliceUnknownElements("xl/workbook.xml", output)),
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><c/><fileVersion appName="xl"></fileVersion><a/><bookViews>`))
+	// Test splice the elements into the invalid XML.
+	assert.Equal(t, "<workbook><", string(f.spliceUnknownElements("xl/workbook.xml", []byte("<workbook><"))))
+
+	// Test the elements will be removed with the worksheet, and be copied with the worksheet.
+	f.unknownElements["xl/worksheets/sheet1.xml"] = []unknownElement{{raw: []byte("<a/>")}}
+	idx := f.NewSheet("Sheet2")
+	assert.NoError(t, f.CopySheet(0, idx))
+	assert.Equal(t, f.unknownElements["xl/worksheets/sheet1.xml"], f.unknownElements["xl/worksheets/sheet2.xml"])
+	f.DeleteSheet("Sheet2")
+	_, ok := f.unknownElements["xl/worksheets/sheet2.xml"]
+	assert.False(t, ok)
+}
+
+func TestAnyAttrs(t *testing.T) {
+	var row xlsxRow
+	assert.NoError(t, xml.Unmarshal([]byte(`<row xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:x14ac="http://schemas.microsoft.com/office/spreadsheetml/2009/9/ac" r="1" x14ac:dyDescent="0.25" xmlns:v="urn:vendor" v:a="1" u:b="2" xmlns:u="urn:unknown" c="3"/>`), &row))
+	assert.Equal(t, xlsxAnyAttrs{
+		{Name: xml.Name{Local: "xmlns:x14ac"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2009/9/ac"},
+		{Name: xml.Name{Local: "x14ac:dyDescent"}, Value: "0.25"},
+		{Name: xml.Name{Local: "xmlns:v"}, Value: "urn:vendor"},
+		{Name: xml.Name{Local: "v:a"}, Value: "1"},
+		{Name: xml.Name{Space: "urn:unknown", Local: "b"}, Value: "2"},
+		{Name: xml.Name{Local: "xmlns:u"}, Value: "urn:unknown"},
+		{Name: xml.Name{Local: "c"}, Value: "3"},
+	}, row.AnyAttrs)
+	output, err := xml.Marshal(row)
+	assert.NoError(t, err)
+	assert.NoError(t, xml.Unmarshal(output, new(struct{})))
+
+	// Test declare the well-known prefix of the namespace at the root element.
+	attrs := addNamespacePrefixes([]xml.Attr{
+		{Name: xml.Name{Space: "xmlns", Local: "ac"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2009/9/ac"},
+		{Name: xml.Name{Space: "xmlns", Local: "xr"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2014/revision"},
+	})
+	assert.Equal(t, xml.Attr{Name: xml.Name{Space: "xmlns", Local: "x14ac"}, Value: "http://schemas.microsoft.com/office/spreadsheetml/2009/9/ac"}, attrs[2])
+	assert.Len(t, attrs, 3)
+}
+
+func TestCellMetadataAttrs(t *testing.T) {
+	f, err := OpenReader(bytes.NewReader(buildPackage(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1" cm="1" ph="1"><f t="array" ref="A1">_xlfn.SEQUENCE(1)</f><v>1</v></c><c r="B1" t="e" vm="1"><v>#VALUE!</v></c><c r="C1" t="e" vm="2"><v>#VALUE!</v></c><c r="D1" t="e" vm="3"><v>#VALUE!</v></c></row></sheetData></worksheet>`,
+	})))
+	assert.NoError(t, err)
+	// Test the metadata attributes will be removed when the value or formula
+	// of the cell is replaced.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "1+1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", "value"))
+	assert.NoError(t, f.SetCellRichText("Sheet1", "C1", []RichTextRun{{Text: "rich"}}))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	sheet := string(readZipBytes(t, buf.Bytes())["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, sheet, `<c r="A1" ph="1"><f t="array" ref="A1">1+1</f>`)
+	assert.Contains(t, sheet, `<c r="B1" t="s"><v>`)
+	assert.Contains(t, sheet, `<c r="C1" t="s"><v>`)
+	assert.Contains(t, sheet, `<c r="D1" t="e" vm="3"><v>#VALUE!</v></c>`)
+	assert.NotContains(t, sheet, `cm="1"`)
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	value, err := f.GetCellValue("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+}
+
+// buildPackage provides a function to build the spreadsheet package with
+// the parts of the new workbook replaced by the given parts.
+func buildPackage(t *testing.T, parts map[string]string) []byte {
+	buf, err := NewFile().WriteToBuffer()
+	assert.NoError(t, err)
+	files := readZipBytes(t, buf.Bytes())
+	for name, content := range parts {
+		files[name] = []byte(content)
+	}
+	buf.Reset()
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		fi, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = fi.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// readZipBytes provides a function to read all parts of the spreadsheet
+// package by given content of the package.
+func readZipBytes(t *testing.T, b []byte) map[string][]byte {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	assert.NoError(t, err)
+	files, _, err := ReadZipReader(zr)
+	assert.NoError(t, err)
+	return files
+}
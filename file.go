@@ -13,11 +13,19 @@ package excelize
 
 import (
 	"archive/zip"
+	"bufio"
 	"bytes"
+	"compress/flate"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 // NewFile provides a function to create new file by default template. For
@@ -54,6 +62,35 @@ func NewFile() *File {
 	return f
 }
 
+// NewFileFromTemplate provides a function to create new file by given path
+// of the template (.xltx or .xltm) file. The template will be converted to
+// the workbook, or the macro-enabled workbook if the template contains the
+// macros, and the path of the template will not be kept, so the new file
+// should be saved by SaveAs. For example:
+//
+//    f, err := excelize.NewFileFromTemplate("Book1.xltx")
+//    if err != nil {
+//        return
+//    }
+//    err = f.SaveAs("Book1.xlsx")
+//
+func NewFileFromTemplate(filename string, opt ...Options) (*File, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	f, err := OpenReader(file, opt...)
+	if err != nil {
+		return nil, err
+	}
+	ext := ".xlsx"
+	if f.hasMacros() {
+		ext = ".xlsm"
+	}
+	return f, f.setWorkbookContentType(ext)
+}
+
 // Save provides a function to override the xlsx file with origin path.
 func (f *File) Save() error {
 	if f.Path == "" {
@@ -63,41 +100,185 @@ func (f *File) Save() error {
 }
 
 // SaveAs provides a function to create or update to an xlsx file at the
-// provided path.
+// provided path. The file will be saved as OpenDocument Spreadsheet if the
+// extension of the path is ".ods". The content type of the workbook will be
+// set by the extension of the path, the workbook will be saved as the
+// macro-enabled workbook, template, macro-enabled template or macro-enabled
+// add-in if the extension is ".xlsm", ".xltx", ".xltm" or ".xlam", and the
+// ErrMacroFreeFormat error will be returned if the workbook contains the
+// macros and the extension is ".xlsx" or ".xltx". The zip entries will be
+// written to the file directly, and the CompressionLevel of the options
+// specifies the level of the deflate compression. Only the workbook,
+// worksheets, styles and shared strings which have been modified will be
+// serialized, the untouched parts will be copied byte-for-byte from the
+// source spreadsheet package. The options only take effect on this call, the
+// same as Write, and the options specified by opening the spreadsheet will be
+// used if no options are given. For example, save the spreadsheet with the
+// best speed compression:
+//
+//    err := f.SaveAs("Book1.xlsx", excelize.Options{CompressionLevel: flate.BestSpeed})
+//
 func (f *File) SaveAs(name string, opt ...Options) error {
 	if len(name) > FileNameLength {
 		return errors.New("file name length exceeds maximum limit")
 	}
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext != ".ods" {
+		if err := f.setWorkbookContentType(ext); err != nil {
+			return err
+		}
+	}
+	if f.packageCloser != nil && filepath.Clean(name) == filepath.Clean(f.Path) {
+		// the opened package will be overwritten
+		if err := f.loadParts(); err != nil {
+			return err
+		}
+	}
 	file, err := os.OpenFile(name, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0666)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
-	for _, o := range opt {
-		f.options = &o
+	defer f.scopeOptions(opt...)()
+	if ext == ".ods" {
+		return f.WriteODS(file)
 	}
 	return f.Write(file)
 }
 
-// Write provides a function to write to an io.Writer.
-func (f *File) Write(w io.Writer) error {
+// Write provides a function to write to an io.Writer. The parts of the
+// spreadsheet package will be streamed to the writer directly. The options
+// could be specified the same as SaveAs, and only take effect on this call,
+// the options specified by opening the spreadsheet will be used if no
+// options are given.
+func (f *File) Write(w io.Writer, opt ...Options) error {
+	defer f.scopeOptions(opt...)()
 	_, err := f.WriteTo(w)
 	return err
 }
 
-// WriteTo implements io.WriterTo to write the file.
+// scopeOptions provides a function to use the last one of the given options
+// for saving the spreadsheet, and returns the function to restore the
+// previous options. The previous options will be kept if no options are
+// given.
+func (f *File) scopeOptions(opt ...Options) func() {
+	options := f.options
+	if len(opt) > 0 {
+		o := parseOptions(opt...)
+		f.options = &o
+	}
+	return func() { f.options = options }
+}
+
+// WriteTo implements io.WriterTo to write the file. The zip entries of the
+// spreadsheet package will be written to the writer without building the
+// whole package in memory. The encrypted spreadsheet will be built in a
+// temporary file before being encrypted.
 func (f *File) WriteTo(w io.Writer) (int64, error) {
-	buf, err := f.WriteToBuffer()
-	if err != nil {
-		return 0, err
+	if f.encrypted() {
+		return f.writeEncrypted(w)
+	}
+	cw := &countWriter{w: w}
+	zw := zip.NewWriter(cw)
+	if err := f.writeToZip(zw); err != nil {
+		zw.Close()
+		return cw.n, err
 	}
-	return buf.WriteTo(w)
+	err := zw.Close()
+	return cw.n, err
 }
 
 // WriteToBuffer provides a function to get bytes.Buffer from the saved file.
+// The whole spreadsheet package will be built in memory, and be encrypted if
+// the Password of the options which specified by opening or saving the
+// spreadsheet is not empty.
 func (f *File) WriteToBuffer() (*bytes.Buffer, error) {
 	buf := new(bytes.Buffer)
 	zw := zip.NewWriter(buf)
+	if err := f.writeToZip(zw); err != nil {
+		zw.Close()
+		return buf, err
+	}
+	if err := zw.Close(); err != nil {
+		return buf, err
+	}
+	if f.encrypted() {
+		b, err := Encrypt(buf.Bytes(), f.options)
+		if err != nil {
+			return buf, err
+		}
+		buf.Reset()
+		buf.Write(b)
+	}
+	return buf, nil
+}
+
+// encrypted provides a function to check if the spreadsheet should be
+// encrypted when saving, only the options with the password will encrypt the
+// spreadsheet, so saving with other options doesn't encrypt it.
+func (f *File) encrypted() bool {
+	return f.options != nil && f.options.Password != ""
+}
+
+// writeEncrypted provides a function to build the spreadsheet package in a
+// temporary file, encrypt the package from the file into another temporary
+// file chunk by chunk, and write the encrypted package to the writer.
+func (f *File) writeEncrypted(w io.Writer) (int64, error) {
+	tmp, err := ioutil.TempFile(os.TempDir(), "excelize-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	zw := zip.NewWriter(tmp)
+	if err = f.writeToZip(zw); err != nil {
+		zw.Close()
+		return 0, err
+	}
+	if err = zw.Close(); err != nil {
+		return 0, err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	encrypted, err := ioutil.TempFile(os.TempDir(), "excelize-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(encrypted.Name())
+	defer encrypted.Close()
+	encryptionInfo, err := encryptPackage(encrypted, bufio.NewReader(tmp), size, f.options)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = encrypted.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	cw := &countWriter{w: w}
+	err = writeCompoundFile(cw, encryptionInfo, bufio.NewReader(encrypted))
+	return cw.n, err
+}
+
+// writeToZip provides a function to serialize the structures of the
+// spreadsheet and write the parts of the spreadsheet package to the zip
+// writer. The deflate compressor will be registered with the
+// CompressionLevel of the options if specified.
+func (f *File) writeToZip(zw *zip.Writer) error {
+	if f.options != nil && f.options.CompressionLevel != 0 {
+		level := f.options.CompressionLevel
+		zw.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(w, level)
+		})
+	}
+	strict := f.options != nil && f.options.Strict
+	f.setConformance(strict)
+	if err := f.setDocPropsTime(); err != nil {
+		return err
+	}
 	f.calcChainWriter()
 	f.commentsWriter()
 	f.contentTypesWriter()
@@ -109,30 +290,267 @@ func (f *File) WriteToBuffer() (*bytes.Buffer, error) {
 	f.sharedStringsWriter()
 	f.styleSheetWriter()
 
-	for path, content := range f.XLSX {
-		fi, err := zw.Create(path)
+	for _, path := range f.getPartsOrder() {
+		content := f.XLSX[path]
+		fi, err := zw.CreateHeader(f.getZipFileHeader(path))
 		if err != nil {
-			zw.Close()
-			return buf, err
+			return err
+		}
+		if strict && isXMLPart(path) {
+			if content, err = f.readBytes(path); err == nil {
+				_, err = fi.Write(namespaceTransitionalToStrict(append([]byte(nil), content...)))
+			}
+		} else if content == nil {
+			err = f.copyPart(fi, path)
+		} else {
+			_, err = fi.Write(content)
 		}
-		_, err = fi.Write(content)
 		if err != nil {
-			zw.Close()
-			return buf, err
+			return err
 		}
 	}
+	return nil
+}
 
-	if f.options != nil {
-		if err := zw.Close(); err != nil {
-			return buf, err
+// getPartsOrder provides a function to get the names of the parts which will
+// be written to the spreadsheet package. The parts will be sorted with the
+// Reproducible option: the content types and the package relationships at
+// first, then the parts in the order of the content types overrides, and the
+// rest of the parts sorted by name.
+func (f *File) getPartsOrder() []string {
+	names := make([]string, 0, len(f.XLSX))
+	for name := range f.XLSX {
+		names = append(names, name)
+	}
+	if f.options == nil || !f.options.Reproducible {
+		return names
+	}
+	rank := map[string]int{"[Content_Types].xml": 0, "_rels/.rels": 1}
+	if f.ContentTypes != nil {
+		for _, override := range f.ContentTypes.Overrides {
+			name := strings.TrimPrefix(override.PartName, "/")
+			if _, ok := rank[name]; !ok {
+				rank[name] = len(rank)
+			}
 		}
-		b, err := Encrypt(buf.Bytes(), f.options)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, oki := rank[names[i]]
+		rj, okj := rank[names[j]]
+		if oki && okj {
+			return ri < rj
+		}
+		if oki != okj {
+			return oki
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// getZipFileHeader provides a function to get the header of the zip entry by
+// given part name. The modified time of the entry will be fixed with the
+// Reproducible option, the ModifiedTime of the options will be used if
+// specified, otherwise the MS-DOS epoch will be used.
+func (f *File) getZipFileHeader(name string) *zip.FileHeader {
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	if f.options != nil && f.options.Reproducible {
+		header.Modified = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+		if !f.options.ModifiedTime.IsZero() {
+			header.Modified = f.options.ModifiedTime.UTC()
+		}
+	}
+	return header
+}
+
+// setDocPropsTime provides a function to set the created and modified time
+// of the document core properties by the CreatedTime and ModifiedTime of the
+// options.
+func (f *File) setDocPropsTime() error {
+	if f.options == nil || (f.options.CreatedTime.IsZero() && f.options.ModifiedTime.IsZero()) {
+		return nil
+	}
+	props := &DocProperties{}
+	if !f.options.CreatedTime.IsZero() {
+		props.Created = f.options.CreatedTime.UTC().Format(time.RFC3339)
+	}
+	if !f.options.ModifiedTime.IsZero() {
+		props.Modified = f.options.ModifiedTime.UTC().Format(time.RFC3339)
+	}
+	return f.SetDocProps(props)
+}
+
+// workbookContentTypes defined the content types of the workbook part by the
+// extension of the spreadsheet file.
+var workbookContentTypes = map[string]string{
+	".xlam": ContentTypeMacroAddIn,
+	".xlsm": ContentTypeMacro,
+	".xlsx": ContentTypeSheetML,
+	".xltm": ContentTypeMacroTemplate,
+	".xltx": ContentTypeTemplate,
+}
+
+// hasMacros provides a function to check if the spreadsheet contains the VBA
+// project or the Excel 4.0 macro sheets.
+func (f *File) hasMacros() bool {
+	for name := range f.XLSX {
+		if strings.HasPrefix(name, "xl/vbaProject") || strings.HasPrefix(name, "xl/macrosheets/") {
+			return true
+		}
+	}
+	return false
+}
+
+// setWorkbookContentType provides a function to set the content type of the
+// workbook part by given extension of the spreadsheet file. The content type
+// will not be changed if the extension is unknown or the spreadsheet has no
+// workbook part.
+func (f *File) setWorkbookContentType(ext string) error {
+	contentType, ok := workbookContentTypes[strings.ToLower(ext)]
+	if !ok {
+		return nil
+	}
+	if (contentType == ContentTypeSheetML || contentType == ContentTypeTemplate) && f.hasMacros() {
+		return newMacroFreeFormatError(ext)
+	}
+	if _, ok = f.XLSX["xl/workbook.xml"]; !ok {
+		return nil
+	}
+	content := f.contentTypesReader()
+	for idx, o := range content.Overrides {
+		if o.PartName == "/xl/workbook.xml" {
+			content.Overrides[idx].ContentType = contentType
+			return nil
+		}
+	}
+	content.Overrides = append(content.Overrides, xlsxOverride{PartName: "/xl/workbook.xml", ContentType: contentType})
+	return nil
+}
+
+// countWriter directly maps the writer which counts the number of bytes
+// written to the underlying writer.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements the io.Writer interface to write to the underlying writer
+// and count the number of bytes written.
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// setUnmodified provides a function to record the structure which has been
+// deserialized from the part of the spreadsheet package by given part name.
+// The part will be copied from the source spreadsheet package instead of
+// serializing the structure when saving, until it has been marked as
+// modified or the structure has been replaced. The structure will not be
+// recorded if the spreadsheet wasn't opened from a spreadsheet package or the
+// part doesn't exist in the package.
+func (f *File) setUnmodified(name string, v interface{}) {
+	if _, ok := f.XLSX[name]; !ok {
+		return
+	}
+	f.unmodifiedLock.Lock()
+	defer f.unmodifiedLock.Unlock()
+	if f.unmodified != nil {
+		f.unmodified[name] = v
+	}
+}
+
+// isUnmodified provides a function to check if the structure of the part by
+// given part name is the one deserialized from the source spreadsheet
+// package and has not been modified.
+func (f *File) isUnmodified(name string, v interface{}) bool {
+	f.unmodifiedLock.Lock()
+	defer f.unmodifiedLock.Unlock()
+	u, ok := f.unmodified[name]
+	return ok && u == v
+}
+
+// setModified provides a function to mark the part as modified by given part
+// name, the structure of the part will be serialized when saving the
+// spreadsheet.
+func (f *File) setModified(name string) {
+	f.unmodifiedLock.Lock()
+	defer f.unmodifiedLock.Unlock()
+	delete(f.unmodified, name)
+}
+
+// setConformance provides a function to set the conformance class attribute
+// of the workbook root element, the attribute will be removed if the
+// spreadsheet will not be saved with the Strict namespaces.
+func (f *File) setConformance(strict bool) {
+	attrs, ok := f.xmlAttr["xl/workbook.xml"]
+	if !ok {
+		return
+	}
+	var root []xml.Attr
+	for _, attr := range attrs {
+		if attr.Name.Space != "" || attr.Name.Local != "conformance" {
+			root = append(root, attr)
+		}
+	}
+	if strict {
+		root = append(root, xml.Attr{Name: xml.Name{Local: "conformance"}, Value: "strict"})
+	}
+	if len(root) != len(attrs) || strict {
+		f.setModified("xl/workbook.xml")
+	}
+	f.xmlAttr["xl/workbook.xml"] = root
+}
+
+// copyPart provides a function to copy the content of the part which has not
+// been loaded into memory to the writer by given part name. The XML part
+// with the Strict namespaces will be converted to the Transitional
+// namespaces.
+func (f *File) copyPart(w io.Writer, name string) error {
+	rc, err := f.openPart(name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	r := bufio.NewReaderSize(rc, 16<<10)
+	if head, _ := r.Peek(16 << 10); isXMLPart(name) && bytes.Contains(head, []byte(strictNamespacePrefix)) {
+		content, err := ioutil.ReadAll(r)
 		if err != nil {
-			return buf, err
+			return err
 		}
-		buf.Reset()
-		buf.Write(b)
-		return buf, nil
+		_, err = w.Write(namespaceStrictToTransitional(content))
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// Close closes the spreadsheet file which opened with the LazyLoad option,
+// and removes the temporary files which extracted from the spreadsheet
+// package and the disk-backed index of the shared strings table. The parts
+// of the spreadsheet package which have not been loaded can't be accessed
+// after closing.
+func (f *File) Close() error {
+	var err error
+	f.partsLock.Lock()
+	for name, path := range f.tempFiles {
+		if e := os.Remove(path); e != nil && err == nil {
+			err = e
+		}
+		delete(f.tempFiles, name)
+	}
+	f.partsLock.Unlock()
+	if f.sstIndex != nil {
+		if e := f.sstIndex.close(); e != nil && err == nil {
+			err = e
+		}
+		f.sstIndex = nil
+	}
+	if f.packageCloser != nil {
+		if e := f.packageCloser.Close(); e != nil && err == nil {
+			err = e
+		}
+		f.packageCloser = nil
 	}
-	return buf, zw.Close()
+	return err
 }
@@ -1,10 +1,18 @@
 package excelize
 
 import (
+	"archive/zip"
 	"bufio"
 	"bytes"
+	"compress/flate"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -46,3 +54,333 @@ func TestWriteTo(t *testing.T) {
 	_, err = f.WriteTo(bufio.NewWriter(&buf))
 	assert.EqualError(t, err, "zip: FileHeader.Name too long")
 }
+
+func TestWriteToBuffer(t *testing.T) {
+	// Test write the spreadsheet saved with the options without the password.
+	f := NewFile()
+	path := filepath.Join("test", "TestWriteToBuffer.xlsx")
+	assert.NoError(t, f.SaveAs(path, Options{Reproducible: true}))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	_, err = OpenReader(buf)
+	assert.NoError(t, err)
+
+	// Test write the spreadsheet opened with the password.
+	assert.EqualError(t, f.SaveAs(path, Options{Password: "password"}), "not support encryption currently")
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	_, err = OpenReader(buf)
+	assert.NoError(t, err)
+	f, err = OpenFile(filepath.Join("test", "encryptSHA1.xlsx"), Options{Password: "password"})
+	assert.NoError(t, err)
+	_, err = f.WriteToBuffer()
+	assert.EqualError(t, err, "not support encryption currently")
+}
+
+func TestStrictNamespace(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Strict"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A1", "https://github.com/360EntSecGroup-Skylar/excelize", "External"))
+	assert.NoError(t, f.AddPicture("Sheet1", "B2", filepath.Join("test", "images", "excel.png"), ""))
+	path := filepath.Join("test", "TestStrictNamespace.xlsx")
+	assert.NoError(t, f.SaveAs(path, Options{Strict: true}))
+
+	parts := readZipParts(t, path)
+	assert.Contains(t, string(parts["xl/workbook.xml"]), `xmlns="`+StrictNameSpaceSpreadSheet+`"`)
+	assert.Contains(t, string(parts["xl/workbook.xml"]), `conformance="strict"`)
+	assert.NotContains(t, string(parts["xl/worksheets/sheet1.xml"]), NameSpaceSpreadSheet.Value)
+	assert.Contains(t, string(parts["xl/drawings/drawing1.xml"]), "http://purl.oclc.org/ooxml/drawingml/spreadsheetDrawing")
+	assert.Contains(t, string(parts["docProps/app.xml"]), StrictNameSpaceExtendedProperties)
+	assert.Contains(t, string(parts["_rels/.rels"]), StrictSourceRelationshipExtendProperties)
+
+	// Test read the spreadsheet with the Strict namespaces.
+	for _, opts := range []Options{{}, {LazyLoad: true}} {
+		f, err := OpenFile(path, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Sheet1"}, f.GetSheetList())
+		value, err := f.GetCellValue("Sheet1", "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Strict", value)
+		link, target, err := f.GetCellHyperLink("Sheet1", "A1")
+		assert.NoError(t, err)
+		assert.True(t, link)
+		assert.Equal(t, "https://github.com/360EntSecGroup-Skylar/excelize", target)
+		file, raw, err := f.GetPicture("Sheet1", "B2")
+		assert.NoError(t, err)
+		assert.Equal(t, "image1.png", file)
+		assert.NotEmpty(t, raw)
+		buf, err := f.WriteToBuffer()
+		assert.NoError(t, err)
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		for _, zf := range zr.File {
+			content, err := readFile(zf)
+			assert.NoError(t, err)
+			assert.NotContains(t, string(content), strictNamespacePrefix, zf.Name)
+			assert.NotContains(t, string(content), `conformance="strict"`, zf.Name)
+		}
+		assert.NoError(t, f.Close())
+	}
+
+	// Test convert the relationship types with the Strict namespaces.
+	assert.Equal(t, `<Relationship Type="`+SourceRelationshipExtendProperties+`"/>`, string(namespaceStrictToTransitional([]byte(`<Relationship Type="`+StrictSourceRelationshipExtendProperties+`"/>`))))
+	assert.Equal(t, `<Relationship Type='`+SourceRelationshipChart+`'/>`, string(namespaceStrictToTransitional([]byte(`<Relationship Type='`+StrictSourceRelationshipChart+`'/>`))))
+	assert.Equal(t, `<Relationship Type="`+StrictSourceRelationshipImage+`"/>`, string(namespaceTransitionalToStrict([]byte(`<Relationship Type="`+SourceRelationshipImage+`"/>`))))
+
+	// Test the namespaces in the cell values and the other attributes will not be converted.
+	assert.Equal(t, `<sst xmlns="`+NameSpaceSpreadSheet.Value+`"><si><t>`+StrictNameSpaceSpreadSheet+`</t></si></sst>`,
+		string(namespaceStrictToTransitional([]byte(`<sst xmlns="`+StrictNameSpaceSpreadSheet+`"><si><t>`+StrictNameSpaceSpreadSheet+`</t></si></sst>`))))
+	assert.Equal(t, `<Relationship Target="`+SourceRelationshipImage+`"/>`, string(namespaceTransitionalToStrict([]byte(`<Relationship Target="`+SourceRelationshipImage+`"/>`))))
+}
+
+func readZipParts(t *testing.T, path string) map[string][]byte {
+	zr, err := zip.OpenReader(path)
+	assert.NoError(t, err)
+	defer zr.Close()
+	parts := make(map[string][]byte)
+	for _, zf := range zr.File {
+		parts[zf.Name], err = readFile(zf)
+		assert.NoError(t, err)
+	}
+	return parts
+}
+
+func TestWriteStream(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 100; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(row), &[]interface{}{"excelize", row, true}))
+	}
+	var buf bytes.Buffer
+	n, err := f.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	f, err = OpenReader(&buf)
+	assert.NoError(t, err)
+	value, err := f.GetCellValue("Sheet1", "B100")
+	assert.NoError(t, err)
+	assert.Equal(t, "100", value)
+
+	// Test write with the compression levels.
+	sizes := make(map[int]int)
+	for _, level := range []int{flate.HuffmanOnly, flate.BestCompression} {
+		buf.Reset()
+		assert.NoError(t, f.Write(&buf, Options{CompressionLevel: level}))
+		sizes[level] = buf.Len()
+		f, err = OpenReader(bytes.NewReader(buf.Bytes()))
+		assert.NoError(t, err)
+	}
+	assert.Greater(t, sizes[flate.HuffmanOnly], sizes[flate.BestCompression])
+	assert.EqualError(t, f.Write(&buf, Options{CompressionLevel: 10}), "flate: invalid compression level 10: want value in range [-2, 9]")
+
+	// Test write the encrypted spreadsheet.
+	buf.Reset()
+	assert.EqualError(t, f.Write(&buf, Options{Password: "password"}), "not support encryption currently")
+	assert.Zero(t, buf.Len())
+
+	// Test the options of writing and saving only take effect on the call.
+	assert.Nil(t, f.options)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestWrite.xlsx"), Options{Reproducible: true}))
+	assert.Nil(t, f.options)
+	buf.Reset()
+	assert.NoError(t, f.Write(&buf))
+	_, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+
+	// Test write to the writer with error.
+	_, err = f.WriteTo(errWriter{})
+	assert.EqualError(t, err, "write error")
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errors.New("write error") }
+
+func TestWriteReproducible(t *testing.T) {
+	opts := Options{
+		Reproducible: true,
+		CreatedTime:  time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC),
+		ModifiedTime: time.Date(2020, time.February, 3, 4, 5, 6, 0, time.UTC),
+	}
+	newBook := func() *File {
+		f := NewFile()
+		for _, sheet := range []string{"Sheet2", "Sheet10", "Sheet3"} {
+			f.NewSheet(sheet)
+			assert.NoError(t, f.SetCellValue(sheet, "A1", sheet))
+		}
+		// Test with the drawings, pictures and comments.
+		assert.NoError(t, f.AddPicture("Sheet2", "B2", filepath.Join("test", "images", "excel.png"), ""))
+		assert.NoError(t, f.AddPicture("Sheet3", "B2", filepath.Join("test", "images", "excel.jpg"), ""))
+		assert.NoError(t, f.AddShape("Sheet3", "D2", `{"type":"rect","paragraph":[{"text":"Rectangle"}]}`))
+		assert.NoError(t, f.AddComment("Sheet2", "A1", `{"author":"Excelize: ","text":"comment"}`))
+		assert.NoError(t, f.AddComment("Sheet10", "A1", `{"author":"Excelize: ","text":"comment"}`))
+		return f
+	}
+	var expected []byte
+	for i := 0; i < 10; i++ {
+		var buf bytes.Buffer
+		assert.NoError(t, newBook().Write(&buf, opts))
+		if expected == nil {
+			expected = buf.Bytes()
+			continue
+		}
+		assert.Equal(t, expected, buf.Bytes())
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(expected), int64(len(expected)))
+	assert.NoError(t, err)
+	assert.Equal(t, "[Content_Types].xml", zr.File[0].Name)
+	assert.Equal(t, "_rels/.rels", zr.File[1].Name)
+	for _, file := range zr.File {
+		assert.True(t, opts.ModifiedTime.Equal(file.Modified), file.Name)
+	}
+
+	// Test the created and modified time of the document core properties.
+	f, err := OpenReader(bytes.NewReader(expected))
+	assert.NoError(t, err)
+	props, err := f.GetDocProps()
+	assert.NoError(t, err)
+	assert.Equal(t, "2020-01-02T03:04:05Z", props.Created)
+	assert.Equal(t, "2020-02-03T04:05:06Z", props.Modified)
+
+	// Test the zip entries with the MS-DOS epoch timestamp.
+	var buf bytes.Buffer
+	assert.NoError(t, newBook().Write(&buf, Options{Reproducible: true}))
+	zr, err = zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, 1980, zr.File[0].Modified.Year())
+}
+
+func TestWriteUnmodifiedParts(t *testing.T) {
+	source := readZipParts(t, filepath.Join("test", "Book1.xlsx"))
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	// Test read the workbook, worksheets, styles and shared strings.
+	assert.Equal(t, []string{"Sheet1", "Sheet2"}, f.GetSheetList())
+	for _, sheet := range f.GetSheetList() {
+		_, err = f.GetCellValue(sheet, "A1")
+		assert.NoError(t, err)
+		_, err = f.GetCellStyle(sheet, "A1")
+		assert.NoError(t, err)
+		_, err = f.GetRows(sheet)
+		assert.NoError(t, err)
+		_, err = f.GetColWidth(sheet, "A")
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, f.SetCellValue("Sheet2", "A1", 1))
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	parts := readZipBytes(t, buf.Bytes())
+	// Test the parts which have not been modified are copied byte-for-byte.
+	for _, name := range []string{"xl/workbook.xml", "xl/worksheets/sheet1.xml", "xl/styles.xml", "xl/sharedStrings.xml"} {
+		assert.Equal(t, source[name], parts[name], name)
+	}
+	assert.NotEqual(t, source["xl/worksheets/sheet2.xml"], parts["xl/worksheets/sheet2.xml"])
+
+	// Test the parts will be serialized after modified.
+	_, err = f.NewStyle(&Style{NumFmt: 14})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "unmodified"))
+	f.SetActiveSheet(1)
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	parts = readZipBytes(t, buf.Bytes())
+	for _, name := range []string{"xl/workbook.xml", "xl/worksheets/sheet1.xml", "xl/styles.xml", "xl/sharedStrings.xml"} {
+		assert.NotEqual(t, source[name], parts[name], name)
+	}
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "unmodified", val)
+
+	// Test the structures which have only been read are assigned to the
+	// exported fields, and the changes of the structures by the functions
+	// will be serialized.
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	_, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	_, err = f.GetCellStyle("Sheet1", "A1")
+	assert.NoError(t, err)
+	_, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.NotNil(t, f.WorkBook)
+	assert.NotNil(t, f.Styles)
+	assert.NotNil(t, f.SharedStrings)
+	assert.NotNil(t, f.Sheet["xl/worksheets/sheet1.xml"])
+	assert.NoError(t, f.SetCellValue("Sheet2", "A1", "modified"))
+	assert.NotNil(t, f.Sheet["xl/worksheets/sheet2.xml"])
+	_, err = f.NewStyle(&Style{Font: &Font{Family: "Excelize"}})
+	assert.NoError(t, err)
+	font := f.Styles.Fonts.Font[len(f.Styles.Fonts.Font)-1]
+	font.Name.Val = stringPtr("Modified")
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	parts = readZipBytes(t, buf.Bytes())
+	assert.Equal(t, source["xl/worksheets/sheet1.xml"], parts["xl/worksheets/sheet1.xml"])
+	assert.Contains(t, string(parts["xl/styles.xml"]), `<name val="Modified">`)
+
+	// Test the structure which has been replaced will be serialized.
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	_, err = f.GetCellStyle("Sheet1", "A1")
+	assert.NoError(t, err)
+	f.Styles = &xlsxStyleSheet{}
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.NotEqual(t, source["xl/styles.xml"], readZipBytes(t, buf.Bytes())["xl/styles.xml"])
+}
+
+func TestSaveAsContentType(t *testing.T) {
+	getContentType := func(parts map[string][]byte) string {
+		content := new(xlsxTypes)
+		assert.NoError(t, xml.Unmarshal(parts["[Content_Types].xml"], content))
+		for _, o := range content.Overrides {
+			if o.PartName == "/xl/workbook.xml" {
+				return o.ContentType
+			}
+		}
+		return ""
+	}
+	f := NewFile()
+	for _, ext := range []string{".xlsx", ".xltx", ".XLSX"} {
+		path := filepath.Join("test", "TestSaveAsContentType"+ext)
+		assert.NoError(t, f.SaveAs(path))
+		assert.Equal(t, workbookContentTypes[strings.ToLower(ext)], getContentType(readZipParts(t, path)))
+	}
+	// Test create new file from the template.
+	f, err := NewFileFromTemplate(filepath.Join("test", "TestSaveAsContentType.xltx"))
+	assert.NoError(t, err)
+	assert.Equal(t, "", f.Path)
+	assert.EqualError(t, f.Save(), "no path defined for file, consider File.WriteTo or File.Write")
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.Equal(t, ContentTypeSheetML, getContentType(readZipBytes(t, buf.Bytes())))
+
+	// Test save the macros with the macro-free and macro-enabled file extensions.
+	assert.NoError(t, f.AddVBAProject(filepath.Join("test", "vbaProject.bin")))
+	for _, ext := range []string{".xlsx", ".xltx"} {
+		path := filepath.Join("test", "TestSaveAsContentTypeMacro"+ext)
+		err = f.SaveAs(path)
+		assert.True(t, errors.Is(err, ErrMacroFreeFormat))
+		assert.EqualError(t, err, "macros can't be saved in the macro-free file format "+ext)
+		_, err = os.Stat(path)
+		assert.True(t, os.IsNotExist(err))
+	}
+	for _, ext := range []string{".xlsm", ".xltm", ".xlam"} {
+		path := filepath.Join("test", "TestSaveAsContentTypeMacro"+ext)
+		assert.NoError(t, f.SaveAs(path))
+		assert.Equal(t, workbookContentTypes[ext], getContentType(readZipParts(t, path)))
+	}
+	f, err = NewFileFromTemplate(filepath.Join("test", "TestSaveAsContentTypeMacro.xltm"))
+	assert.NoError(t, err)
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.Equal(t, ContentTypeMacro, getContentType(readZipBytes(t, buf.Bytes())))
+
+	// Test create new file from the template with invalid path and file.
+	_, err = NewFileFromTemplate(filepath.Join("test", "NotExist.xltx"))
+	assert.Error(t, err)
+	_, err = NewFileFromTemplate(filepath.Join("test", "vbaProject.bin"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,483 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HTMLOptions directly maps the options of the ExportHTML. Range specifies
+// the cell range to be rendered such as "A1:D10", default is the used range
+// of the worksheet. ShowHidden specifies to render the hidden rows and
+// columns. RawCellValue specifies to render the raw cell values instead of
+// the formatted values.
+type HTMLOptions struct {
+	Range        string
+	ShowHidden   bool
+	RawCellValue bool
+}
+
+// htmlBorderStyles defined the CSS border styles by the border styles of the
+// cell.
+var htmlBorderStyles = map[string]string{
+	"thin":             "1px solid",
+	"medium":           "2px solid",
+	"thick":            "3px solid",
+	"double":           "3px double",
+	"hair":             "1px dotted",
+	"dotted":           "1px dotted",
+	"dashed":           "1px dashed",
+	"dashDot":          "1px dashed",
+	"dashDotDot":       "1px dashed",
+	"slantDashDot":     "2px dashed",
+	"mediumDashed":     "2px dashed",
+	"mediumDashDot":    "2px dashed",
+	"mediumDashDotDot": "2px dashed",
+}
+
+// htmlHorizontalAlignments defined the CSS text alignments by the horizontal
+// alignments of the cell.
+var htmlHorizontalAlignments = map[string]string{
+	"left":             "left",
+	"center":           "center",
+	"centerContinuous": "center",
+	"right":            "right",
+	"justify":          "justify",
+	"distributed":      "justify",
+	"fill":             "left",
+}
+
+// htmlVerticalAlignments defined the CSS vertical alignments by the vertical
+// alignments of the cell.
+var htmlVerticalAlignments = map[string]string{
+	"top":         "top",
+	"center":      "middle",
+	"bottom":      "bottom",
+	"justify":     "middle",
+	"distributed": "middle",
+}
+
+// htmlSheet defined the worksheet data to be rendered as HTML.
+type htmlSheet struct {
+	cells     map[string]*xlsxC
+	heights   map[int]float64
+	hiddenRow map[int]bool
+	hiddenCol map[int]bool
+	spans     map[string][2]int
+	covered   map[string]bool
+	links     map[string]string
+	styles    map[int]string
+}
+
+// ExportHTML provides a function to render the worksheet as HTML table by
+// given worksheet name, writer and options. The merged cells are rendered
+// with colspan and rowspan, the fonts, fills, borders and alignments of the
+// cells, column widths and row heights are rendered as inline CSS, so the
+// output can be used in email bodies. The default style of the workbook is
+// rendered on the table element. The hidden rows and columns are skipped,
+// and the external hyperlinks with http, https or mailto scheme are rendered
+// as links. All the cell values are escaped. For example, render the range
+// A1:D10 of Sheet1:
+//
+//    var buf bytes.Buffer
+//    err := f.ExportHTML("Sheet1", &buf, excelize.HTMLOptions{Range: "A1:D10"})
+//
+func (f *File) ExportHTML(sheet string, w io.Writer, opts ...HTMLOptions) error {
+	var options HTMLOptions
+	for _, o := range opts {
+		options = o
+	}
+	ws, err := f.workSheetReadOnly(sheet)
+	if err != nil {
+		return err
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	hs, coordinates, err := f.getHTMLSheet(sheet, ws, options)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`<table style="border-collapse:collapse;table-layout:fixed`)
+	if style := f.getHTMLCellStyle(0); style != "" {
+		bw.WriteString(";" + style)
+	}
+	bw.WriteString(`">`)
+	if coordinates == nil {
+		bw.WriteString(`</table>`)
+		return bw.Flush()
+	}
+	bw.WriteString(`<colgroup>`)
+	for col := coordinates[0]; col <= coordinates[2]; col++ {
+		if !hs.hiddenCol[col] || options.ShowHidden {
+			fmt.Fprintf(bw, `<col style="width:%dpx">`, f.getColWidth(sheet, col))
+		}
+	}
+	bw.WriteString(`</colgroup>`)
+	sst := f.sharedStringsReadOnly()
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		if hs.hiddenRow[row] && !options.ShowHidden {
+			continue
+		}
+		fmt.Fprintf(bw, `<tr style="height:%spt">`, strconv.FormatFloat(hs.heights[row], 'f', -1, 64))
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			if hs.hiddenCol[col] && !options.ShowHidden {
+				continue
+			}
+			axis, _ := CoordinatesToCellName(col, row)
+			if hs.covered[axis] {
+				continue
+			}
+			if err = f.writeHTMLCell(bw, hs, axis, sst, options); err != nil {
+				return err
+			}
+		}
+		bw.WriteString(`</tr>`)
+	}
+	bw.WriteString(`</table>`)
+	return bw.Flush()
+}
+
+// getHTMLSheet provides a function to collect the cells, row heights,
+// hidden rows and columns, merged cells and hyperlinks of the worksheet, and
+// returns the coordinates of the range to be rendered.
+func (f *File) getHTMLSheet(sheet string, ws *xlsxWorksheet, options HTMLOptions) (*htmlSheet, []int, error) {
+	hs := &htmlSheet{
+		cells: make(map[string]*xlsxC), heights: make(map[int]float64),
+		hiddenRow: make(map[int]bool), hiddenCol: make(map[int]bool),
+		spans: make(map[string][2]int), covered: make(map[string]bool),
+		links: make(map[string]string), styles: make(map[int]string),
+	}
+	var maxCol, maxRow int
+	for i := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[i]
+		hs.hiddenRow[row.R] = row.Hidden
+		if row.Ht != 0 {
+			hs.heights[row.R] = row.Ht
+		}
+		for j := range row.C {
+			col, r, err := CellNameToCoordinates(row.C[j].R)
+			if err != nil {
+				return hs, nil, err
+			}
+			hs.cells[row.C[j].R] = &row.C[j]
+			if col > maxCol {
+				maxCol = col
+			}
+			if r > maxRow {
+				maxRow = r
+			}
+		}
+	}
+	coordinates := []int{1, 1, maxCol, maxRow}
+	if options.Range != "" {
+		var err error
+		if coordinates, err = getHTMLRangeCoordinates(options.Range); err != nil {
+			return hs, nil, err
+		}
+	} else if maxCol == 0 {
+		return hs, nil, nil
+	}
+	defaultHeight := defaultRowHeight
+	if ws.SheetFormatPr != nil && ws.SheetFormatPr.DefaultRowHeight != 0 {
+		defaultHeight = ws.SheetFormatPr.DefaultRowHeight
+	}
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		if _, ok := hs.heights[row]; !ok {
+			hs.heights[row] = defaultHeight
+		}
+	}
+	if ws.Cols != nil {
+		for _, c := range ws.Cols.Col {
+			for col := c.Min; col <= c.Max && c.Hidden; col++ {
+				hs.hiddenCol[col] = true
+			}
+		}
+	}
+	if err := hs.setMergeCells(ws, coordinates, options); err != nil {
+		return hs, nil, err
+	}
+	if ws.Hyperlinks != nil {
+		for _, link := range ws.Hyperlinks.Hyperlink {
+			if link.RID != "" {
+				hs.links[link.Ref] = f.getSheetRelationshipsTargetByID(sheet, link.RID)
+			}
+		}
+	}
+	return hs, coordinates, nil
+}
+
+// getHTMLRangeCoordinates provides a function to convert the cell range or
+// single cell reference to the sorted pair of coordinates.
+func getHTMLRangeCoordinates(ref string) ([]int, error) {
+	rng := strings.Split(ref, ":")
+	if len(rng) == 1 {
+		rng = append(rng, rng[0])
+	}
+	if len(rng) != 2 {
+		return nil, newInvalidRangeError(ref)
+	}
+	coordinates, err := areaRangeToCoordinates(rng[0], rng[1])
+	if err != nil {
+		return nil, err
+	}
+	return coordinates, sortCoordinates(coordinates)
+}
+
+// setMergeCells provides a function to calculate the colspan and rowspan of
+// the merged cells in the given range, the hidden rows and columns are not
+// counted.
+func (hs *htmlSheet) setMergeCells(ws *xlsxWorksheet, coordinates []int, options HTMLOptions) error {
+	if ws.MergeCells == nil {
+		return nil
+	}
+	for _, mergeCell := range ws.MergeCells.Cells {
+		rect, err := getHTMLRangeCoordinates(mergeCell.Ref)
+		if err != nil {
+			return err
+		}
+		x1, y1, x2, y2 := rect[0], rect[1], rect[2], rect[3]
+		if x1 < coordinates[0] {
+			x1 = coordinates[0]
+		}
+		if y1 < coordinates[1] {
+			y1 = coordinates[1]
+		}
+		if x2 > coordinates[2] {
+			x2 = coordinates[2]
+		}
+		if y2 > coordinates[3] {
+			y2 = coordinates[3]
+		}
+		var start string
+		var colspan, rowspan int
+		for row := y1; row <= y2; row++ {
+			if hs.hiddenRow[row] && !options.ShowHidden {
+				continue
+			}
+			rowspan++
+			for col := x1; col <= x2; col++ {
+				if hs.hiddenCol[col] && !options.ShowHidden {
+					continue
+				}
+				axis, _ := CoordinatesToCellName(col, row)
+				if start == "" {
+					start = axis
+				} else if axis != start {
+					hs.covered[axis] = true
+				}
+				if rowspan == 1 {
+					colspan++
+				}
+			}
+		}
+		if start != "" {
+			hs.spans[start] = [2]int{colspan, rowspan}
+		}
+	}
+	return nil
+}
+
+// writeHTMLCell provides a function to write the td element of the cell by
+// given cell coordinates.
+func (f *File) writeHTMLCell(bw *bufio.Writer, hs *htmlSheet, axis string, sst *xlsxSST, options HTMLOptions) error {
+	var value, style string
+	if c, ok := hs.cells[axis]; ok {
+		var err error
+		if value, err = c.getValueFrom(f, sst, options.RawCellValue); err != nil {
+			return err
+		}
+		if style, ok = hs.styles[c.S]; !ok && c.S != 0 {
+			style = f.getHTMLCellStyle(c.S)
+			hs.styles[c.S] = style
+		}
+	}
+	bw.WriteString(`<td`)
+	if span, ok := hs.spans[axis]; ok {
+		if span[0] > 1 {
+			fmt.Fprintf(bw, ` colspan="%d"`, span[0])
+		}
+		if span[1] > 1 {
+			fmt.Fprintf(bw, ` rowspan="%d"`, span[1])
+		}
+	}
+	if style != "" {
+		fmt.Fprintf(bw, ` style="%s"`, style)
+	}
+	bw.WriteString(`>`)
+	content := strings.Replace(html.EscapeString(value), "\n", "<br>", -1)
+	if link, ok := hs.links[axis]; ok && isSafeHTMLLink(link) {
+		fmt.Fprintf(bw, `<a href="%s">%s</a>`, html.EscapeString(link), content)
+	} else {
+		bw.WriteString(content)
+	}
+	_, err := bw.WriteString(`</td>`)
+	return err
+}
+
+// isSafeHTMLLink provides a function to check if the hyperlink can be
+// rendered in HTML, only http, https and mailto scheme are allowed.
+func isSafeHTMLLink(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// getHTMLCellStyle provides a function to get the inline CSS of the cell by
+// given style ID.
+func (f *File) getHTMLCellStyle(styleID int) string {
+	s := f.stylesReadOnly()
+	if s.CellXfs == nil || styleID < 0 || styleID >= len(s.CellXfs.Xf) {
+		return ""
+	}
+	xf, css := s.CellXfs.Xf[styleID], []string{}
+	if xf.FontID != nil && s.Fonts != nil && *xf.FontID >= 0 && *xf.FontID < len(s.Fonts.Font) {
+		css = append(css, f.getHTMLFontStyle(s.Fonts.Font[*xf.FontID])...)
+	}
+	if xf.FillID != nil && s.Fills != nil && *xf.FillID >= 0 && *xf.FillID < len(s.Fills.Fill) {
+		if fill := s.Fills.Fill[*xf.FillID]; fill.PatternFill != nil && fill.PatternFill.PatternType != "" && fill.PatternFill.PatternType != "none" {
+			if color := f.getHTMLColor(&fill.PatternFill.FgColor); color != "" {
+				css = append(css, "background-color:"+color)
+			}
+		}
+	}
+	if xf.BorderID != nil && s.Borders != nil && *xf.BorderID >= 0 && *xf.BorderID < len(s.Borders.Border) {
+		border := s.Borders.Border[*xf.BorderID]
+		for _, side := range []struct {
+			name string
+			line xlsxLine
+		}{{"left", border.Left}, {"right", border.Right}, {"top", border.Top}, {"bottom", border.Bottom}} {
+			if style, ok := htmlBorderStyles[side.line.Style]; ok {
+				color := f.getHTMLColor(side.line.Color)
+				if color == "" {
+					color = "#000000"
+				}
+				css = append(css, fmt.Sprintf("border-%s:%s %s", side.name, style, color))
+			}
+		}
+	}
+	if xf.Alignment != nil {
+		if align, ok := htmlHorizontalAlignments[xf.Alignment.Horizontal]; ok {
+			css = append(css, "text-align:"+align)
+		}
+		if align, ok := htmlVerticalAlignments[xf.Alignment.Vertical]; ok {
+			css = append(css, "vertical-align:"+align)
+		}
+		if xf.Alignment.WrapText {
+			css = append(css, "white-space:pre-wrap")
+		}
+	}
+	return strings.Join(css, ";")
+}
+
+// getHTMLFontStyle provides a function to get the inline CSS of the font.
+func (f *File) getHTMLFontStyle(font *xlsxFont) []string {
+	var css []string
+	if font.Name != nil && font.Name.Val != nil {
+		if name := strings.Map(func(r rune) rune {
+			if r == '"' || r == '\'' || r == ';' || r == '<' || r == '>' || r == '&' || r == '\\' {
+				return -1
+			}
+			return r
+		}, *font.Name.Val); name != "" {
+			css = append(css, fmt.Sprintf("font-family:'%s'", name))
+		}
+	}
+	if font.Sz != nil && font.Sz.Val != nil {
+		css = append(css, "font-size:"+strconv.FormatFloat(*font.Sz.Val, 'f', -1, 64)+"pt")
+	}
+	if font.B != nil && *font.B {
+		css = append(css, "font-weight:bold")
+	}
+	if font.I != nil && *font.I {
+		css = append(css, "font-style:italic")
+	}
+	var decorations []string
+	if font.U != nil && (font.U.Val == nil || *font.U.Val != "none") {
+		decorations = append(decorations, "underline")
+	}
+	if font.Strike != nil && *font.Strike {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		css = append(css, "text-decoration:"+strings.Join(decorations, " "))
+	}
+	if color := f.getHTMLColor(font.Color); color != "" {
+		css = append(css, "color:"+color)
+	}
+	return css
+}
+
+// getHTMLColor provides a function to get the CSS color by given color of
+// the style, the theme colors will be resolved by the theme of the
+// workbook. Returns empty string if the color can't be resolved.
+func (f *File) getHTMLColor(color *xlsxColor) string {
+	if color == nil {
+		return ""
+	}
+	rgb := color.RGB
+	if color.Theme != nil {
+		rgb = f.getThemeColor(*color.Theme, color.Tint)
+	}
+	if len(rgb) == 8 {
+		rgb = rgb[2:]
+	}
+	if len(rgb) != 6 {
+		return ""
+	}
+	if _, err := strconv.ParseUint(rgb, 16, 32); err != nil {
+		return ""
+	}
+	return "#" + strings.ToUpper(rgb)
+}
+
+// getThemeColor provides a function to get the ARGB color by given theme
+// color index and tint. The first four indexes of the theme colors are
+// light 1, dark 1, light 2 and dark 2, which are in reverse order of the
+// color scheme of the theme.
+func (f *File) getThemeColor(index int, tint float64) string {
+	if f.Theme == nil {
+		return ""
+	}
+	if index < 4 {
+		index ^= 1
+	}
+	children := f.Theme.ThemeElements.ClrScheme.Children
+	if index < 0 || index >= len(children) {
+		return ""
+	}
+	var base string
+	if clr := children[index]; clr.SrgbClr != nil && clr.SrgbClr.Val != nil {
+		base = *clr.SrgbClr.Val
+	} else if clr.SysClr != nil {
+		base = clr.SysClr.LastClr
+	}
+	if len(base) != 6 {
+		return ""
+	}
+	if _, err := strconv.ParseUint(base, 16, 32); err != nil {
+		return ""
+	}
+	return ThemeColor(base, tint)
+}
@@ -0,0 +1,86 @@
+package excelize
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportHTML(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Title", nil, nil, "Hidden"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"<b>x</b> & \"y\"", 1.5, "line1\nline2"}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", "hidden row"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A4", "Link"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B4", "Script"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C4", "Location"))
+	assert.NoError(t, f.MergeCell("Sheet1", "A1", "C1"))
+	assert.NoError(t, f.SetColVisible("Sheet1", "D", false))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 3, false))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 2, 30))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "A", 20))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A4", "https://github.com/360EntSecGroup-Skylar/excelize?a=1&b=2", "External"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "B4", "javascript:alert(1)", "External"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "C4", "Sheet1!A1", "Location"))
+	style, err := f.NewStyle(`{"font":{"bold":true,"italic":true,"underline":"single","strike":true,"family":"Arial\"><script>","size":14,"color":"#FF0000"},"fill":{"type":"pattern","color":["#E0EBF5"],"pattern":1},"border":[{"type":"left","color":"0000FF","style":3},{"type":"top","style":1}],"alignment":{"horizontal":"center","vertical":"center","wrap_text":true}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportHTML("Sheet1", &buf))
+	assert.Equal(t, `<table style="border-collapse:collapse;table-layout:fixed;font-family:'Calibri';font-size:11pt;color:#000000">`+
+		`<colgroup><col style="width:146px"><col style="width:64px"><col style="width:64px"></colgroup>`+
+		`<tr style="height:15pt"><td colspan="3" style="font-family:'Arialscript';font-size:14pt;font-weight:bold;font-style:italic;text-decoration:underline line-through;color:#FF0000;background-color:#E0EBF5;border-left:1px dashed #0000FF;border-top:1px solid #000000;text-align:center;vertical-align:middle;white-space:pre-wrap">Title</td></tr>`+
+		`<tr style="height:30pt"><td>&lt;b&gt;x&lt;/b&gt; &amp; &#34;y&#34;</td><td>1.5</td><td>line1<br>line2</td></tr>`+
+		`<tr style="height:15pt"><td><a href="https://github.com/360EntSecGroup-Skylar/excelize?a=1&amp;b=2">Link</a></td><td>Script</td><td>Location</td></tr>`+
+		`</table>`, buf.String())
+
+	// Test export HTML with range and hidden rows and columns.
+	buf.Reset()
+	assert.NoError(t, f.ExportHTML("Sheet1", &buf, HTMLOptions{Range: "B3:D1", ShowHidden: true}))
+	assert.Equal(t, `<table style="border-collapse:collapse;table-layout:fixed;font-family:'Calibri';font-size:11pt;color:#000000">`+
+		`<colgroup><col style="width:64px"><col style="width:64px"><col style="width:69px"></colgroup>`+
+		`<tr style="height:15pt"><td colspan="2"></td><td>Hidden</td></tr>`+
+		`<tr style="height:30pt"><td>1.5</td><td>line1<br>line2</td><td></td></tr>`+
+		`<tr style="height:15pt"><td></td><td></td><td></td></tr>`+
+		`</table>`, buf.String())
+
+	// Test export HTML on empty worksheet.
+	buf.Reset()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.ExportHTML("Sheet2", &buf))
+	assert.Equal(t, `<table style="border-collapse:collapse;table-layout:fixed;font-family:'Calibri';font-size:11pt;color:#000000"></table>`, buf.String())
+
+	// Test export HTML with invalid arguments.
+	assert.EqualError(t, f.ExportHTML("SheetN", &buf), "sheet SheetN is not exist")
+	assert.EqualError(t, f.ExportHTML("Sheet1", &buf, HTMLOptions{Range: "A1:B2:C3"}), `invalid cell range "A1:B2:C3"`)
+	assert.EqualError(t, f.ExportHTML("Sheet1", &buf, HTMLOptions{Range: "A:B"}), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.ExportHTML("Sheet1", iotestErrWriter{}), "write error")
+}
+
+func TestGetHTMLColor(t *testing.T) {
+	f := NewFile()
+	theme, invalid := 4, 20
+	for _, c := range []struct {
+		color    *xlsxColor
+		expected string
+	}{
+		{nil, ""},
+		{&xlsxColor{RGB: "FF00FF00"}, "#00FF00"},
+		{&xlsxColor{RGB: "00ff00"}, "#00FF00"},
+		{&xlsxColor{RGB: "red"}, ""},
+		{&xlsxColor{RGB: "ZZZZZZ"}, ""},
+		{&xlsxColor{Theme: &theme}, "#5B9BD5"},
+		{&xlsxColor{Theme: &invalid}, ""},
+	} {
+		assert.Equal(t, c.expected, f.getHTMLColor(c.color))
+	}
+	dark1, light1 := 1, 0
+	assert.Equal(t, "#000000", f.getHTMLColor(&xlsxColor{Theme: &dark1}))
+	assert.Equal(t, "#FFFFFF", f.getHTMLColor(&xlsxColor{Theme: &light1}))
+	f.Theme = nil
+	assert.Equal(t, "", f.getThemeColor(4, 0))
+	assert.Equal(t, "", f.getHTMLCellStyle(-1))
+	assert.False(t, isSafeHTMLLink("%zz"))
+}
@@ -18,14 +18,23 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
 // ReadZipReader can be used to read the spreadsheet in memory without touching the
-// filesystem.
-func ReadZipReader(r *zip.Reader) (map[string][]byte, int, error) {
+// filesystem. The number of the parts and the uncompressed size of the parts
+// will be checked by the limits of the options before inflating.
+func ReadZipReader(r *zip.Reader, opt ...Options) (map[string][]byte, int, error) {
 	var err error
+	if err = checkZipReader(r, parseOptions(opt...)); err != nil {
+		return nil, 0, err
+	}
 	var docPart = map[string]string{
 		"[content_types].xml":  "[Content_Types].xml",
 		"xl/sharedstrings.xml": "xl/sharedStrings.xml",
@@ -47,12 +56,214 @@ func ReadZipReader(r *zip.Reader) (map[string][]byte, int, error) {
 	return fileList, worksheets, nil
 }
 
-// readXML provides a function to read XML content as string.
+// checkZipReader provides a function to check the number of the parts and
+// the declared uncompressed size of the parts in the spreadsheet package by
+// the limits of the given options. The zip reader reports an error when the
+// inflated content of a part exceeds the declared size, so the limits will
+// be checked before inflating any part.
+func checkZipReader(r *zip.Reader, opts Options) error {
+	if opts.MaxParts > 0 && len(r.File) > opts.MaxParts {
+		return newMaxPartsError(len(r.File), opts.MaxParts)
+	}
+	var total uint64
+	for _, zf := range r.File {
+		size := zf.UncompressedSize64
+		if opts.UnzipPartSizeLimit > 0 && size > uint64(opts.UnzipPartSizeLimit) {
+			return newUnzipPartSizeLimitError(zf.Name, opts.UnzipPartSizeLimit)
+		}
+		if opts.UnzipSizeLimit > 0 {
+			if size > uint64(opts.UnzipSizeLimit)-total {
+				return newUnzipSizeLimitError(opts.UnzipSizeLimit)
+			}
+			total += size
+		}
+	}
+	return nil
+}
+
+// readZipReaderLazy provides a function to get the part list of the
+// spreadsheet package without inflating the parts. The content of the parts
+// in the list are nil and will be inflated on first access.
+func (f *File) readZipReaderLazy(r *zip.Reader) (map[string][]byte, int) {
+	var docPart = map[string]string{
+		"[content_types].xml":  "[Content_Types].xml",
+		"xl/sharedstrings.xml": "xl/sharedStrings.xml",
+	}
+	fileList := make(map[string][]byte, len(r.File))
+	worksheets := 0
+	for _, v := range r.File {
+		fileName := v.Name
+		if partName, ok := docPart[strings.ToLower(v.Name)]; ok {
+			fileName = partName
+		}
+		fileList[fileName], f.zipParts[fileName] = nil, v
+		if strings.HasPrefix(v.Name, "xl/worksheets/sheet") {
+			worksheets++
+		}
+	}
+	return fileList, worksheets
+}
+
+// loadPart provides a function to inflate the part of the spreadsheet
+// package by given part name. The worksheet which size exceeds the
+// UnzipXMLSizeLimit of the options will be extracted to a temporary file.
+// The Strict namespaces of the XML part will be converted to the Transitional
+// namespaces, and the binary worksheet will be converted to the worksheet
+// XML. The caller should hold the partsLock.
+func (f *File) loadPart(name string) error {
+	if part, ok := f.xlsbParts[name]; ok {
+		return f.loadXLSBPart(name, part)
+	}
+	zf, ok := f.zipParts[name]
+	if !ok {
+		return nil
+	}
+	if f.unzipSizeLimit > 0 && int64(zf.UncompressedSize64) > f.unzipSizeLimit &&
+		strings.HasPrefix(name, "xl/worksheets/sheet") {
+		path, err := unzipToTemp(zf)
+		if err != nil {
+			return err
+		}
+		delete(f.zipParts, name)
+		f.tempFiles[name] = path
+		return nil
+	}
+	content, err := readFile(zf)
+	if err != nil {
+		return err
+	}
+	if isXMLPart(name) {
+		content = namespaceStrictToTransitional(content)
+	}
+	delete(f.zipParts, name)
+	f.XLSX[name] = content
+	return nil
+}
+
+// loadParts provides a function to inflate all parts of the spreadsheet
+// package which have not been loaded.
+func (f *File) loadParts() error {
+	f.partsLock.Lock()
+	defer f.partsLock.Unlock()
+	for name, content := range f.XLSX {
+		if content == nil {
+			if err := f.loadPart(name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// partSize provides a function to get the uncompressed size of the part by
+// given part name.
+func (f *File) partSize(name string) int64 {
+	f.partsLock.Lock()
+	defer f.partsLock.Unlock()
+	if zf, ok := f.zipParts[name]; ok {
+		return int64(zf.UncompressedSize64)
+	}
+	if path, ok := f.tempFiles[name]; ok {
+		if fi, err := os.Stat(path); err == nil {
+			return fi.Size()
+		}
+	}
+	return int64(len(f.XLSX[name]))
+}
+
+// unzipToTemp provides a function to extract the part of the spreadsheet
+// package into a temporary file, and returns the path of the file.
+func unzipToTemp(zf *zip.File) (string, error) {
+	rc, err := zf.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	tmp, err := ioutil.TempFile(os.TempDir(), "excelize-")
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.Copy(tmp, rc); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), tmp.Close()
+}
+
+// readBytes provides a function to read the content of the part by given
+// part name. The part which has not been loaded will be inflated from the
+// spreadsheet package, or be read from the temporary file. It returns nil if
+// the part doesn't exist.
+func (f *File) readBytes(name string) ([]byte, error) {
+	f.partsLock.Lock()
+	defer f.partsLock.Unlock()
+	content, ok := f.XLSX[name]
+	if !ok || content != nil {
+		return content, nil
+	}
+	if err := f.loadPart(name); err != nil {
+		return nil, err
+	}
+	if path, ok := f.tempFiles[name]; ok {
+		return ioutil.ReadFile(path)
+	}
+	if content = f.XLSX[name]; content == nil {
+		return []byte{}, nil
+	}
+	return content, nil
+}
+
+// spilledPart provides a function to get the path of the temporary file
+// which the part has been extracted to by given part name, the part which
+// has not been loaded will be inflated first.
+func (f *File) spilledPart(name string) (string, bool, error) {
+	f.partsLock.Lock()
+	defer f.partsLock.Unlock()
+	if content, ok := f.XLSX[name]; !ok || content != nil {
+		return "", false, nil
+	}
+	if err := f.loadPart(name); err != nil {
+		return "", false, err
+	}
+	path, ok := f.tempFiles[name]
+	return path, ok, nil
+}
+
+// openPart provides a function to get the reader of the part by given part
+// name without loading the whole part which has not been loaded into
+// memory. The caller should close the returned reader.
+func (f *File) openPart(name string) (io.ReadCloser, error) {
+	f.partsLock.Lock()
+	defer f.partsLock.Unlock()
+	content, ok := f.XLSX[name]
+	if !ok || content != nil {
+		return ioutil.NopCloser(bytes.NewReader(content)), nil
+	}
+	if path, ok := f.tempFiles[name]; ok {
+		return os.Open(path)
+	}
+	if zf, ok := f.zipParts[name]; ok {
+		return zf.Open()
+	}
+	if part, ok := f.xlsbParts[name]; ok {
+		return part.open()
+	}
+	return ioutil.NopCloser(bytes.NewReader(content)), nil
+}
+
+// readXML provides a function to read XML content as string for the readers
+// which can't return the error, the error of reading the part will be
+// logged like the errors of decoding the part.
 func (f *File) readXML(name string) []byte {
-	if content, ok := f.XLSX[name]; ok {
-		return content
+	content, err := f.readBytes(name)
+	if err != nil {
+		log.Printf("read part %s error: %s", name, err)
+	}
+	if content == nil {
+		return []byte{}
 	}
-	return []byte{}
+	return content
 }
 
 // saveFileList provides a function to update given file content in file list
@@ -70,10 +281,12 @@ func readFile(file *zip.File) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer rc.Close()
 	dat := make([]byte, 0, file.FileInfo().Size())
 	buff := bytes.NewBuffer(dat)
-	_, _ = io.Copy(buff, rc)
-	rc.Close()
+	if _, err = io.Copy(buff, rc); err != nil {
+		return nil, err
+	}
 	return buff.Bytes(), nil
 }
 
@@ -239,20 +452,80 @@ func parseFormatSet(formatSet string) []byte {
 	return []byte("{}")
 }
 
+// strictNamespacePrefix defined the common prefix of the Strict namespaces.
+const strictNamespacePrefix = "http://purl.oclc.org/ooxml/"
+
+// strictNamespaces defined the pairs of the Strict and Transitional
+// namespaces, the namespaces which not be translated by the prefixes should
+// be placed before the prefixes.
+var strictNamespaces = [][2]string{
+	{StrictSourceRelationshipExtendProperties, SourceRelationshipExtendProperties},
+	{StrictSourceRelationshipCustomProperties, SourceRelationshipCustomProperties},
+	{StrictNameSpaceExtendedProperties, NameSpaceExtendedProperties},
+	{StrictNameSpaceCustomProperties, NameSpaceCustomProperties},
+	{"http://purl.oclc.org/ooxml/officeDocument/", "http://schemas.openxmlformats.org/officeDocument/2006/"},
+	{"http://purl.oclc.org/ooxml/drawingml/", "http://schemas.openxmlformats.org/drawingml/2006/"},
+	{"http://purl.oclc.org/ooxml/spreadsheetml/", "http://schemas.openxmlformats.org/spreadsheetml/2006/"},
+	{"http://purl.oclc.org/ooxml/schemaLibrary/", "http://schemas.openxmlformats.org/schemaLibrary/2006/"},
+}
+
+// namespaceAttrExp defined the regular expression to match the values of
+// the namespace declarations, the relationship types and the URIs of the
+// graphic data, which are the only attributes referencing the namespaces.
+var namespaceAttrExp = regexp.MustCompile(`\s(?:xmlns(?::[^\s=]+)?|Type|uri)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
 // namespaceStrictToTransitional provides a method to convert Strict and
 // Transitional namespaces.
 func namespaceStrictToTransitional(content []byte) []byte {
-	var namespaceTranslationDic = map[string]string{
-		StrictSourceRelationship:         SourceRelationship.Value,
-		StrictSourceRelationshipChart:    SourceRelationshipChart,
-		StrictSourceRelationshipComments: SourceRelationshipComments,
-		StrictSourceRelationshipImage:    SourceRelationshipImage,
-		StrictNameSpaceSpreadSheet:       NameSpaceSpreadSheet.Value,
+	if !bytes.Contains(content, []byte(strictNamespacePrefix)) {
+		return content
+	}
+	return convertNamespaces(content, 0, 1)
+}
+
+// namespaceTransitionalToStrict provides a method to convert Transitional
+// namespaces to Strict namespaces.
+func namespaceTransitionalToStrict(content []byte) []byte {
+	return convertNamespaces(content, 1, 0)
+}
+
+// convertNamespaces provides a function to convert the namespaces in the
+// values of the attributes which reference the namespaces by given index of
+// the source and target namespaces in the pairs of the Strict and
+// Transitional namespaces. The cell values and other content will not be
+// changed.
+func convertNamespaces(content []byte, from, to int) []byte {
+	var (
+		buf  bytes.Buffer
+		last int
+	)
+	for _, match := range namespaceAttrExp.FindAllSubmatchIndex(content, -1) {
+		start, end := match[2], match[3]
+		if start == -1 {
+			start, end = match[4], match[5]
+		}
+		value := string(content[start:end])
+		for _, ns := range strictNamespaces {
+			if strings.HasPrefix(value, ns[from]) {
+				buf.Write(content[last:start])
+				buf.WriteString(ns[to] + value[len(ns[from]):])
+				last = end
+				break
+			}
+		}
 	}
-	for s, n := range namespaceTranslationDic {
-		content = bytesReplace(content, []byte(s), []byte(n), -1)
+	if last == 0 {
+		return content
 	}
-	return content
+	buf.Write(content[last:])
+	return buf.Bytes()
+}
+
+// isXMLPart provides a function to check if the part of the spreadsheet
+// package is an XML part by given part name.
+func isXMLPart(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".xml" || ext == ".rels"
 }
 
 // bytesReplace replace old bytes with given new.
@@ -285,6 +558,45 @@ func bytesReplace(s, old, new []byte, n int) []byte {
 	return s[0:w]
 }
 
+// getRelsPath provides a function to get the path of the relationships part
+// by given part name, for example, get xl/worksheets/_rels/sheet1.xml.rels
+// by xl/worksheets/sheet1.xml.
+func getRelsPath(name string) string {
+	return path.Join(path.Dir(name), "_rels", path.Base(name)+".rels")
+}
+
+// getRelsTargetPath provides a function to get the part name of the internal
+// relationship target by given path of the relationships part and the target
+// which is relative to the source part or absolute to the package root.
+func getRelsTargetPath(relsPath, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(path.Dir(relsPath)), target)
+}
+
+// quoteSheetName provides a function to quote the sheet name in the cell
+// reference of the formula if the sheet name contains characters other
+// than letters, digits, underscores and periods.
+func quoteSheetName(sheet string) string {
+	if strings.IndexFunc(sheet, func(r rune) bool {
+		return !(r == '_' || r == '.' || r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z')
+	}) == -1 {
+		return sheet
+	}
+	return "'" + strings.Replace(sheet, "'", "''", -1) + "'"
+}
+
+// unquoteSheetName provides a function to remove the single quotes around
+// the sheet name in the reference, and unescape the single quotes in the
+// sheet name.
+func unquoteSheetName(sheet string) string {
+	if len(sheet) > 1 && sheet[0] == '\'' && sheet[len(sheet)-1] == '\'' {
+		return strings.Replace(sheet[1:len(sheet)-1], "''", "'", -1)
+	}
+	return sheet
+}
+
 // genSheetPasswd provides a method to generate password for worksheet
 // protection by given plaintext. When an Excel sheet is being protected with
 // a password, a 16-bit (two byte) long hash is generated. To verify a
@@ -387,6 +699,7 @@ func (f *File) addNameSpaces(path string, ns xml.Attr) {
 		}
 	}
 	if !exist {
+		f.setModified(path)
 		f.xmlAttr[path] = append(f.xmlAttr[path], ns)
 		if !mc {
 			f.xmlAttr[path] = append(f.xmlAttr[path], SourceRelationshipCompatibility)
@@ -0,0 +1,365 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FormulaLocale directly maps the localized formula grammar of a locale.
+// ArgSeparator is the separator of the function arguments, DecimalSeparator
+// is the decimal mark of the numbers, ArrayColumnSeparator and
+// ArrayRowSeparator are the separators in the array constants, and the Names
+// maps the en-US function names, logical values and error values to the
+// localized names.
+type FormulaLocale struct {
+	ArgSeparator         string
+	DecimalSeparator     string
+	ArrayColumnSeparator string
+	ArrayRowSeparator    string
+	Names                map[string]string
+}
+
+// formulaLocales defined the built-in formula locales.
+var formulaLocales = struct {
+	sync.RWMutex
+	locales map[string]*FormulaLocale
+}{locales: map[string]*FormulaLocale{
+	"en-US": {ArgSeparator: ",", DecimalSeparator: ".", ArrayColumnSeparator: ",", ArrayRowSeparator: ";"},
+	"de-DE": {ArgSeparator: ";", DecimalSeparator: ",", ArrayColumnSeparator: ".", ArrayRowSeparator: ";", Names: map[string]string{
+		"#N/A": "#NV", "#NUM!": "#ZAHL!", "#REF!": "#BEZUG!", "#VALUE!": "#WERT!",
+		"ABS": "ABS", "AND": "UND", "ARABIC": "ARABISCH", "AVERAGE": "MITTELWERT", "CEILING": "OBERGRENZE",
+		"COMBIN": "KOMBINATIONEN", "CONCATENATE": "VERKETTEN", "COUNT": "ANZAHL", "COUNTA": "ANZAHL2",
+		"COUNTIF": "ZÄHLENWENN", "DATE": "DATUM", "DAY": "TAG", "DEGREES": "GRAD", "EVEN": "GERADE",
+		"FACT": "FAKULTÄT", "FALSE": "FALSCH", "FLOOR": "UNTERGRENZE", "GCD": "GGT", "HLOOKUP": "WVERWEIS",
+		"IF": "WENN", "IFERROR": "WENNFEHLER", "INDEX": "INDEX", "INT": "GANZZAHL", "ISBLANK": "ISTLEER",
+		"ISERR": "ISTFEHL", "ISERROR": "ISTFEHLER", "ISEVEN": "ISTGERADE", "ISNA": "ISTNV",
+		"ISNONTEXT": "ISTKTEXT", "ISNUMBER": "ISTZAHL", "ISODD": "ISTUNGERADE", "LCM": "KGV", "LEFT": "LINKS",
+		"LEN": "LÄNGE", "LOWER": "KLEIN", "MATCH": "VERGLEICH", "MAX": "MAX", "MDETERM": "MDET",
+		"MEDIAN": "MEDIAN", "MID": "TEIL", "MIN": "MIN", "MOD": "REST", "MONTH": "MONAT", "MROUND": "VRUNDEN",
+		"MULTINOMIAL": "POLYNOMIAL", "NA": "NV", "NOT": "NICHT", "NOW": "JETZT", "ODD": "UNGERADE", "OR": "ODER",
+		"POWER": "POTENZ", "PRODUCT": "PRODUKT", "RADIANS": "BOGENMASS", "RAND": "ZUFALLSZAHL",
+		"RANDBETWEEN": "ZUFALLSBEREICH", "RIGHT": "RECHTS", "ROMAN": "RÖMISCH", "ROUND": "RUNDEN",
+		"ROUNDDOWN": "ABRUNDEN", "ROUNDUP": "AUFRUNDEN", "SIGN": "VORZEICHEN", "SQRT": "WURZEL",
+		"SQRTPI": "WURZELPI", "SUM": "SUMME", "SUMIF": "SUMMEWENN", "SUMPRODUCT": "SUMMENPRODUKT",
+		"SUMSQ": "QUADRATESUMME", "TEXT": "TEXT", "TODAY": "HEUTE", "TRIM": "GLÄTTEN", "TRUE": "WAHR",
+		"TRUNC": "KÜRZEN", "UPPER": "GROSS", "VALUE": "WERT", "VLOOKUP": "SVERWEIS", "YEAR": "JAHR",
+	}},
+	"es-ES": {ArgSeparator: ";", DecimalSeparator: ",", ArrayColumnSeparator: "\\", ArrayRowSeparator: ";", Names: map[string]string{
+		"#DIV/0!": "#¡DIV/0!", "#NAME?": "#¿NOMBRE?", "#NULL!": "#¡NULO!",
+		"#NUM!": "#¡NUM!", "#REF!": "#¡REF!", "#VALUE!": "#¡VALOR!",
+		"AND": "Y", "AVERAGE": "PROMEDIO", "CONCATENATE": "CONCATENAR", "COUNT": "CONTAR", "COUNTA": "CONTARA",
+		"COUNTIF": "CONTAR.SI", "DATE": "FECHA", "DAY": "DIA", "EVEN": "REDONDEA.PAR", "FALSE": "FALSO",
+		"HLOOKUP": "BUSCARH", "IF": "SI", "IFERROR": "SI.ERROR", "INDEX": "INDICE", "INT": "ENTERO",
+		"ISBLANK": "ESBLANCO", "ISERROR": "ESERROR", "ISNA": "ESNOD", "ISNUMBER": "ESNUMERO",
+		"LEFT": "IZQUIERDA", "LEN": "LARGO", "LOWER": "MINUSC", "MATCH": "COINCIDIR", "MEDIAN": "MEDIANA",
+		"MID": "EXTRAE", "MOD": "RESIDUO", "MONTH": "MES", "NA": "NOD", "NOT": "NO", "NOW": "AHORA",
+		"ODD": "REDONDEA.IMPAR", "OR": "O", "POWER": "POTENCIA", "PRODUCT": "PRODUCTO", "RAND": "ALEATORIO",
+		"RANDBETWEEN": "ALEATORIO.ENTRE", "RIGHT": "DERECHA", "ROUND": "REDONDEAR", "ROUNDDOWN": "REDONDEAR.MENOS",
+		"ROUNDUP": "REDONDEAR.MAS", "SIGN": "SIGNO", "SQRT": "RAIZ", "SUM": "SUMA", "SUMIF": "SUMAR.SI",
+		"SUMPRODUCT": "SUMAPRODUCTO", "TEXT": "TEXTO", "TODAY": "HOY", "TRIM": "ESPACIOS", "TRUE": "VERDADERO",
+		"TRUNC": "TRUNCAR", "UPPER": "MAYUSC", "VALUE": "VALOR", "VLOOKUP": "BUSCARV", "YEAR": "AÑO",
+	}},
+	"fr-FR": {ArgSeparator: ";", DecimalSeparator: ",", ArrayColumnSeparator: ".", ArrayRowSeparator: ";", Names: map[string]string{
+		"#NAME?": "#NOM?", "#NUM!": "#NOMBRE!", "#VALUE!": "#VALEUR!",
+		"AND": "ET", "AVERAGE": "MOYENNE", "CEILING": "PLAFOND", "CONCATENATE": "CONCATENER", "COUNT": "NB",
+		"COUNTA": "NBVAL", "COUNTIF": "NB.SI", "DAY": "JOUR", "DEGREES": "DEGRES", "EVEN": "PAIR",
+		"FALSE": "FAUX", "FLOOR": "PLANCHER", "GCD": "PGCD", "HLOOKUP": "RECHERCHEH", "IF": "SI",
+		"IFERROR": "SIERREUR", "INT": "ENT", "ISBLANK": "ESTVIDE", "ISERR": "ESTERR", "ISERROR": "ESTERREUR",
+		"ISEVEN": "EST.PAIR", "ISNA": "ESTNA", "ISNONTEXT": "ESTNONTEXTE", "ISNUMBER": "ESTNUM",
+		"ISODD": "EST.IMPAIR", "LCM": "PPCM", "LEFT": "GAUCHE", "LEN": "NBCAR", "LOWER": "MINUSCULE",
+		"MATCH": "EQUIV", "MDETERM": "DETERMAT", "MEDIAN": "MEDIANE", "MID": "STXT", "MONTH": "MOIS",
+		"MROUND": "ARRONDI.AU.MULTIPLE", "NOT": "NON", "NOW": "MAINTENANT", "ODD": "IMPAIR", "OR": "OU",
+		"POWER": "PUISSANCE", "PRODUCT": "PRODUIT", "RAND": "ALEA", "RANDBETWEEN": "ALEA.ENTRE.BORNES",
+		"RIGHT": "DROITE", "ROUND": "ARRONDI", "ROUNDDOWN": "ARRONDI.INF", "ROUNDUP": "ARRONDI.SUP",
+		"SIGN": "SIGNE", "SQRT": "RACINE", "SQRTPI": "RACINE.PI", "SUM": "SOMME", "SUMIF": "SOMME.SI",
+		"SUMPRODUCT": "SOMMEPROD", "SUMSQ": "SOMME.CARRES", "TEXT": "TEXTE", "TODAY": "AUJOURDHUI",
+		"TRIM": "SUPPRESPACE", "TRUE": "VRAI", "TRUNC": "TRONQUE", "UPPER": "MAJUSCULE", "VALUE": "CNUM",
+		"VLOOKUP": "RECHERCHEV", "YEAR": "ANNEE",
+	}},
+	"it-IT": {ArgSeparator: ";", DecimalSeparator: ",", ArrayColumnSeparator: ".", ArrayRowSeparator: ";", Names: map[string]string{
+		"#N/A": "#N/D", "#NAME?": "#NOME?", "#NULL!": "#NULLO!", "#REF!": "#RIF!", "#VALUE!": "#VALORE!",
+		"ABS": "ASS", "AND": "E", "AVERAGE": "MEDIA", "CONCATENATE": "CONCATENA", "COUNT": "CONTA.NUMERI",
+		"COUNTA": "CONTA.VALORI", "COUNTIF": "CONTA.SE", "DATE": "DATA", "DAY": "GIORNO", "FALSE": "FALSO",
+		"HLOOKUP": "CERCA.ORIZZ", "IF": "SE", "IFERROR": "SE.ERRORE", "INDEX": "INDICE", "ISBLANK": "VAL.VUOTO",
+		"ISERROR": "VAL.ERRORE", "ISNA": "VAL.NON.DISP", "ISNUMBER": "VAL.NUMERO", "LEFT": "SINISTRA",
+		"LEN": "LUNGHEZZA", "LOWER": "MINUSC", "MATCH": "CONFRONTA", "MEDIAN": "MEDIANA", "MID": "STRINGA.ESTRAI",
+		"MOD": "RESTO", "MONTH": "MESE", "NA": "NON.DISP", "NOT": "NON", "NOW": "ADESSO", "OR": "O",
+		"PI": "PI.GRECO", "POWER": "POTENZA", "PRODUCT": "PRODOTTO", "RAND": "CASUALE",
+		"RANDBETWEEN": "CASUALE.TRA", "RIGHT": "DESTRA", "ROUND": "ARROTONDA", "ROUNDDOWN": "ARROTONDA.PER.DIF",
+		"ROUNDUP": "ARROTONDA.PER.ECC", "SQRT": "RADQ", "SUM": "SOMMA", "SUMIF": "SOMMA.SE",
+		"SUMPRODUCT": "MATR.SOMMA.PRODOTTO", "TEXT": "TESTO", "TODAY": "OGGI", "TRIM": "ANNULLA.SPAZI",
+		"TRUE": "VERO", "TRUNC": "TRONCA", "UPPER": "MAIUSC", "VALUE": "VALORE", "VLOOKUP": "CERCA.VERT",
+		"YEAR": "ANNO",
+	}},
+}}
+
+// SetFormulaLocale provides a function to add or replace the formula locale
+// by given locale name, which can be used by the LocalizeFormula,
+// DelocalizeFormula functions and the Locale option of the SetCellFormula
+// and GetCellFormula. The built-in locales are en-US, de-DE, es-ES, fr-FR
+// and it-IT. For example, add a formula locale for Dutch:
+//
+//    excelize.SetFormulaLocale("nl-NL", &excelize.FormulaLocale{
+//        ArgSeparator:         ";",
+//        DecimalSeparator:     ",",
+//        ArrayColumnSeparator: ".",
+//        ArrayRowSeparator:    ";",
+//        Names:                map[string]string{"SUM": "SOM", "IF": "ALS"},
+//    })
+//
+func SetFormulaLocale(name string, locale *FormulaLocale) {
+	formulaLocales.Lock()
+	defer formulaLocales.Unlock()
+	formulaLocales.locales[name] = locale
+}
+
+// getFormulaLocale provides a function to get the formula locale by given
+// locale name.
+func getFormulaLocale(name string) (*FormulaLocale, error) {
+	formulaLocales.RLock()
+	defer formulaLocales.RUnlock()
+	locale, ok := formulaLocales.locales[name]
+	if !ok || locale == nil {
+		return nil, fmt.Errorf("unsupported formula locale %s", name)
+	}
+	return locale, nil
+}
+
+// LocalizeFormula provides a function to convert the en-US formula to the
+// localized formula of the given locale, covering the function names,
+// logical values, error values, argument separators, decimal marks and the
+// separators of the array constants. For example:
+//
+//    formula, err := excelize.LocalizeFormula("=SUM(A1,1.5)", "de-DE")
+//
+// The formula will be =SUMME(A1;1,5).
+//
+func LocalizeFormula(formula, locale string) (string, error) {
+	to, err := getFormulaLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	from, _ := getFormulaLocale("en-US")
+	return translateFormula(formula, from, to, false), nil
+}
+
+// DelocalizeFormula provides a function to convert the localized formula of
+// the given locale to the en-US formula which stored in the spreadsheet. For
+// example:
+//
+//    formula, err := excelize.DelocalizeFormula("=SUMME(A1;1,5)", "de-DE")
+//
+// The formula will be =SUM(A1,1.5).
+//
+func DelocalizeFormula(formula, locale string) (string, error) {
+	from, err := getFormulaLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	to, _ := getFormulaLocale("en-US")
+	return translateFormula(formula, from, to, true), nil
+}
+
+// isFormulaNameRune checks if the given character can be a part of the
+// function name, defined name or reference in the formula.
+func isFormulaNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '$'
+}
+
+// isFormulaErrorRune checks if the given character can be a part of the
+// error value in the formula, such as #N/A, #DIV/0! or #¿NOMBRE?.
+func isFormulaErrorRune(r rune) bool {
+	return isFormulaNameRune(r) || strings.ContainsRune("/!?¡¿", r)
+}
+
+// newFormulaNameTable provides a function to build the lookup table of the
+// names by given names map of the formula locale, which maps the upper case
+// source names to the target names. The reverse specifies the translate
+// direction of the names map, which maps the en-US names to the localized
+// names. The names are visited in the sorted order of the en-US names, and
+// the first one wins if multiple names mapped to the same localized name.
+func newFormulaNameTable(names map[string]string, reverse bool) map[string]string {
+	keys := make([]string, 0, len(names))
+	for en := range names {
+		keys = append(keys, en)
+	}
+	sort.Strings(keys)
+	table := make(map[string]string, len(keys))
+	for _, en := range keys {
+		source, target := en, names[en]
+		if reverse {
+			source, target = target, en
+		}
+		if _, ok := table[strings.ToUpper(source)]; !ok {
+			table[strings.ToUpper(source)] = target
+		}
+	}
+	return table
+}
+
+// translateName provides a function to translate the function name, logical
+// value or error value by given lookup table of the names. The reverse
+// specifies the translate direction of the lookup table. Other names will be
+// kept as is.
+func translateName(name string, isFunc bool, table map[string]string, reverse bool) string {
+	var prefix string
+	if strings.HasPrefix(strings.ToLower(name), "_xlfn.") {
+		prefix, name = name[:6], name[6:]
+	}
+	target, ok := table[strings.ToUpper(name)]
+	if !ok {
+		return prefix + name
+	}
+	en := name
+	if reverse {
+		en = target
+	}
+	if !isFunc && en != "TRUE" && en != "FALSE" && !strings.HasPrefix(en, "#") {
+		return prefix + name
+	}
+	return prefix + target
+}
+
+// scanFormulaLiteral provides a function to get the end index of the string
+// literal, quoted sheet name or bracketed text which starts at the given
+// index of the formula.
+func scanFormulaLiteral(formula string, start int) int {
+	end := map[byte]byte{'"': '"', '\'': '\'', '[': ']'}[formula[start]]
+	for i := start + 1; i < len(formula); i++ {
+		if formula[i] != end {
+			continue
+		}
+		if end != ']' && i+1 < len(formula) && formula[i+1] == end {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(formula)
+}
+
+// scanFormulaNumber provides a function to get the end index of the number
+// which starts at the given index of the formula, and the number with the
+// translated decimal mark.
+func scanFormulaNumber(formula string, start int, from, to *FormulaLocale) (int, string) {
+	var b strings.Builder
+	i := start
+	for i < len(formula) {
+		if isFormulaDecimal(formula, i, from) {
+			b.WriteString(to.DecimalSeparator)
+			i += len(from.DecimalSeparator)
+			continue
+		}
+		if formula[i] < '0' || formula[i] > '9' {
+			break
+		}
+		b.WriteByte(formula[i])
+		i++
+	}
+	return i, b.String()
+}
+
+// isFormulaDecimal checks if the decimal mark of the given formula locale
+// followed by a digit at the given index of the formula.
+func isFormulaDecimal(formula string, i int, locale *FormulaLocale) bool {
+	next := i + len(locale.DecimalSeparator)
+	return strings.HasPrefix(formula[i:], locale.DecimalSeparator) &&
+		next < len(formula) && formula[next] >= '0' && formula[next] <= '9'
+}
+
+// translateFormula provides a function to convert the formula between the
+// given source and target formula locales.
+func translateFormula(formula string, from, to *FormulaLocale, reverse bool) string {
+	var (
+		b     strings.Builder
+		depth int
+		names = to.Names
+	)
+	if reverse {
+		names = from.Names
+	}
+	table := newFormulaNameTable(names, reverse)
+	for i := 0; i < len(formula); {
+		c := formula[i]
+		switch {
+		case c == '"' || c == '\'' || c == '[':
+			end := scanFormulaLiteral(formula, i)
+			b.WriteString(formula[i:end])
+			i = end
+			continue
+		case c == '#':
+			end := i + 1
+			for end < len(formula) {
+				r, size := utf8.DecodeRuneInString(formula[end:])
+				if !isFormulaErrorRune(r) {
+					break
+				}
+				end += size
+			}
+			b.WriteString(translateName(formula[i:end], false, table, reverse))
+			i = end
+			continue
+		case c >= '0' && c <= '9' || isFormulaDecimal(formula, i, from):
+			end, number := scanFormulaNumber(formula, i, from, to)
+			b.WriteString(number)
+			i = end
+			continue
+		}
+		if r, size := utf8.DecodeRuneInString(formula[i:]); unicode.IsLetter(r) || r == '_' || r == '$' {
+			end := i + size
+			for end < len(formula) {
+				r, size := utf8.DecodeRuneInString(formula[end:])
+				if !isFormulaNameRune(r) {
+					break
+				}
+				end += size
+			}
+			isFunc := end < len(formula) && formula[end] == '('
+			isRef := end < len(formula) && strings.IndexByte("!:", formula[end]) != -1 ||
+				i > 0 && strings.IndexByte("!:", formula[i-1]) != -1
+			name := formula[i:end]
+			if !isRef {
+				name = translateName(name, isFunc, table, reverse)
+			}
+			b.WriteString(name)
+			i = end
+			continue
+		}
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case depth > 0 && strings.HasPrefix(formula[i:], from.ArrayColumnSeparator):
+			b.WriteString(to.ArrayColumnSeparator)
+			i += len(from.ArrayColumnSeparator)
+			continue
+		case depth > 0 && strings.HasPrefix(formula[i:], from.ArrayRowSeparator):
+			b.WriteString(to.ArrayRowSeparator)
+			i += len(from.ArrayRowSeparator)
+			continue
+		case strings.HasPrefix(formula[i:], from.ArgSeparator):
+			b.WriteString(to.ArgSeparator)
+			i += len(from.ArgSeparator)
+			continue
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package excelize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalizeFormula(t *testing.T) {
+	for _, c := range []struct{ locale, formula, localized string }{
+		{"de-DE", "=SUM(A1,1.5)", "=SUMME(A1;1,5)"},
+		{"de-DE", "=IF(Sheet1!A1>0.5,TRUE,FALSE)", "=WENN(Sheet1!A1>0,5;WAHR;FALSCH)"},
+		{"de-DE", `=COUNTIF('Sum, Data'!A1:A5,"a,b")+.25`, `=ZÄHLENWENN('Sum, Data'!A1:A5;"a,b")+,25`},
+		{"de-DE", "=SUM({1.5,2;3,4})*_xlfn.ROUNDUP(1,0)", "=SUMME({1,5.2;3.4})*_xlfn.AUFRUNDEN(1;0)"},
+		{"de-DE", "=ISNA(#N/A)+SUM(Table1[Col.1],$B$1:$B$2,1:1)", "=ISTNV(#NV)+SUMME(Table1[Col.1];$B$1:$B$2;1:1)"},
+		{"de-DE", "=IFERROR(1/0,#VALUE!)&#DIV/0!&Sheet1!#REF!", "=WENNFEHLER(1/0;#WERT!)&#DIV/0!&Sheet1!#BEZUG!"},
+		{"es-ES", "=SUM({1.5,2;3,4})", `=SUMA({1,5\2;3\4})`},
+		{"es-ES", "=IF(ISERROR(A1),#NAME?,#NULL!)", "=SI(ESERROR(A1);#¿NOMBRE?;#¡NULO!)"},
+		{"fr-FR", "=IFERROR(A1,#NUM!)", "=SIERREUR(A1;#NOMBRE!)"},
+		{"fr-FR", "=IF(AND(A1,B1),1,0)", "=SI(ET(A1;B1);1;0)"},
+		{"it-IT", "=IFERROR(VLOOKUP(A1,B1:C2,2,FALSE),PI())", "=SE.ERRORE(CERCA.VERT(A1;B1:C2;2;FALSO);PI.GRECO())"},
+		{"en-US", "=SUM(A1,1.5)", "=SUM(A1,1.5)"},
+	} {
+		localized, err := LocalizeFormula(c.formula, c.locale)
+		assert.NoError(t, err)
+		assert.Equal(t, c.localized, localized)
+		formula, err := DelocalizeFormula(c.localized, c.locale)
+		assert.NoError(t, err)
+		assert.Equal(t, c.formula, formula)
+	}
+	// Test delocalize formula with lower case function name.
+	formula, err := DelocalizeFormula("=summe(1;wahr;#nv)", "de-DE")
+	assert.NoError(t, err)
+	assert.Equal(t, "=SUM(1,TRUE,#N/A)", formula)
+
+	// Test convert formula with user defined formula locale.
+	SetFormulaLocale("nl-NL", &FormulaLocale{
+		ArgSeparator:         ";",
+		DecimalSeparator:     ",",
+		ArrayColumnSeparator: ".",
+		ArrayRowSeparator:    ";",
+		Names:                map[string]string{"SUM": "SOM", "CEILING.MATH": "AFRONDEN.BOVEN", "CEILING": "AFRONDEN.BOVEN"},
+	})
+	localized, err := LocalizeFormula("=SUM(0.5,1)", "nl-NL")
+	assert.NoError(t, err)
+	assert.Equal(t, "=SOM(0,5;1)", localized)
+	// Test delocalize formula with the localized name mapped by multiple names.
+	for i := 0; i < 10; i++ {
+		formula, err = DelocalizeFormula("=AFRONDEN.BOVEN(1,5)", "nl-NL")
+		assert.NoError(t, err)
+		assert.Equal(t, "=CEILING(1.5)", formula)
+	}
+	SetFormulaLocale("nl-NL", nil)
+
+	// Test convert formula with unsupported formula locale.
+	_, err = LocalizeFormula("=SUM(1,2)", "nl-NL")
+	assert.EqualError(t, err, "unsupported formula locale nl-NL")
+	_, err = DelocalizeFormula("=SUM(1,2)", "xx")
+	assert.EqualError(t, err, "unsupported formula locale xx")
+}
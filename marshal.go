@@ -0,0 +1,650 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnmarshalOptions directly maps the options of the UnmarshalRows. HeaderRow
+// specifies the row number of the header row, default is the first row.
+// Required specifies the column names which must exist in the header row.
+// Parsers specifies the custom parser functions by given column names, the
+// function receives the cell of the column and returns the value which can
+// be assigned to the field.
+type UnmarshalOptions struct {
+	HeaderRow int
+	Required  []string
+	Parsers   map[string]func(cell RowCell) (interface{}, error)
+}
+
+// ErrUnmarshalCell defines an error of unmarshal the cell into the field of
+// the struct.
+type ErrUnmarshalCell struct {
+	Sheet  string
+	Cell   string
+	Column string
+	Field  string
+	Err    error
+}
+
+func (err ErrUnmarshalCell) Error() string {
+	return fmt.Sprintf("cannot unmarshal cell %s!%s of column %q into field %s: %v", err.Sheet, err.Cell, err.Column, err.Field, err.Err)
+}
+
+// Unwrap returns the underlying error of unmarshal the cell.
+func (err ErrUnmarshalCell) Unwrap() error {
+	return err.Err
+}
+
+// structField directly maps the field of the struct which mapped to the
+// column of the worksheet.
+type structField struct {
+	index  int
+	name   string
+	column string
+	numFmt string
+	style  string
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// getStructFields provides a function to get the fields of the struct which
+// mapped to the columns by the xlsx tags. The exported fields without the
+// tag are mapped to the column which has the same name as the field, and the
+// fields with tag "-" are ignored. The number format of the column can be
+// specified by the "numfmt" option of the xlsx tag with the built-in number
+// format index or custom number format, and the style of the column can be
+// specified by the xlsxstyle tag with the JSON format set of NewStyle. For
+// example:
+//
+//    type Employee struct {
+//        Name     string    `xlsx:"Full Name" xlsxstyle:"{\"font\":{\"bold\":true}}"`
+//        Birthday time.Time `xlsx:"Date of Birth,numfmt=14"`
+//        Salary   float64   `xlsx:",numfmt=#,##0.00"`
+//        Note     string    `xlsx:"-"`
+//    }
+//
+func getStructFields(typ reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		f := structField{index: i, name: field.Name, column: field.Name, style: field.Tag.Get("xlsxstyle")}
+		if tag, ok := field.Tag.Lookup("xlsx"); ok {
+			opts := strings.SplitN(tag, ",", 2)
+			if opts[0] == "-" {
+				continue
+			}
+			if opts[0] != "" {
+				f.column = opts[0]
+			}
+			if len(opts) == 2 && strings.HasPrefix(opts[1], "numfmt=") {
+				f.numFmt = strings.TrimPrefix(opts[1], "numfmt=")
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// UnmarshalRows provides a function to read the rows of the worksheet into
+// the slice of structs by given worksheet name, pointer to the slice and
+// options. The header row is used to map the columns to the fields of the
+// struct by the xlsx tags. The cells will be converted to the type of the
+// fields as the reverse of SetCellValue: integers, floats, booleans,
+// strings, time.Time in the 1900 or 1904 date system of the workbook,
+// time.Duration, the pointers of these types and the types which implement
+// encoding.TextUnmarshaler are supported. The empty rows will be skipped,
+// and the empty cells keep the zero value of the fields. An ErrUnmarshalCell
+// error will be returned with the cell coordinates if the cell can't be
+// converted. For example, read the employees on Sheet1:
+//
+//    type Employee struct {
+//        Name     string    `xlsx:"Full Name"`
+//        Birthday time.Time `xlsx:"Date of Birth"`
+//        Salary   float64
+//    }
+//    var employees []Employee
+//    err := f.UnmarshalRows("Sheet1", &employees, excelize.UnmarshalOptions{
+//        Required: []string{"Full Name"},
+//    })
+//
+func (f *File) UnmarshalRows(sheet string, dst interface{}, opts ...UnmarshalOptions) error {
+	var options UnmarshalOptions
+	for _, o := range opts {
+		options = o
+	}
+	if options.HeaderRow < 1 {
+		options.HeaderRow = 1
+	}
+	slice := reflect.ValueOf(dst)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return errors.New("dst must be a pointer to a slice of structs")
+	}
+	slice = slice.Elem()
+	elemType, isPtr := slice.Type().Elem(), false
+	if elemType.Kind() == reflect.Ptr {
+		elemType, isPtr = elemType.Elem(), true
+	}
+	if elemType.Kind() != reflect.Struct {
+		return errors.New("dst must be a pointer to a slice of structs")
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var (
+		header map[int]string
+		fields = getStructFields(elemType)
+	)
+	for rows.Next() {
+		cells, err := rows.Cells()
+		if err != nil {
+			return err
+		}
+		if rows.CurrentRow() < options.HeaderRow {
+			continue
+		}
+		if rows.CurrentRow() == options.HeaderRow {
+			if header, err = getHeaderColumns(cells, options); err != nil {
+				return err
+			}
+			continue
+		}
+		elem := reflect.New(elemType).Elem()
+		ok, err := f.unmarshalRow(rows, sheet, cells, header, fields, elem, options)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if isPtr {
+			elem = elem.Addr()
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	if err = rows.Error(); err != nil {
+		return err
+	}
+	if header == nil {
+		return fmt.Errorf("header row %d is not exist", options.HeaderRow)
+	}
+	return nil
+}
+
+// getHeaderColumns provides a function to get the column names by the
+// column number from the cells of the header row, and check the required
+// columns.
+func getHeaderColumns(cells []RowCell, options UnmarshalOptions) (map[int]string, error) {
+	header, names := make(map[int]string), make(map[string]bool)
+	for _, cell := range cells {
+		col, _, err := CellNameToCoordinates(cell.Axis)
+		if err != nil {
+			return header, err
+		}
+		header[col], names[cell.Formatted] = cell.Formatted, true
+	}
+	for _, name := range options.Required {
+		if !names[name] {
+			return header, fmt.Errorf("required column %q is not exist in header row %d", name, options.HeaderRow)
+		}
+	}
+	return header, nil
+}
+
+// unmarshalRow provides a function to set the fields of the struct by given
+// cells of the row, and returns false if the row is empty.
+func (f *File) unmarshalRow(rows *Rows, sheet string, cells []RowCell, header map[int]string, fields []structField, elem reflect.Value, options UnmarshalOptions) (bool, error) {
+	columns := make(map[string]RowCell, len(cells))
+	var empty = true
+	for _, cell := range cells {
+		col, _, err := CellNameToCoordinates(cell.Axis)
+		if err != nil {
+			return false, err
+		}
+		if name, ok := header[col]; ok {
+			columns[name] = cell
+			empty = empty && cell.Value == ""
+		}
+	}
+	if empty {
+		return false, nil
+	}
+	for _, field := range fields {
+		cell, ok := columns[field.column]
+		if !ok || (cell.Value == "" && options.Parsers[field.column] == nil) {
+			continue
+		}
+		var err error
+		if parser, ok := options.Parsers[field.column]; ok {
+			err = setParsedField(elem.Field(field.index), cell, parser)
+		} else {
+			err = f.setCellField(rows, elem.Field(field.index), cell)
+		}
+		if err != nil {
+			return false, ErrUnmarshalCell{Sheet: sheet, Cell: cell.Axis, Column: field.column, Field: field.name, Err: err}
+		}
+	}
+	return true, nil
+}
+
+// setParsedField provides a function to set the field by given cell and
+// custom parser function.
+func setParsedField(field reflect.Value, cell RowCell, parser func(cell RowCell) (interface{}, error)) error {
+	value, err := parser(cell)
+	if err != nil || value == nil {
+		return err
+	}
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Type().AssignableTo(field.Type()):
+		field.Set(v)
+	case v.Type().ConvertibleTo(field.Type()) && (field.Kind() != reflect.String || v.Kind() == reflect.String):
+		field.Set(v.Convert(field.Type()))
+	default:
+		return fmt.Errorf("cannot assign %s to %s", v.Type(), field.Type())
+	}
+	return nil
+}
+
+// setCellField provides a function to convert the cell value to the type of
+// the field and set the field.
+func (f *File) setCellField(rows *Rows, field reflect.Value, cell RowCell) error {
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := f.setCellField(rows, ptr.Elem(), cell); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+	switch field.Type() {
+	case timeType:
+		t, err := rows.CellTime(cell)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339Nano, cell.Value); err != nil {
+				return err
+			}
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := parseCellDuration(cell.Value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(cell.Value))
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell.Formatted)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell.Value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := parseCellInt(cell.Value)
+		if err != nil {
+			return err
+		}
+		if field.OverflowInt(n) {
+			return fmt.Errorf("value %s overflows %s", cell.Value, field.Type())
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := parseCellInt(cell.Value)
+		if err != nil {
+			return err
+		}
+		if n < 0 || field.OverflowUint(uint64(n)) {
+			return fmt.Errorf("value %s overflows %s", cell.Value, field.Type())
+		}
+		field.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(cell.Value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// parseCellInt provides a function to parse the integer value of the cell,
+// the integral float value will be accepted.
+func parseCellInt(value string) (int64, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		return n, nil
+	}
+	fn, e := strconv.ParseFloat(value, 64)
+	if e != nil || fn != math.Trunc(fn) || math.Abs(fn) > math.MaxInt64 {
+		return 0, err
+	}
+	return int64(fn), nil
+}
+
+// parseCellDuration provides a function to parse the duration value of the
+// cell, the number of days stored by SetCellValue and the duration string
+// such as "1h30m" are accepted. The number of days will be rounded to the
+// second, which is the precision of the duration stored by SetCellValue.
+func parseCellDuration(value string) (time.Duration, error) {
+	days, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.ParseDuration(value)
+	}
+	return time.Duration(math.Round(days*86400)) * time.Second, nil
+}
+
+// MarshalOptions directly maps the options of the MarshalRows. NoHeader
+// specifies not to write the header row. HeaderStyle specifies the style ID
+// of the header cells. Table specifies to create a table for the written
+// range with the format set TableFormat, see AddTable for details on the
+// table format.
+type MarshalOptions struct {
+	NoHeader    bool
+	HeaderStyle int
+	Table       bool
+	TableFormat string
+}
+
+// marshalCells provides a function to get the rows of the cells to be
+// written by given slice of structs and options. The first row will be the
+// header if not disabled.
+func (f *File) marshalCells(slice interface{}, options MarshalOptions) ([][]Cell, error) {
+	v := reflect.ValueOf(slice)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, errors.New("slice of structs expected")
+	}
+	elemType := v.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, errors.New("slice of structs expected")
+	}
+	if options.NoHeader && options.Table {
+		return nil, errors.New("table requires the header row")
+	}
+	fields := getStructFields(elemType)
+	styles := make([]int, len(fields))
+	for i, field := range fields {
+		styleID, err := f.newFieldStyle(field, elemType.Field(field.index).Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid style of field %s: %v", field.name, err)
+		}
+		styles[i] = styleID
+	}
+	var rows [][]Cell
+	if !options.NoHeader {
+		header := make([]Cell, len(fields))
+		for i, field := range fields {
+			header[i] = Cell{StyleID: options.HeaderStyle, Value: field.column}
+		}
+		rows = append(rows, header)
+	}
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				rows = append(rows, make([]Cell, len(fields)))
+				continue
+			}
+			elem = elem.Elem()
+		}
+		row := make([]Cell, len(fields))
+		for j, field := range fields {
+			value, err := getFieldValue(elem.Field(field.index))
+			if err != nil {
+				return nil, fmt.Errorf("cannot marshal field %s of element %d: %v", field.name, i, err)
+			}
+			row[j] = Cell{StyleID: styles[j], Value: value}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// newFieldStyle provides a function to create the style of the cells by
+// given field. The date and time fields without the number format will use
+// the built-in number format 22 (m/d/yy h:mm), the same as SetCellValue. The
+// style ID will be cached by the style and number format of the field, so
+// marshaling the same structs repeatedly doesn't create the style again.
+func (f *File) newFieldStyle(field structField, typ reflect.Type) (int, error) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if field.style == "" && field.numFmt == "" && typ != timeType {
+		return 0, nil
+	}
+	key := strings.Join([]string{field.style, field.numFmt, strconv.FormatBool(typ == timeType)}, "\x00")
+	if styleID, ok := f.fieldStyles[key]; ok {
+		return styleID, nil
+	}
+	styleID, err := f.newFieldStyleID(field, typ)
+	if err != nil {
+		return styleID, err
+	}
+	if f.fieldStyles == nil {
+		f.fieldStyles = make(map[string]int)
+	}
+	f.fieldStyles[key] = styleID
+	return styleID, err
+}
+
+// newFieldStyleID provides a function to create the style by given field
+// which has the style or number format, and the type of the field.
+func (f *File) newFieldStyleID(field structField, typ reflect.Type) (int, error) {
+	style := &Style{}
+	if field.style != "" {
+		var err error
+		if style, err = parseFormatStyleSet(field.style); err != nil {
+			return 0, err
+		}
+	}
+	if field.numFmt != "" {
+		if numFmt, err := strconv.Atoi(field.numFmt); err == nil {
+			style.NumFmt = numFmt
+		} else {
+			style.CustomNumFmt = &field.numFmt
+		}
+	}
+	if typ == timeType && style.NumFmt == 0 && style.CustomNumFmt == nil {
+		style.NumFmt = 22
+	}
+	return f.NewStyle(style)
+}
+
+// getFieldValue provides a function to get the cell value by given field of
+// the struct. The values of the named basic types will be converted to the
+// underlying types, and the types which implement encoding.TextMarshaler
+// will be written as the text.
+func getFieldValue(v reflect.Value) (interface{}, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Type() {
+	case timeType, durationType:
+		return v.Interface(), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32:
+		return float32(v.Float()), nil
+	case reflect.Float64:
+		return v.Float(), nil
+	}
+	return v.Interface(), nil
+}
+
+// MarshalRows provides a function to write the slice of structs to the
+// worksheet by given worksheet name, starting coordinate, slice and options.
+// The header row will be written with the column names by the xlsx tags of
+// the struct, and each element of the slice will be written as a row. The
+// number formats and styles of the columns are specified by the tags, see
+// UnmarshalRows for details on the tags. For example, write the employees
+// to Sheet1 starting at cell B2, and create a table for the range:
+//
+//    type Employee struct {
+//        Name     string    `xlsx:"Full Name"`
+//        Birthday time.Time `xlsx:"Date of Birth,numfmt=14"`
+//        Salary   float64   `xlsx:",numfmt=#,##0.00"`
+//    }
+//    err := f.MarshalRows("Sheet1", "B2", []Employee{
+//        {Name: "Alice", Birthday: time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC), Salary: 1234.5},
+//    }, excelize.MarshalOptions{Table: true, TableFormat: `{"table_style":"TableStyleMedium2"}`})
+//
+func (f *File) MarshalRows(sheet, startCell string, slice interface{}, opts ...MarshalOptions) error {
+	var options MarshalOptions
+	for _, o := range opts {
+		options = o
+	}
+	col, row, err := CellNameToCoordinates(startCell)
+	if err != nil {
+		return err
+	}
+	if f.getSheetID(sheet) == -1 {
+		return fmt.Errorf("sheet %s is not exist", sheet)
+	}
+	rows, err := f.marshalCells(slice, options)
+	if err != nil {
+		return err
+	}
+	for r, cells := range rows {
+		for c, cell := range cells {
+			axis, err := CoordinatesToCellName(col+c, row+r)
+			if err != nil {
+				return err
+			}
+			if err = f.SetCellValue(sheet, axis, cell.Value); err != nil {
+				return err
+			}
+			if cell.StyleID != 0 {
+				if err = f.SetCellStyle(sheet, axis, axis, cell.StyleID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if !options.Table {
+		return err
+	}
+	vcell, err := getMarshalEndCell(col, row, len(rows), len(rows[0]))
+	if err != nil {
+		return err
+	}
+	return f.AddTable(sheet, startCell, vcell, options.TableFormat)
+}
+
+// getMarshalEndCell provides a function to get the bottom right cell of the
+// written range by given starting coordinates and the size of the range.
+func getMarshalEndCell(col, row, rows, cols int) (string, error) {
+	if cols == 0 {
+		return "", errors.New("struct has no fields to be marshaled")
+	}
+	return CoordinatesToCellName(col+cols-1, row+rows-1)
+}
+
+// MarshalRows writes the slice of structs to the stream rows by given
+// starting coordinate, slice and options. See File.MarshalRows for details
+// on the tags and options. Note that the rows must be written in ascending
+// order and you must call the 'Flush' method to end the streaming writing
+// process. For example:
+//
+//    sw, err := f.NewStreamWriter("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    if err := sw.MarshalRows("A1", employees, excelize.MarshalOptions{Table: true}); err != nil {
+//        fmt.Println(err)
+//    }
+//    if err := sw.Flush(); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (sw *StreamWriter) MarshalRows(startCell string, slice interface{}, opts ...MarshalOptions) error {
+	var options MarshalOptions
+	for _, o := range opts {
+		options = o
+	}
+	col, row, err := CellNameToCoordinates(startCell)
+	if err != nil {
+		return err
+	}
+	rows, err := sw.File.marshalCells(slice, options)
+	if err != nil {
+		return err
+	}
+	for r, cells := range rows {
+		axis, err := CoordinatesToCellName(col, row+r)
+		if err != nil {
+			return err
+		}
+		values := make([]interface{}, len(cells))
+		for c, cell := range cells {
+			values[c] = cell
+		}
+		if err = sw.SetRow(axis, values); err != nil {
+			return err
+		}
+	}
+	if !options.Table {
+		return err
+	}
+	vcell, err := getMarshalEndCell(col, row, len(rows), len(rows[0]))
+	if err != nil {
+		return err
+	}
+	return sw.AddTable(startCell, vcell, options.TableFormat)
+}
@@ -0,0 +1,247 @@
+package excelize
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalRows(t *testing.T) {
+	type employee struct {
+		Name     string        `xlsx:"Full Name"`
+		Age      int           `xlsx:"Age"`
+		Salary   float64       `xlsx:""`
+		Active   bool          `xlsx:"Active"`
+		Birthday time.Time     `xlsx:"Date of Birth"`
+		Shift    time.Duration `xlsx:"Shift"`
+		IP       net.IP        `xlsx:"IP"`
+		Level    *uint8        `xlsx:"Level"`
+		Team     string        `xlsx:"Team"`
+		Note     string        `xlsx:"-"`
+		internal string
+	}
+	birthday := time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFile()
+	for axis, value := range map[string]interface{}{
+		"A2": "Full Name", "B2": "Age", "C2": "Salary", "D2": "Active", "E2": "Date of Birth", "F2": "Shift", "G2": "IP", "H2": "Level", "I2": "Team", "J2": "Note",
+		"A3": "Alice", "B3": 30, "C3": 1234.5, "D3": true, "E3": birthday, "F3": 8 * time.Hour, "G3": "192.168.0.1", "H3": 3, "I3": "North", "J3": "ignored",
+		"A5": "Bob", "B5": 41.0, "D5": false, "E5": "1990-05-01T00:00:00Z", "F5": "1h30m",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", axis, value))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Employees"))
+	level := uint8(3)
+	var employees []employee
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &employees, UnmarshalOptions{
+		HeaderRow: 2,
+		Required:  []string{"Full Name", "Age"},
+		Parsers: map[string]func(cell RowCell) (interface{}, error){
+			"Team": func(cell RowCell) (interface{}, error) {
+				return strings.ToUpper(cell.Value), nil
+			},
+		},
+	}))
+	assert.Equal(t, []employee{
+		{Name: "Alice", Age: 30, Salary: 1234.5, Active: true, Birthday: birthday, Shift: 8 * time.Hour, IP: net.ParseIP("192.168.0.1"), Level: &level, Team: "NORTH"},
+		{Name: "Bob", Age: 41, Birthday: birthday, Shift: 90 * time.Minute},
+	}, employees)
+
+	// Test unmarshal rows into the slice of pointers.
+	var pointers []*employee
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &pointers, UnmarshalOptions{HeaderRow: 2}))
+	assert.Len(t, pointers, 2)
+	assert.Equal(t, "Alice", pointers[0].Name)
+
+	// Test unmarshal rows with required column not exist.
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &employees, UnmarshalOptions{HeaderRow: 2, Required: []string{"ID"}}),
+		`required column "ID" is not exist in header row 2`)
+	// Test unmarshal rows with header row not exist.
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &employees, UnmarshalOptions{HeaderRow: 10}), "header row 10 is not exist")
+	// Test unmarshal rows with invalid destination.
+	for _, dst := range []interface{}{employees, &[]int{}, nil} {
+		assert.EqualError(t, f.UnmarshalRows("Sheet1", dst), "dst must be a pointer to a slice of structs")
+	}
+	// Test unmarshal rows on not exists worksheet.
+	assert.EqualError(t, f.UnmarshalRows("SheetN", &employees), "sheet SheetN is not exist")
+
+	// Test unmarshal rows with invalid cell values.
+	for _, c := range []struct {
+		axis  string
+		value interface{}
+		err   string
+	}{
+		{"B5", "forty", `cannot unmarshal cell Sheet1!B5 of column "Age" into field Age: strconv.ParseInt: parsing "forty": invalid syntax`},
+		{"B5", 41.5, `cannot unmarshal cell Sheet1!B5 of column "Age" into field Age: strconv.ParseInt: parsing "41.5": invalid syntax`},
+		{"H5", 256, `cannot unmarshal cell Sheet1!H5 of column "Level" into field Level: value 256 overflows uint8`},
+		{"H5", -1, `cannot unmarshal cell Sheet1!H5 of column "Level" into field Level: value -1 overflows uint8`},
+		{"C5", "much", `cannot unmarshal cell Sheet1!C5 of column "Salary" into field Salary: strconv.ParseFloat: parsing "much": invalid syntax`},
+		{"D5", "yes", `cannot unmarshal cell Sheet1!D5 of column "Active" into field Active: strconv.ParseBool: parsing "yes": invalid syntax`},
+		{"E5", "yesterday", `cannot unmarshal cell Sheet1!E5 of column "Date of Birth" into field Birthday: parsing time "yesterday" as "2006-01-02T15:04:05.999999999Z07:00": cannot parse "yesterday" as "2006"`},
+		{"F5", "long", `cannot unmarshal cell Sheet1!F5 of column "Shift" into field Shift: time: invalid duration "long"`},
+		{"G5", "host", `cannot unmarshal cell Sheet1!G5 of column "IP" into field IP: invalid IP address: host`},
+	} {
+		f := NewFile()
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]interface{}{"Full Name", "Age", "Salary", "Active", "Date of Birth", "Shift", "IP", "Level"}))
+		assert.NoError(t, f.SetCellValue("Sheet1", c.axis, c.value))
+		var employees []employee
+		err := f.UnmarshalRows("Sheet1", &employees, UnmarshalOptions{HeaderRow: 4})
+		assert.EqualError(t, err, c.err)
+		var cellErr ErrUnmarshalCell
+		assert.True(t, errors.As(err, &cellErr))
+	}
+
+	// Test unmarshal rows with custom parser error and unsupported field type.
+	type record struct {
+		Value string
+		Items []string
+	}
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Value", "Items"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"A", "B"}))
+	var records []record
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &records, UnmarshalOptions{
+		Parsers: map[string]func(cell RowCell) (interface{}, error){
+			"Value": func(cell RowCell) (interface{}, error) { return nil, errors.New("parse error") },
+		},
+	}), `cannot unmarshal cell Sheet1!A2 of column "Value" into field Value: parse error`)
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &records, UnmarshalOptions{
+		Parsers: map[string]func(cell RowCell) (interface{}, error){
+			"Value": func(cell RowCell) (interface{}, error) { return 1, nil },
+		},
+	}), `cannot unmarshal cell Sheet1!A2 of column "Value" into field Value: cannot assign int to string`)
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &records), `cannot unmarshal cell Sheet1!B2 of column "Items" into field Items: unsupported type []string`)
+}
+
+func TestMarshalRows(t *testing.T) {
+	type level int
+	type employee struct {
+		Name     string        `xlsx:"Full Name" xlsxstyle:"{\"font\":{\"bold\":true}}"`
+		Age      level         `xlsx:"Age"`
+		Salary   float64       `xlsx:",numfmt=#,##0.00"`
+		Active   bool          `xlsx:"Active"`
+		Birthday time.Time     `xlsx:"Date of Birth,numfmt=14"`
+		Joined   *time.Time    `xlsx:"Joined"`
+		Shift    time.Duration `xlsx:"Shift"`
+		IP       net.IP        `xlsx:"IP"`
+		Note     string        `xlsx:"-"`
+		internal string
+	}
+	birthday := time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC)
+	employees := []*employee{
+		{Name: "Alice", Age: 30, Salary: 1234.5, Active: true, Birthday: birthday, Joined: &birthday, Shift: 8 * time.Hour, IP: net.ParseIP("192.168.0.1"), Note: "ignored"},
+		{Name: "Bob", Age: 41, Birthday: birthday},
+	}
+	f := NewFile()
+	assert.NoError(t, f.MarshalRows("Sheet1", "B2", employees, MarshalOptions{Table: true, TableFormat: `{"table_name":"Employees"}`}))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		nil,
+		{"", "Full Name", "Age", "Salary", "Active", "Date of Birth", "Joined", "Shift", "IP"},
+		{"", "Alice", "30", "1234.5", "1", "05-01-90", "5/1/90 00:00", "08:00:00", "192.168.0.1"},
+		{"", "Bob", "41", "0", "0", "05-01-90", "", "00:00:00", ""},
+	}, rows)
+	for axis, expected := range map[string]string{"B3": `{"font":{"bold":true}}`, "B2": ""} {
+		styleID, err := f.GetCellStyle("Sheet1", axis)
+		assert.NoError(t, err)
+		if expected == "" {
+			assert.Equal(t, 0, styleID)
+			continue
+		}
+		expectedID, err := f.NewStyle(expected)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedID, styleID)
+	}
+	assert.Equal(t, 1, f.countTables())
+
+	// Test marshal and unmarshal rows round trip.
+	var result []employee
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &result, UnmarshalOptions{HeaderRow: 2}))
+	employees[0].Note = ""
+	assert.Equal(t, []employee{*employees[0], *employees[1]}, result)
+
+	// Test marshal rows repeatedly with the cached styles.
+	xfs := len(f.Styles.CellXfs.Xf)
+	assert.Len(t, f.fieldStyles, 4)
+	assert.NoError(t, f.MarshalRows("Sheet1", "B10", employees))
+	assert.Len(t, f.fieldStyles, 4)
+	assert.Equal(t, xfs, len(f.Styles.CellXfs.Xf))
+	styleID, err := f.GetCellStyle("Sheet1", "B11")
+	assert.NoError(t, err)
+	expectedID, err := f.GetCellStyle("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedID, styleID)
+
+	// Test marshal rows without header and with header style.
+	f = NewFile()
+	assert.NoError(t, f.MarshalRows("Sheet1", "A1", &[]employee{{Name: "Alice"}}, MarshalOptions{NoHeader: true}))
+	value, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", value)
+	styleID, err = f.NewStyle(`{"fill":{"type":"pattern","color":["#E0EBF5"],"pattern":1}}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.MarshalRows("Sheet1", "A3", []employee{}, MarshalOptions{HeaderStyle: styleID}))
+	headerStyle, err := f.GetCellStyle("Sheet1", "H3")
+	assert.NoError(t, err)
+	assert.Equal(t, styleID, headerStyle)
+
+	// Test marshal rows with invalid arguments.
+	assert.EqualError(t, f.MarshalRows("Sheet1", "A", employees), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, f.MarshalRows("SheetN", "A1", employees), "sheet SheetN is not exist")
+	for _, slice := range []interface{}{nil, employee{}, []int{1}} {
+		assert.EqualError(t, f.MarshalRows("Sheet1", "A1", slice), "slice of structs expected")
+	}
+	assert.EqualError(t, f.MarshalRows("Sheet1", "A1", employees, MarshalOptions{NoHeader: true, Table: true}), "table requires the header row")
+	assert.EqualError(t, f.MarshalRows("Sheet1", "A1", []struct{ unexported int }{{}}, MarshalOptions{Table: true}), "struct has no fields to be marshaled")
+	assert.EqualError(t, f.MarshalRows("Sheet1", "A1", []struct {
+		Name string `xlsxstyle:"{"`
+	}{{}}), "invalid style of field Name: unexpected end of JSON input")
+	assert.EqualError(t, f.MarshalRows("Sheet1", "A1", []struct {
+		Value textMarshaler
+	}{{}}), "cannot marshal field Value of element 0: marshal error")
+}
+
+func TestStreamMarshalRows(t *testing.T) {
+	type product struct {
+		Name  string
+		Price float64 `xlsx:"Unit Price,numfmt=2"`
+		Added time.Time
+	}
+	added := time.Date(2020, 10, 18, 12, 0, 0, 0, time.UTC)
+	products := make([]product, 100)
+	for i := range products {
+		products[i] = product{Name: fmt.Sprintf("Product %d", i+1), Price: float64(i) + 0.5, Added: added}
+	}
+	f := NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.MarshalRows("A1", products, MarshalOptions{Table: true}))
+	assert.NoError(t, sw.Flush())
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 101)
+	assert.Equal(t, []string{"Name", "Unit Price", "Added"}, rows[0])
+	assert.Equal(t, []string{"Product 100", "99.50", "10/18/20 12:00"}, rows[100])
+	assert.Equal(t, 1, f.countTables())
+	var result []product
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &result))
+	assert.Equal(t, products, result)
+
+	// Test marshal rows with invalid arguments.
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.EqualError(t, sw.MarshalRows("A", products), `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	assert.EqualError(t, sw.MarshalRows("A1", nil), "slice of structs expected")
+	assert.EqualError(t, sw.MarshalRows("A1", []struct{}{}, MarshalOptions{Table: true}), "struct has no fields to be marshaled")
+}
+
+type textMarshaler struct{}
+
+func (textMarshaler) MarshalText() ([]byte, error) {
+	return nil, errors.New("marshal error")
+}
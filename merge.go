@@ -182,7 +182,7 @@ func (f *File) UnmergeCell(sheet string, hcell, vcell string) error {
 // currently.
 func (f *File) GetMergeCells(sheet string) ([]MergeCell, error) {
 	var mergeCells []MergeCell
-	xlsx, err := f.workSheetReader(sheet)
+	xlsx, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return mergeCells, err
 	}
@@ -0,0 +1,1370 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/efp"
+)
+
+// Source relationship and namespace list of the OpenDocument Spreadsheet.
+const (
+	ODSMimeType     = "application/vnd.oasis.opendocument.spreadsheet"
+	NameSpaceODSOff = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	NameSpaceODSSty = "urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+	NameSpaceODSTxt = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	NameSpaceODSTbl = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	NameSpaceODSFo  = "urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"
+	NameSpaceODSLnk = "http://www.w3.org/1999/xlink"
+)
+
+// odsStyle directly maps the properties of the style:style element of the
+// OpenDocument Spreadsheet, the properties are keyed by the local name of
+// the attributes of the properties elements.
+type odsStyle struct {
+	parent string
+	props  map[string]string
+}
+
+// odsCell directly maps the table:table-cell and table:covered-table-cell
+// element of the OpenDocument Spreadsheet.
+type odsCell struct {
+	covered                   bool
+	repeat, colSpan, rowSpan  int
+	valueType, value, formula string
+	style, text, link         string
+}
+
+// odsReader defined the state of reading the OpenDocument Spreadsheet.
+type odsReader struct {
+	f        *File
+	styles   map[string]*odsStyle
+	styleIDs map[string]int
+	sheet    string
+	sheets   int
+	row, col int
+	cells    int
+	maxCells int
+}
+
+// odsLengthUnits defined the number of points of the length units.
+var odsLengthUnits = map[string]float64{
+	"pt": 1, "pc": 12, "in": 72, "cm": 72 / 2.54, "mm": 72 / 25.4, "px": 0.75,
+}
+
+// odsBorderStyles defined the border styles of the OpenDocument Spreadsheet
+// by the border styles of the cell.
+var odsBorderStyles = map[string]string{
+	"thin":             "0.75pt solid",
+	"medium":           "1.75pt solid",
+	"thick":            "2.5pt solid",
+	"double":           "2.5pt double",
+	"hair":             "0.75pt dotted",
+	"dotted":           "0.75pt dotted",
+	"dashed":           "0.75pt dashed",
+	"dashDot":          "0.75pt dashed",
+	"dashDotDot":       "0.75pt dashed",
+	"slantDashDot":     "1.75pt dashed",
+	"mediumDashed":     "1.75pt dashed",
+	"mediumDashDot":    "1.75pt dashed",
+	"mediumDashDotDot": "1.75pt dashed",
+}
+
+// odsCellRefPattern defined the pattern of the single cell reference.
+var odsCellRefPattern = regexp.MustCompile(`^\$?[A-Za-z]{1,3}\$?[0-9]+$`)
+
+// odsRangeRefPattern defined the pattern of the cell, column or row
+// reference in a range.
+var odsRangeRefPattern = regexp.MustCompile(`^(\$?[A-Za-z]{1,3}\$?[0-9]+|\$?[A-Za-z]{1,3}|\$?[0-9]+)$`)
+
+// isODSPackage provides a function to check if the zip package is an
+// OpenDocument Spreadsheet by the mimetype entry.
+func isODSPackage(zr *zip.Reader) bool {
+	for _, file := range zr.File {
+		if file.Name != "mimetype" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return false
+		}
+		defer rc.Close()
+		b, err := ioutil.ReadAll(io.LimitReader(rc, int64(len(ODSMimeType))+1))
+		return err == nil && strings.TrimSpace(string(b)) == ODSMimeType
+	}
+	return false
+}
+
+// openODS provides a function to read the OpenDocument Spreadsheet package
+// into a new spreadsheet file. The cell values, formulas, styles, merged
+// cells, row heights, column widths and visibility of the sheets will be
+// converted. The number of cells in each table will be checked by the
+// MaxCellsPerSheet of the given options while converting.
+func openODS(zr *zip.Reader, opts Options) (*File, error) {
+	r := &odsReader{f: NewFile(), styles: make(map[string]*odsStyle), styleIDs: make(map[string]int),
+		maxCells: opts.MaxCellsPerSheet,
+	}
+	for _, name := range []string{"styles.xml", "content.xml"} {
+		for _, file := range zr.File {
+			if file.Name != name {
+				continue
+			}
+			rc, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			err = r.read(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return r.f, nil
+}
+
+// read provides a function to read the styles and tables from the XML part
+// of the OpenDocument Spreadsheet.
+func (r *odsReader) read(rd io.Reader) error {
+	d := r.f.xmlNewDecoder(rd)
+	var (
+		style     *odsStyle
+		cells     []odsCell
+		rowRepeat int
+		rowStyle  string
+		rowHidden bool
+	)
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Space + " " + t.Name.Local {
+			case NameSpaceODSSty + " style":
+				style = &odsStyle{parent: getODSAttr(t, NameSpaceODSSty, "parent-style-name"), props: make(map[string]string)}
+				r.styles[getODSAttr(t, NameSpaceODSSty, "name")] = style
+			case NameSpaceODSSty + " table-column-properties", NameSpaceODSSty + " table-row-properties",
+				NameSpaceODSSty + " table-cell-properties", NameSpaceODSSty + " paragraph-properties",
+				NameSpaceODSSty + " text-properties":
+				for _, attr := range t.Attr {
+					if style != nil {
+						style.props[attr.Name.Local] = attr.Value
+					}
+				}
+			case NameSpaceODSTbl + " table":
+				if err = r.newSheet(getODSAttr(t, NameSpaceODSTbl, "name")); err != nil {
+					return err
+				}
+			case NameSpaceODSTbl + " table-column":
+				if err = r.setColumns(t); err != nil {
+					return err
+				}
+			case NameSpaceODSTbl + " table-row":
+				cells, rowRepeat = cells[:0], getODSIntAttr(t, "number-rows-repeated", 1)
+				rowStyle = getODSAttr(t, NameSpaceODSTbl, "style-name")
+				rowHidden = getODSAttr(t, NameSpaceODSTbl, "visibility") == "collapse"
+			case NameSpaceODSTbl + " table-cell", NameSpaceODSTbl + " covered-table-cell":
+				cell, err := readODSCell(d, t)
+				if err != nil {
+					return err
+				}
+				cells = append(cells, cell)
+			}
+		case xml.EndElement:
+			switch t.Name.Space + " " + t.Name.Local {
+			case NameSpaceODSSty + " style":
+				style = nil
+			case NameSpaceODSTbl + " table-row":
+				if err = r.setRows(cells, rowRepeat, rowStyle, rowHidden); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// getODSAttr provides a function to get the attribute value by given
+// element, namespace and local name of the attribute.
+func getODSAttr(t xml.StartElement, space, local string) string {
+	for _, attr := range t.Attr {
+		if attr.Name.Space == space && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// getODSIntAttr provides a function to get the integer attribute value of
+// the table namespace by given element, local name and default value.
+func getODSIntAttr(t xml.StartElement, local string, defaultValue int) int {
+	n, err := strconv.Atoi(getODSAttr(t, NameSpaceODSTbl, local))
+	if err != nil || n < 1 {
+		return defaultValue
+	}
+	return n
+}
+
+// readODSCell provides a function to read the cell element, the text of the
+// paragraphs will be joined by line breaks.
+func readODSCell(d *xml.Decoder, t xml.StartElement) (odsCell, error) {
+	cell := odsCell{
+		covered:   t.Name.Local == "covered-table-cell",
+		repeat:    getODSIntAttr(t, "number-columns-repeated", 1),
+		colSpan:   getODSIntAttr(t, "number-columns-spanned", 1),
+		rowSpan:   getODSIntAttr(t, "number-rows-spanned", 1),
+		valueType: getODSAttr(t, NameSpaceODSOff, "value-type"),
+		formula:   getODSAttr(t, NameSpaceODSTbl, "formula"),
+		style:     getODSAttr(t, NameSpaceODSTbl, "style-name"),
+	}
+	switch cell.valueType {
+	case "date":
+		cell.value = getODSAttr(t, NameSpaceODSOff, "date-value")
+	case "time":
+		cell.value = getODSAttr(t, NameSpaceODSOff, "time-value")
+	case "boolean":
+		cell.value = getODSAttr(t, NameSpaceODSOff, "boolean-value")
+	case "string":
+		cell.value = getODSAttr(t, NameSpaceODSOff, "string-value")
+	default:
+		cell.value = getODSAttr(t, NameSpaceODSOff, "value")
+	}
+	var (
+		text       strings.Builder
+		paragraphs int
+	)
+	for depth := 1; depth > 0; {
+		token, err := d.Token()
+		if err != nil {
+			return cell, err
+		}
+		switch tk := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch tk.Name.Space + " " + tk.Name.Local {
+			case NameSpaceODSOff + " annotation":
+				if err = d.Skip(); err != nil {
+					return cell, err
+				}
+				depth--
+			case NameSpaceODSTxt + " p", NameSpaceODSTxt + " h":
+				if paragraphs++; paragraphs > 1 {
+					text.WriteString("\n")
+				}
+			case NameSpaceODSTxt + " s":
+				n, err := strconv.Atoi(getODSAttr(tk, NameSpaceODSTxt, "c"))
+				if err != nil || n < 1 {
+					n = 1
+				}
+				text.WriteString(strings.Repeat(" ", n))
+			case NameSpaceODSTxt + " tab":
+				text.WriteString("\t")
+			case NameSpaceODSTxt + " line-break":
+				text.WriteString("\n")
+			case NameSpaceODSTxt + " a":
+				cell.link = getODSAttr(tk, NameSpaceODSLnk, "href")
+			}
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			text.Write(tk)
+		}
+	}
+	cell.text = text.String()
+	return cell, nil
+}
+
+// newSheet provides a function to create the worksheet by given name of the
+// table, the first table will use the default worksheet of the file. The
+// invalid characters of the name will be removed, and the empty or
+// duplicate names will be rejected.
+func (r *odsReader) newSheet(name string) error {
+	name = trimSheetName(name)
+	if name == "" || r.sheets > 0 && r.f.GetSheetIndex(name) != -1 {
+		return fmt.Errorf("invalid sheet name %q", name)
+	}
+	if r.sheets++; r.sheets == 1 {
+		r.f.SetSheetName("Sheet1", name)
+	} else {
+		r.f.NewSheet(name)
+	}
+	r.sheet, r.row, r.col, r.cells = name, 0, 0, 0
+	return nil
+}
+
+// setColumns provides a function to set the width and visibility of the
+// columns by given table:table-column element.
+func (r *odsReader) setColumns(t xml.StartElement) error {
+	repeat := getODSIntAttr(t, "number-columns-repeated", 1)
+	start, end := r.col+1, r.col+repeat
+	if r.col += repeat; start > TotalColumns {
+		return nil
+	}
+	if end > TotalColumns {
+		end = TotalColumns
+	}
+	startCol, _ := ColumnNumberToName(start)
+	endCol, _ := ColumnNumberToName(end)
+	if width, ok := parseODSLength(r.getStyleProps(getODSAttr(t, NameSpaceODSTbl, "style-name"))["column-width"]); ok {
+		pixels := width * 4 / 3
+		if err := r.f.SetColWidth(r.sheet, startCol, endCol, math.Round((pixels-5)/7*100)/100); err != nil {
+			return err
+		}
+	}
+	if getODSAttr(t, NameSpaceODSTbl, "visibility") == "collapse" {
+		return r.f.SetColVisible(r.sheet, startCol+":"+endCol, false)
+	}
+	return nil
+}
+
+// setRows provides a function to set the cells of the row by given cells,
+// repeated times, style name and visibility of the row. The trailing blank
+// cells which repeated more than once are used by the applications to fill
+// the default style of the columns, so these cells will be ignored. The row
+// attributes of the repeated blank rows are ignored.
+func (r *odsReader) setRows(cells []odsCell, repeat int, style string, hidden bool) error {
+	cells = trimODSCells(cells)
+	if len(cells) == 0 && repeat > 1 {
+		r.row += repeat
+		return nil
+	}
+	height, hasHeight := parseODSLength(r.getStyleProps(style)["row-height"])
+	for i := 0; i < repeat && r.row < TotalRows; i++ {
+		r.row++
+		if hasHeight {
+			if err := r.f.SetRowHeight(r.sheet, r.row, height); err != nil {
+				return err
+			}
+		}
+		if hidden {
+			if err := r.f.SetRowVisible(r.sheet, r.row, false); err != nil {
+				return err
+			}
+		}
+		col := 0
+		for _, cell := range cells {
+			if cell.covered || cell.style == "" && cell.isBlank() {
+				col += cell.repeat
+				continue
+			}
+			for j := 0; j < cell.repeat && col < TotalColumns; j++ {
+				col++
+				if err := r.setCell(cell, col); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// trimODSCells provides a function to remove the trailing blank cells of the
+// row, the styled blank cell will be kept if it is not repeated.
+func trimODSCells(cells []odsCell) []odsCell {
+	for n := len(cells); n > 0; n-- {
+		if cell := cells[n-1]; !cell.covered && (!cell.isBlank() || cell.style != "" && cell.repeat == 1) {
+			return cells[:n]
+		}
+	}
+	return nil
+}
+
+// isBlank provides a function to check if the cell has no value, text,
+// formula, hyperlink and merged range.
+func (c odsCell) isBlank() bool {
+	return c.valueType == "" && c.text == "" && c.formula == "" && c.link == "" && c.colSpan < 2 && c.rowSpan < 2
+}
+
+// setCell provides a function to set the value, formula, style, hyperlink
+// and merged range of the cell by given cell and column number.
+func (r *odsReader) setCell(cell odsCell, col int) error {
+	if cell.covered {
+		return nil
+	}
+	if r.cells++; r.maxCells > 0 && r.cells > r.maxCells {
+		return newMaxCellsError(r.sheet, r.maxCells)
+	}
+	axis, err := CoordinatesToCellName(col, r.row)
+	if err != nil {
+		return err
+	}
+	var numFmt int
+	switch cell.valueType {
+	case "float", "currency":
+		n, err := strconv.ParseFloat(cell.value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value %q of cell %s!%s", cell.value, r.sheet, axis)
+		}
+		err = r.f.SetCellFloat(r.sheet, axis, n, -1, 64)
+	case "percentage":
+		n, err := strconv.ParseFloat(cell.value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value %q of cell %s!%s", cell.value, r.sheet, axis)
+		}
+		numFmt, err = 10, r.f.SetCellFloat(r.sheet, axis, n, -1, 64)
+	case "date":
+		t, layout, err := parseODSDate(cell.value)
+		if err != nil {
+			return fmt.Errorf("invalid date value %q of cell %s!%s", cell.value, r.sheet, axis)
+		}
+		if numFmt = 22; layout == "2006-01-02" {
+			numFmt = 14
+		}
+		err = r.f.SetCellValue(r.sheet, axis, t)
+	case "time":
+		d, err := parseODSDuration(cell.value)
+		if err != nil {
+			return fmt.Errorf("invalid time value %q of cell %s!%s", cell.value, r.sheet, axis)
+		}
+		numFmt, err = 21, r.f.SetCellFloat(r.sheet, axis, d.Seconds()/86400, -1, 64)
+	case "boolean":
+		err = r.f.SetCellBool(r.sheet, axis, cell.value == "true")
+	default:
+		if cell.text != "" || cell.value != "" {
+			value := cell.text
+			if value == "" {
+				value = cell.value
+			}
+			err = r.f.SetCellStr(r.sheet, axis, value)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if cell.formula != "" {
+		if err = r.f.SetCellFormula(r.sheet, axis, fromOpenFormula(cell.formula)); err != nil {
+			return err
+		}
+	}
+	if err = r.setCellStyle(axis, cell.style, numFmt); err != nil {
+		return err
+	}
+	if cell.link != "" {
+		linkType := "External"
+		if strings.HasPrefix(cell.link, "#") {
+			linkType, cell.link = "Location", fromOpenFormula("["+strings.TrimPrefix(cell.link, "#")+"]")
+		}
+		if err = r.f.SetCellHyperLink(r.sheet, axis, cell.link, linkType); err != nil {
+			return err
+		}
+	}
+	if cell.colSpan > 1 || cell.rowSpan > 1 {
+		end, err := CoordinatesToCellName(col+cell.colSpan-1, r.row+cell.rowSpan-1)
+		if err != nil {
+			return err
+		}
+		return r.f.MergeCell(r.sheet, axis, end)
+	}
+	return nil
+}
+
+// setCellStyle provides a function to set the style of the cell by given
+// style name and number format.
+func (r *odsReader) setCellStyle(axis, name string, numFmt int) error {
+	key := name + "|" + strconv.Itoa(numFmt)
+	styleID, ok := r.styleIDs[key]
+	if !ok {
+		style := getODSCellStyle(r.getStyleProps(name))
+		if numFmt != 0 {
+			if style == nil {
+				style = &Style{}
+			}
+			style.NumFmt = numFmt
+		}
+		if style != nil {
+			var err error
+			if styleID, err = r.f.NewStyle(style); err != nil {
+				return err
+			}
+		}
+		r.styleIDs[key] = styleID
+	}
+	if styleID == 0 {
+		return nil
+	}
+	return r.f.SetCellStyle(r.sheet, axis, axis, styleID)
+}
+
+// getStyleProps provides a function to get the properties of the style with
+// the inherited properties of the parent styles by given style name.
+func (r *odsReader) getStyleProps(name string) map[string]string {
+	var chain []*odsStyle
+	for depth := 0; name != "" && depth < 8; depth++ {
+		style, ok := r.styles[name]
+		if !ok {
+			break
+		}
+		chain, name = append(chain, style), style.parent
+	}
+	props := make(map[string]string)
+	for i := len(chain) - 1; i >= 0; i-- {
+		for k, v := range chain[i].props {
+			props[k] = v
+		}
+	}
+	return props
+}
+
+// getODSCellStyle provides a function to convert the properties of the cell
+// style to the style of the cell, returns nil if there are no cell style
+// properties.
+func getODSCellStyle(props map[string]string) *Style {
+	var style Style
+	font, hasFont := &Font{}, false
+	if weight, err := strconv.Atoi(props["font-weight"]); props["font-weight"] == "bold" || err == nil && weight >= 600 {
+		font.Bold, hasFont = true, true
+	}
+	if fontStyle := props["font-style"]; fontStyle == "italic" || fontStyle == "oblique" {
+		font.Italic, hasFont = true, true
+	}
+	if underline := props["text-underline-style"]; underline != "" && underline != "none" {
+		font.Underline, hasFont = "single", true
+	}
+	if strike := props["text-line-through-style"]; strike != "" && strike != "none" {
+		font.Strike, hasFont = true, true
+	}
+	if color := getODSColor(props["color"]); color != "" {
+		font.Color, hasFont = color, true
+	}
+	if size, ok := parseODSLength(props["font-size"]); ok {
+		font.Size, hasFont = size, true
+	}
+	for _, name := range []string{"font-name", "font-family"} {
+		if family := strings.Trim(props[name], `'"`); family != "" {
+			font.Family, hasFont = family, true
+			break
+		}
+	}
+	if hasFont {
+		style.Font = font
+	}
+	if color := getODSColor(props["background-color"]); color != "" {
+		style.Fill = Fill{Type: "pattern", Pattern: 1, Color: []string{color}}
+	}
+	for _, side := range []string{"left", "right", "top", "bottom"} {
+		border, ok := props["border-"+side]
+		if !ok {
+			border = props["border"]
+		}
+		if b, ok := parseODSBorder(border); ok {
+			b.Type = side
+			style.Border = append(style.Border, b)
+		}
+	}
+	var alignment Alignment
+	switch props["text-align"] {
+	case "start", "left":
+		alignment.Horizontal = "left"
+	case "center":
+		alignment.Horizontal = "center"
+	case "end", "right":
+		alignment.Horizontal = "right"
+	case "justify":
+		alignment.Horizontal = "justify"
+	}
+	switch props["vertical-align"] {
+	case "top":
+		alignment.Vertical = "top"
+	case "middle":
+		alignment.Vertical = "center"
+	case "bottom":
+		alignment.Vertical = "bottom"
+	}
+	alignment.WrapText = props["wrap-option"] == "wrap"
+	if alignment != (Alignment{}) {
+		style.Alignment = &alignment
+	}
+	if style.Font == nil && style.Fill.Type == "" && style.Border == nil && style.Alignment == nil {
+		return nil
+	}
+	return &style
+}
+
+// getODSColor provides a function to get the color in the format #RRGGBB
+// by given color of the OpenDocument Spreadsheet.
+func getODSColor(color string) string {
+	if len(color) != 7 || color[0] != '#' {
+		return ""
+	}
+	if _, err := strconv.ParseUint(color[1:], 16, 32); err != nil {
+		return ""
+	}
+	return strings.ToUpper(color)
+}
+
+// parseODSLength provides a function to parse the length such as "2.258cm"
+// to points.
+func parseODSLength(length string) (float64, bool) {
+	length = strings.TrimSpace(length)
+	if len(length) < 3 {
+		return 0, false
+	}
+	unit, ok := odsLengthUnits[length[len(length)-2:]]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(length[:len(length)-2], 64)
+	if err != nil {
+		return 0, false
+	}
+	return math.Round(n*unit*100) / 100, true
+}
+
+// parseODSBorder provides a function to parse the border such as
+// "0.74pt solid #000000" to the border of the cell.
+func parseODSBorder(border string) (Border, bool) {
+	var (
+		b     Border
+		width float64
+		style string
+	)
+	for _, field := range strings.Fields(border) {
+		if color := getODSColor(field); color != "" {
+			b.Color = color
+		} else if w, ok := parseODSLength(field); ok {
+			width = w
+		} else {
+			style = field
+		}
+	}
+	switch style {
+	case "solid":
+		switch {
+		case width > 2:
+			b.Style = 5
+		case width > 1:
+			b.Style = 2
+		default:
+			b.Style = 1
+		}
+	case "dashed":
+		if b.Style = 3; width > 1 {
+			b.Style = 8
+		}
+	case "dotted":
+		b.Style = 4
+	case "double":
+		b.Style = 6
+	default:
+		return b, false
+	}
+	if b.Color == "" {
+		b.Color = "#000000"
+	}
+	return b, true
+}
+
+// parseODSDate provides a function to parse the date value of the cell,
+// returns the time and the layout of the value.
+func parseODSDate(value string) (time.Time, string, error) {
+	var err error
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999", "2006-01-02", time.RFC3339Nano} {
+		var t time.Time
+		if t, err = time.Parse(layout, value); err == nil {
+			return t, layout, nil
+		}
+	}
+	return time.Time{}, "", err
+}
+
+// parseODSDuration provides a function to parse the time value of the cell
+// in the format of ISO 8601 duration such as "PT12H30M00S".
+func parseODSDuration(value string) (time.Duration, error) {
+	sign := ""
+	if strings.HasPrefix(value, "-") {
+		sign, value = "-", value[1:]
+	}
+	if !strings.HasPrefix(value, "PT") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return time.ParseDuration(sign + strings.ToLower(strings.TrimPrefix(value, "PT")))
+}
+
+// fromOpenFormula provides a function to convert the formula in OpenFormula
+// syntax such as "of:=SUM([.A1:.B2];[$Sheet2.C3])" to the formula of the
+// spreadsheet such as "SUM(A1:B2,Sheet2!C3)".
+func fromOpenFormula(formula string) string {
+	if idx := strings.Index(formula, ":="); idx != -1 && !strings.ContainsAny(formula[:idx], `"[(`) {
+		formula = formula[idx+2:]
+	}
+	formula = strings.TrimPrefix(formula, "=")
+	var (
+		b        strings.Builder
+		inString bool
+	)
+	for i := 0; i < len(formula); i++ {
+		c := formula[i]
+		switch {
+		case c == '"':
+			inString = !inString
+			b.WriteByte(c)
+		case inString:
+			b.WriteByte(c)
+		case c == '[':
+			end := strings.IndexByte(formula[i:], ']')
+			if end == -1 {
+				b.WriteString(formula[i:])
+				return b.String()
+			}
+			b.WriteString(fromOpenFormulaRef(formula[i+1 : i+end]))
+			i += end
+		case c == ';':
+			b.WriteByte(',')
+		case c == '!':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// fromOpenFormulaRef provides a function to convert the reference of the
+// OpenFormula such as "$'Sheet 2'.A1:.B2" to the reference of the
+// spreadsheet such as "'Sheet 2'!A1:B2".
+func fromOpenFormulaRef(ref string) string {
+	var sheet string
+	parts := splitODSRef(ref, ':')
+	for i, part := range parts {
+		dot := strings.LastIndexByte(part, '.')
+		if dot == -1 {
+			continue
+		}
+		if i == 0 {
+			sheet = strings.TrimPrefix(part[:dot], "$")
+		}
+		parts[i] = part[dot+1:]
+	}
+	if sheet == "" {
+		return strings.Join(parts, ":")
+	}
+	if !strings.HasPrefix(sheet, "'") {
+		sheet = quoteSheetName(sheet)
+	}
+	return sheet + "!" + strings.Join(parts, ":")
+}
+
+// splitODSRef provides a function to split the reference by given separator
+// outside the single quoted sheet names.
+func splitODSRef(ref string, sep byte) []string {
+	var (
+		parts  []string
+		start  int
+		quoted bool
+	)
+	for i := 0; i < len(ref); i++ {
+		switch ref[i] {
+		case '\'':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				parts, start = append(parts, ref[start:i]), i+1
+			}
+		}
+	}
+	return append(parts, ref[start:])
+}
+
+// toOpenFormula provides a function to convert the formula of the
+// spreadsheet such as "SUM(A1:B2,Sheet2!C3)" to the formula in OpenFormula
+// syntax such as "of:=SUM([.A1:.B2];[$Sheet2.C3])".
+func toOpenFormula(formula string) string {
+	ps := efp.ExcelParser()
+	var b strings.Builder
+	b.WriteString("of:=")
+	for _, token := range ps.Parse(escapeSheetQuotes(strings.TrimPrefix(formula, "="))) {
+		switch {
+		case token.TType == efp.TokenTypeFunction && token.TSubType == efp.TokenSubTypeStart:
+			b.WriteString(token.TValue + "(")
+		case token.TSubType == efp.TokenSubTypeStart:
+			b.WriteString("(")
+		case token.TSubType == efp.TokenSubTypeStop:
+			b.WriteString(")")
+		case token.TType == efp.TokenTypeArgument:
+			b.WriteString(";")
+		case token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeText:
+			b.WriteString(`"` + strings.Replace(token.TValue, `"`, `""`, -1) + `"`)
+		case token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange:
+			b.WriteString(toOpenFormulaRef(token.TValue))
+		case token.TType == efp.TokenTypeOperatorInfix && token.TSubType == efp.TokenSubTypeIntersection:
+			b.WriteString("!")
+		default:
+			b.WriteString(token.TValue)
+		}
+	}
+	return strings.Replace(b.String(), odsQuotePlaceholder, "''", -1)
+}
+
+// odsQuotePlaceholder defined the placeholder of the escaped single quotes
+// in the quoted sheet names, which can't be unescaped by the formula parser.
+const odsQuotePlaceholder = "\x00"
+
+// escapeSheetQuotes provides a function to replace the escaped single quotes
+// in the quoted sheet names of the formula with the placeholder.
+func escapeSheetQuotes(formula string) string {
+	var (
+		b                strings.Builder
+		inString, inPath bool
+	)
+	for i := 0; i < len(formula); i++ {
+		switch c := formula[i]; {
+		case c == '"' && !inPath:
+			inString = !inString
+		case c == '\'' && !inString:
+			if inPath && i+1 < len(formula) && formula[i+1] == '\'' {
+				b.WriteString(odsQuotePlaceholder)
+				i++
+				continue
+			}
+			inPath = !inPath
+		}
+		b.WriteByte(formula[i])
+	}
+	return b.String()
+}
+
+// toOpenFormulaRef provides a function to convert the reference of the
+// spreadsheet such as "'Sheet 2'!A1:B2" to the reference of the OpenFormula
+// such as "[$'Sheet 2'.A1:.B2]", the defined names will not be converted.
+func toOpenFormulaRef(ref string) string {
+	parts := splitODSRef(ref, ':')
+	for i, part := range parts {
+		var sheet string
+		if idx := strings.LastIndexByte(part, '!'); idx != -1 {
+			sheet, part = "$"+quoteSheetName(strings.Replace(unquoteSheetName(part[:idx]), odsQuotePlaceholder, "'", -1)), part[idx+1:]
+		}
+		if len(parts) == 1 && !odsCellRefPattern.MatchString(part) || !odsRangeRefPattern.MatchString(part) {
+			return ref
+		}
+		parts[i] = sheet + "." + part
+	}
+	return "[" + strings.Join(parts, ":") + "]"
+}
+
+// odsWriter defined the state of writing the OpenDocument Spreadsheet, the
+// automatic styles are collected during writing the tables.
+type odsWriter struct {
+	f          *File
+	styles     bytes.Buffer
+	colStyles  map[string]string
+	rowStyles  map[string]string
+	cellStyles map[int]string
+	sst        *xlsxSST
+}
+
+// WriteODS provides a function to write the spreadsheet as OpenDocument
+// Spreadsheet to io.Writer. The cell values, formulas in OpenFormula syntax,
+// fonts, fills, borders and alignments of the cells, merged cells, row
+// heights, column widths, hyperlinks and hidden rows and columns of the
+// worksheets will be written, and the other contents such as charts,
+// pictures and comments are not supported. The SaveAs function will write
+// the OpenDocument Spreadsheet if the file extension is ".ods". For example:
+//
+//    var buf bytes.Buffer
+//    if err := f.WriteODS(&buf); err != nil {
+//        fmt.Println(err)
+//    }
+//
+func (f *File) WriteODS(w io.Writer) error {
+	ow := &odsWriter{
+		f: f, colStyles: make(map[string]string), rowStyles: make(map[string]string),
+		cellStyles: make(map[int]string), sst: f.sharedStringsReadOnly(),
+	}
+	var body bytes.Buffer
+	for _, sheet := range f.GetSheetList() {
+		ws, err := f.workSheetReadOnly(sheet)
+		if err != nil {
+			if strings.HasSuffix(err.Error(), "is chart sheet") {
+				continue
+			}
+			return err
+		}
+		ws.Lock()
+		err = ow.writeTable(&body, sheet, ws)
+		ws.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	zw := zip.NewWriter(w)
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err = mimetype.Write([]byte(ODSMimeType)); err != nil {
+		return err
+	}
+	namespaces := fmt.Sprintf(` xmlns:office="%s" xmlns:style="%s" xmlns:text="%s" xmlns:table="%s" xmlns:fo="%s" xmlns:xlink="%s" xmlns:of="urn:oasis:names:tc:opendocument:xmlns:of:1.2" office:version="1.2"`,
+		NameSpaceODSOff, NameSpaceODSSty, NameSpaceODSTxt, NameSpaceODSTbl, NameSpaceODSFo, NameSpaceODSLnk)
+	for _, part := range []struct{ name, content string }{
+		{"META-INF/manifest.xml", XMLHeader + `<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.2">` +
+			`<manifest:file-entry manifest:full-path="/" manifest:version="1.2" manifest:media-type="` + ODSMimeType + `"/>` +
+			`<manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>` +
+			`<manifest:file-entry manifest:full-path="styles.xml" manifest:media-type="text/xml"/></manifest:manifest>`},
+		{"styles.xml", XMLHeader + `<office:document-styles` + namespaces + `><office:styles/></office:document-styles>`},
+		{"content.xml", XMLHeader + `<office:document-content` + namespaces + `><office:automatic-styles>` + ow.styles.String() +
+			`</office:automatic-styles><office:body><office:spreadsheet>` + body.String() + `</office:spreadsheet></office:body></office:document-content>`},
+	} {
+		fw, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err = fw.Write([]byte(part.content)); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// writeTable provides a function to write the table:table element by given
+// worksheet.
+func (ow *odsWriter) writeTable(buf *bytes.Buffer, sheet string, ws *xlsxWorksheet) error {
+	var maxCol int
+	cells := make(map[int][]*xlsxC)
+	for i := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[i]
+		for j := range row.C {
+			col, r, err := CellNameToCoordinates(row.C[j].R)
+			if err != nil {
+				return err
+			}
+			if len(cells[r]) < col {
+				cells[r] = append(cells[r], make([]*xlsxC, col-len(cells[r]))...)
+			}
+			cells[r][col-1] = &row.C[j]
+			if col > maxCol {
+				maxCol = col
+			}
+		}
+	}
+	if ws.Cols != nil {
+		for _, c := range ws.Cols.Col {
+			if c.Max > maxCol && c.Max <= TotalColumns && (c.Width != 0 || c.Hidden) {
+				maxCol = c.Max
+			}
+		}
+	}
+	if maxCol == 0 {
+		maxCol = 1
+	}
+	spans, covered, err := getODSMergeCells(ws)
+	if err != nil {
+		return err
+	}
+	links := make(map[string]string)
+	if ws.Hyperlinks != nil {
+		for _, link := range ws.Hyperlinks.Hyperlink {
+			if link.RID != "" {
+				links[link.Ref] = ow.f.getSheetRelationshipsTargetByID(sheet, link.RID)
+			} else if link.Location != "" {
+				links[link.Ref] = "#" + strings.Trim(toOpenFormulaRef(link.Location), "[]")
+			}
+		}
+	}
+	fmt.Fprintf(buf, `<table:table table:name="%s">`, escapeODSText(sheet))
+	ow.writeColumns(buf, ws, maxCol)
+	rows := make(map[int]*xlsxRow, len(ws.SheetData.Row))
+	var maxRow int
+	for i := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[i]
+		rows[row.R] = row
+		if row.R > maxRow && (len(row.C) > 0 || row.Ht != 0 || row.Hidden) {
+			maxRow = row.R
+		}
+	}
+	if maxRow == 0 {
+		buf.WriteString(`<table:table-row><table:table-cell/></table:table-row></table:table>`)
+		return nil
+	}
+	for r, empty := 1, 0; r <= maxRow; r++ {
+		row, ok := rows[r]
+		if !ok || (len(cells[r]) == 0 && row.Ht == 0 && !row.Hidden) {
+			empty++
+			continue
+		}
+		if empty > 0 {
+			fmt.Fprintf(buf, `<table:table-row table:number-rows-repeated="%d"><table:table-cell table:number-columns-repeated="%d"/></table:table-row>`, empty, maxCol)
+			empty = 0
+		}
+		buf.WriteString(`<table:table-row`)
+		if row.Ht != 0 {
+			fmt.Fprintf(buf, ` table:style-name="%s"`, ow.getRowStyle(row.Ht))
+		}
+		if row.Hidden {
+			buf.WriteString(` table:visibility="collapse"`)
+		}
+		buf.WriteString(`>`)
+		for c := 1; c <= maxCol; c++ {
+			axis, _ := CoordinatesToCellName(c, r)
+			if covered[axis] {
+				buf.WriteString(`<table:covered-table-cell/>`)
+				continue
+			}
+			var cell *xlsxC
+			if c <= len(cells[r]) {
+				cell = cells[r][c-1]
+			}
+			if err = ow.writeCell(buf, ws, cell, spans[axis], links[axis]); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(`</table:table-row>`)
+	}
+	buf.WriteString(`</table:table>`)
+	return nil
+}
+
+// getODSMergeCells provides a function to get the spanned columns and rows
+// of the merged cells and the covered cells by given worksheet.
+func getODSMergeCells(ws *xlsxWorksheet) (map[string][2]int, map[string]bool, error) {
+	spans, covered := make(map[string][2]int), make(map[string]bool)
+	if ws.MergeCells == nil {
+		return spans, covered, nil
+	}
+	for _, mergeCell := range ws.MergeCells.Cells {
+		rect, err := getHTMLRangeCoordinates(mergeCell.Ref)
+		if err != nil {
+			return spans, covered, err
+		}
+		for row := rect[1]; row <= rect[3]; row++ {
+			for col := rect[0]; col <= rect[2]; col++ {
+				axis, _ := CoordinatesToCellName(col, row)
+				covered[axis] = row != rect[1] || col != rect[0]
+			}
+		}
+		axis, _ := CoordinatesToCellName(rect[0], rect[1])
+		spans[axis] = [2]int{rect[2] - rect[0] + 1, rect[3] - rect[1] + 1}
+	}
+	return spans, covered, nil
+}
+
+// writeColumns provides a function to write the table:table-column elements
+// by given worksheet and number of the columns, the adjacent columns with
+// the same properties will be written as repeated columns.
+func (ow *odsWriter) writeColumns(buf *bytes.Buffer, ws *xlsxWorksheet, maxCol int) {
+	type column struct {
+		width  float64
+		hidden bool
+	}
+	columns := make([]column, maxCol)
+	if ws.Cols != nil {
+		for _, c := range ws.Cols.Col {
+			for col := c.Min; col <= c.Max && col <= maxCol; col++ {
+				columns[col-1] = column{width: c.Width, hidden: c.Hidden}
+			}
+		}
+	}
+	for i := 0; i < len(columns); {
+		j := i + 1
+		for j < len(columns) && columns[j] == columns[i] {
+			j++
+		}
+		pixels := defaultColWidthPixels
+		if columns[i].width != 0 {
+			pixels = convertColWidthToPixels(columns[i].width)
+		}
+		fmt.Fprintf(buf, `<table:table-column table:style-name="%s"`, ow.getColStyle(pixels))
+		if j-i > 1 {
+			fmt.Fprintf(buf, ` table:number-columns-repeated="%d"`, j-i)
+		}
+		if columns[i].hidden {
+			buf.WriteString(` table:visibility="collapse"`)
+		}
+		buf.WriteString(`/>`)
+		i = j
+	}
+}
+
+// getColStyle provides a function to get the name of the column style by
+// given column width in pixels.
+func (ow *odsWriter) getColStyle(pixels float64) string {
+	length := strconv.FormatFloat(pixels*0.75, 'f', -1, 64) + "pt"
+	name, ok := ow.colStyles[length]
+	if !ok {
+		name = "co" + strconv.Itoa(len(ow.colStyles)+1)
+		ow.colStyles[length] = name
+		fmt.Fprintf(&ow.styles, `<style:style style:name="%s" style:family="table-column"><style:table-column-properties style:column-width="%s"/></style:style>`, name, length)
+	}
+	return name
+}
+
+// getRowStyle provides a function to get the name of the row style by given
+// row height in points.
+func (ow *odsWriter) getRowStyle(height float64) string {
+	length := strconv.FormatFloat(height, 'f', -1, 64) + "pt"
+	name, ok := ow.rowStyles[length]
+	if !ok {
+		name = "ro" + strconv.Itoa(len(ow.rowStyles)+1)
+		ow.rowStyles[length] = name
+		fmt.Fprintf(&ow.styles, `<style:style style:name="%s" style:family="table-row"><style:table-row-properties style:row-height="%s" style:use-optimal-row-height="false"/></style:style>`, name, length)
+	}
+	return name
+}
+
+// getCellStyle provides a function to get the name of the cell style by
+// given style ID, returns empty string if the style has no properties which
+// can be written.
+func (ow *odsWriter) getCellStyle(styleID int) string {
+	if name, ok := ow.cellStyles[styleID]; ok {
+		return name
+	}
+	var name string
+	s := ow.f.stylesReadOnly()
+	if s.CellXfs != nil && styleID > 0 && styleID < len(s.CellXfs.Xf) {
+		xf := s.CellXfs.Xf[styleID]
+		var cellProps, paraProps, textProps []string
+		if xf.FontID != nil && s.Fonts != nil && *xf.FontID >= 0 && *xf.FontID < len(s.Fonts.Font) {
+			textProps = ow.f.getODSFontProps(s.Fonts.Font[*xf.FontID])
+		}
+		if xf.FillID != nil && s.Fills != nil && *xf.FillID >= 0 && *xf.FillID < len(s.Fills.Fill) {
+			if fill := s.Fills.Fill[*xf.FillID]; fill.PatternFill != nil && fill.PatternFill.PatternType != "" && fill.PatternFill.PatternType != "none" {
+				if color := ow.f.getHTMLColor(&fill.PatternFill.FgColor); color != "" {
+					cellProps = append(cellProps, fmt.Sprintf(`fo:background-color="%s"`, color))
+				}
+			}
+		}
+		if xf.BorderID != nil && s.Borders != nil && *xf.BorderID >= 0 && *xf.BorderID < len(s.Borders.Border) {
+			border := s.Borders.Border[*xf.BorderID]
+			for _, side := range []struct {
+				name string
+				line xlsxLine
+			}{{"left", border.Left}, {"right", border.Right}, {"top", border.Top}, {"bottom", border.Bottom}} {
+				if style, ok := odsBorderStyles[side.line.Style]; ok {
+					color := ow.f.getHTMLColor(side.line.Color)
+					if color == "" {
+						color = "#000000"
+					}
+					cellProps = append(cellProps, fmt.Sprintf(`fo:border-%s="%s %s"`, side.name, style, color))
+				}
+			}
+		}
+		if xf.Alignment != nil {
+			if align, ok := map[string]string{"left": "start", "center": "center", "centerContinuous": "center", "right": "end", "justify": "justify", "distributed": "justify"}[xf.Alignment.Horizontal]; ok {
+				paraProps = append(paraProps, fmt.Sprintf(`fo:text-align="%s"`, align))
+			}
+			if align, ok := htmlVerticalAlignments[xf.Alignment.Vertical]; ok {
+				cellProps = append(cellProps, fmt.Sprintf(`style:vertical-align="%s"`, align))
+			}
+			if xf.Alignment.WrapText {
+				cellProps = append(cellProps, `fo:wrap-option="wrap"`)
+			}
+		}
+		if len(cellProps)+len(paraProps)+len(textProps) > 0 {
+			name = "ce" + strconv.Itoa(styleID)
+			fmt.Fprintf(&ow.styles, `<style:style style:name="%s" style:family="table-cell">`, name)
+			for _, props := range []struct {
+				element string
+				attrs   []string
+			}{{"table-cell-properties", cellProps}, {"paragraph-properties", paraProps}, {"text-properties", textProps}} {
+				if len(props.attrs) > 0 {
+					fmt.Fprintf(&ow.styles, `<style:%s %s/>`, props.element, strings.Join(props.attrs, " "))
+				}
+			}
+			ow.styles.WriteString(`</style:style>`)
+		}
+	}
+	ow.cellStyles[styleID] = name
+	return name
+}
+
+// getODSFontProps provides a function to get the text properties of the
+// OpenDocument Spreadsheet by given font.
+func (f *File) getODSFontProps(font *xlsxFont) []string {
+	var props []string
+	if font.Name != nil && font.Name.Val != nil {
+		props = append(props, fmt.Sprintf(`fo:font-family="%s"`, escapeODSText(*font.Name.Val)))
+	}
+	if font.Sz != nil && font.Sz.Val != nil {
+		props = append(props, fmt.Sprintf(`fo:font-size="%spt"`, strconv.FormatFloat(*font.Sz.Val, 'f', -1, 64)))
+	}
+	if font.B != nil && *font.B {
+		props = append(props, `fo:font-weight="bold"`)
+	}
+	if font.I != nil && *font.I {
+		props = append(props, `fo:font-style="italic"`)
+	}
+	if font.U != nil && (font.U.Val == nil || *font.U.Val != "none") {
+		props = append(props, `style:text-underline-style="solid" style:text-underline-width="auto" style:text-underline-color="font-color"`)
+	}
+	if font.Strike != nil && *font.Strike {
+		props = append(props, `style:text-line-through-style="solid"`)
+	}
+	if color := f.getHTMLColor(font.Color); color != "" {
+		props = append(props, fmt.Sprintf(`fo:color="%s"`, color))
+	}
+	return props
+}
+
+// writeCell provides a function to write the table:table-cell element by
+// given cell, spanned columns and rows and hyperlink of the cell.
+func (ow *odsWriter) writeCell(buf *bytes.Buffer, ws *xlsxWorksheet, c *xlsxC, span [2]int, link string) error {
+	buf.WriteString(`<table:table-cell`)
+	if span[0] > 1 || span[1] > 1 {
+		fmt.Fprintf(buf, ` table:number-columns-spanned="%d" table:number-rows-spanned="%d"`, span[0], span[1])
+	}
+	if c == nil {
+		buf.WriteString(`/>`)
+		return nil
+	}
+	if style := ow.getCellStyle(c.S); style != "" {
+		fmt.Fprintf(buf, ` table:style-name="%s"`, style)
+	}
+	raw, err := c.getValueFrom(ow.f, ow.sst, true)
+	if err != nil {
+		return err
+	}
+	text, err := c.getValueFrom(ow.f, ow.sst, false)
+	if err != nil {
+		return err
+	}
+	if c.F != nil {
+		formula := c.F.Content
+		if c.F.T == STCellFormulaTypeShared {
+			formula = getSharedForumula(ws, c.F.Si)
+		}
+		if formula != "" {
+			fmt.Fprintf(buf, ` table:formula="%s"`, escapeODSText(toOpenFormula(formula)))
+		}
+	}
+	switch c.T {
+	case "s", "str", "inlineStr", "e":
+		if raw != "" || c.T != "str" {
+			buf.WriteString(` office:value-type="string"`)
+		}
+	case "b":
+		value := "false"
+		if raw == "1" || strings.EqualFold(raw, "true") {
+			value, text = "true", "TRUE"
+		} else {
+			text = "FALSE"
+		}
+		fmt.Fprintf(buf, ` office:value-type="boolean" office:boolean-value="%s"`, value)
+	case "d":
+		fmt.Fprintf(buf, ` office:value-type="date" office:date-value="%s"`, escapeODSText(raw))
+	default:
+		if raw != "" {
+			ow.writeNumberValue(buf, c.S, raw)
+		}
+	}
+	if text == "" {
+		buf.WriteString(`/>`)
+		return nil
+	}
+	buf.WriteString(`>`)
+	for _, line := range strings.Split(text, "\n") {
+		buf.WriteString(`<text:p>`)
+		if link != "" {
+			fmt.Fprintf(buf, `<text:a xlink:href="%s" xlink:type="simple">`, escapeODSText(link))
+		}
+		buf.WriteString(escapeODSSpaces(line))
+		if link != "" {
+			buf.WriteString(`</text:a>`)
+		}
+		buf.WriteString(`</text:p>`)
+	}
+	buf.WriteString(`</table:table-cell>`)
+	return nil
+}
+
+// writeNumberValue provides a function to write the value type and value
+// attributes of the numeric cell by given style ID and raw value, the date,
+// time and percentage number formats will be written as the corresponding
+// value types.
+func (ow *odsWriter) writeNumberValue(buf *bytes.Buffer, styleID int, raw string) {
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		fmt.Fprintf(buf, ` office:value-type="string"`)
+		return
+	}
+	var numFmt int
+	s := ow.f.stylesReadOnly()
+	if s.CellXfs != nil && styleID > 0 && styleID < len(s.CellXfs.Xf) && s.CellXfs.Xf[styleID].NumFmtID != nil {
+		numFmt = *s.CellXfs.Xf[styleID].NumFmtID
+	}
+	switch {
+	case numFmt >= 14 && numFmt <= 17 || numFmt == 22:
+		if t, err := ExcelDateToTime(n, ow.f.date1904()); err == nil {
+			fmt.Fprintf(buf, ` office:value-type="date" office:date-value="%s"`, t.Format("2006-01-02T15:04:05"))
+			return
+		}
+	case numFmt >= 18 && numFmt <= 21 || numFmt >= 45 && numFmt <= 47:
+		seconds := int64(math.Round(n * 86400))
+		sign := ""
+		if seconds < 0 {
+			sign, seconds = "-", -seconds
+		}
+		fmt.Fprintf(buf, ` office:value-type="time" office:time-value="%sPT%02dH%02dM%02dS"`, sign, seconds/3600, seconds/60%60, seconds%60)
+		return
+	case numFmt == 9 || numFmt == 10:
+		fmt.Fprintf(buf, ` office:value-type="percentage" office:value="%s"`, raw)
+		return
+	}
+	fmt.Fprintf(buf, ` office:value-type="float" office:value="%s"`, raw)
+}
+
+// escapeODSText provides a function to escape the text for the XML
+// attributes and character data.
+func escapeODSText(text string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// escapeODSSpaces provides a function to escape the text of the paragraph,
+// the consecutive, leading and trailing spaces and tabs will be written as
+// text:s and text:tab elements to be preserved.
+func escapeODSSpaces(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case ' ':
+			j := i
+			for j < len(text) && text[j] == ' ' {
+				j++
+			}
+			if n := j - i; i > 0 && j < len(text) && n == 1 {
+				b.WriteString(" ")
+			} else if n == 1 {
+				b.WriteString(`<text:s/>`)
+			} else {
+				fmt.Fprintf(&b, `<text:s text:c="%d"/>`, n)
+			}
+			i = j
+		case '\t':
+			b.WriteString(`<text:tab/>`)
+			i++
+		default:
+			j := strings.IndexAny(text[i:], " \t")
+			if j == -1 {
+				j = len(text) - i
+			}
+			b.WriteString(escapeODSText(text[i : i+j]))
+			i += j
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,312 @@
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestODSRoundTrip(t *testing.T) {
+	f := NewFile()
+	f.SetSheetName("Sheet1", "Data")
+	f.NewSheet("My Sheet")
+	assert.NoError(t, f.SetSheetRow("Data", "A1", &[]interface{}{"Name", 12.5, true, time.Date(2020, 10, 18, 12, 30, 0, 0, time.UTC), "  two\nlines"}))
+	assert.NoError(t, f.SetCellValue("Data", "A3", 8*time.Hour))
+	assert.NoError(t, f.SetCellFormula("Data", "B3", "SUM(B1,'My Sheet'!A1:A2)"))
+	assert.NoError(t, f.SetCellFormula("Data", "C3", `IF(A1="x;y",1,2)`))
+	assert.NoError(t, f.MergeCell("Data", "A5", "B6"))
+	assert.NoError(t, f.SetCellValue("Data", "A5", "merged"))
+	assert.NoError(t, f.SetColWidth("Data", "B", "C", 20))
+	assert.NoError(t, f.SetColVisible("Data", "D", false))
+	assert.NoError(t, f.SetRowHeight("Data", 2, 30))
+	assert.NoError(t, f.SetCellHyperLink("Data", "A1", "https://github.com/360EntSecGroup-Skylar/excelize", "External"))
+	style, err := f.NewStyle(&Style{
+		Font:      &Font{Bold: true, Italic: true, Family: "Arial", Size: 14, Color: "#FF0000"},
+		Fill:      Fill{Type: "pattern", Pattern: 1, Color: []string{"#FFFF00"}},
+		Border:    []Border{{Type: "left", Color: "#0000FF", Style: 2}, {Type: "bottom", Color: "#000000", Style: 1}},
+		Alignment: &Alignment{Horizontal: "center", Vertical: "top", WrapText: true},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Data", "A1", "A1", style))
+	percent, err := f.NewStyle(&Style{NumFmt: 10})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellFloat("My Sheet", "A1", 0.25, -1, 64))
+	assert.NoError(t, f.SetCellStyle("My Sheet", "A1", "A1", percent))
+	assert.NoError(t, f.SetCellInt("My Sheet", "A2", 3))
+
+	path := filepath.Join("test", "TestODSRoundTrip.ods")
+	assert.NoError(t, f.SaveAs(path))
+	ods, err := OpenFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Data", "My Sheet"}, ods.GetSheetList())
+
+	rows, err := ods.GetRows("Data")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Name", "12.5", "1", "10/18/20 12:30", "  two\nlines"}, rows[0])
+	for axis, expected := range map[string]string{"A3": "08:00:00", "A5": "merged"} {
+		value, err := ods.GetCellValue("Data", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, axis)
+	}
+	date, err := ods.GetCellTime("Data", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 10, 18, 12, 30, 0, 0, time.UTC), date.Round(time.Second))
+	for axis, expected := range map[string]string{"B3": "SUM(B1,'My Sheet'!A1:A2)", "C3": `IF(A1="x;y",1,2)`} {
+		formula, err := ods.GetCellFormula("Data", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, axis)
+	}
+	mergeCells, err := ods.GetMergeCells("Data")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A5", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "B6", mergeCells[0].GetEndAxis())
+	width, err := ods.GetColWidth("Data", "B")
+	assert.NoError(t, err)
+	assert.InDelta(t, 20, width, 0.2)
+	visible, err := ods.GetColVisible("Data", "D")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	height, err := ods.GetRowHeight("Data", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	link, target, err := ods.GetCellHyperLink("Data", "A1")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com/360EntSecGroup-Skylar/excelize", target)
+
+	styleID, err := ods.GetCellStyle("Data", "A1")
+	assert.NoError(t, err)
+	s := ods.stylesReader()
+	xf := s.CellXfs.Xf[styleID]
+	font := s.Fonts.Font[*xf.FontID]
+	assert.True(t, *font.B)
+	assert.True(t, *font.I)
+	assert.Equal(t, "Arial", *font.Name.Val)
+	assert.Equal(t, 14.0, *font.Sz.Val)
+	assert.Equal(t, "FFFF0000", font.Color.RGB)
+	assert.Equal(t, "FFFFFF00", s.Fills.Fill[*xf.FillID].PatternFill.FgColor.RGB)
+	border := s.Borders.Border[*xf.BorderID]
+	assert.Equal(t, "medium", border.Left.Style)
+	assert.Equal(t, "FF0000FF", border.Left.Color.RGB)
+	assert.Equal(t, "thin", border.Bottom.Style)
+	assert.Equal(t, "", border.Top.Style)
+	assert.Equal(t, "center", xf.Alignment.Horizontal)
+	assert.Equal(t, "top", xf.Alignment.Vertical)
+	assert.True(t, xf.Alignment.WrapText)
+
+	rows, err = ods.GetRows("My Sheet")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"25.00%"}, {"3"}}, rows)
+
+	// Test save as ODS with upper case extension and save the ODS file as XLSX.
+	assert.NoError(t, ods.SaveAs(filepath.Join("test", "TestODSRoundTrip.ODS")))
+	assert.NoError(t, ods.SaveAs(filepath.Join("test", "TestODSRoundTrip.xlsx")))
+
+	// Test write ODS with invalid worksheet.
+	f = NewFile()
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.SheetData.Row = []xlsxRow{{R: 1, C: []xlsxC{{R: "-"}}}}
+	assert.EqualError(t, f.WriteODS(&bytes.Buffer{}), `cannot convert cell "-" to coordinates: invalid cell name "-"`)
+	assert.EqualError(t, NewFile().WriteODS(iotestErrWriter{}), "write error")
+
+	// Test round trip with the default sheet name.
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "default"))
+	var buf bytes.Buffer
+	assert.NoError(t, f.WriteODS(&buf))
+	ods, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1"}, ods.GetSheetList())
+	value, err := ods.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "default", value)
+}
+
+func TestOpenODS(t *testing.T) {
+	content := `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0" xmlns:xlink="http://www.w3.org/1999/xlink">
+<office:automatic-styles>
+<style:style style:name="co1" style:family="table-column"><style:table-column-properties style:column-width="2.258cm"/></style:style>
+<style:style style:name="ro1" style:family="table-row"><style:table-row-properties style:row-height="0.5in"/></style:style>
+<style:style style:name="ce1" style:family="table-cell" style:parent-style-name="Bold"><style:table-cell-properties fo:border="0.06pt solid #000000" style:vertical-align="middle"/><style:paragraph-properties fo:text-align="end"/></style:style>
+</office:automatic-styles>
+<office:body><office:spreadsheet>
+<table:table table:name="First">
+<table:table-column table:style-name="co1" table:number-columns-repeated="2"/><table:table-column table:visibility="collapse"/>
+<table:table-row table:style-name="ro1"><table:table-cell table:style-name="ce1" office:value-type="string"><text:p>a<text:s text:c="2"/>b<text:tab/>c</text:p><text:p>d<text:line-break/>e</text:p><office:annotation><text:p>comment</text:p></office:annotation></table:table-cell><table:table-cell office:value-type="float" office:value="1.5" table:number-columns-repeated="2"><text:p>1.5</text:p></table:table-cell><table:table-cell office:value-type="boolean" office:boolean-value="true"><text:p>TRUE</text:p></table:table-cell></table:table-row>
+<table:table-row table:number-rows-repeated="1048573"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+<table:table-row table:number-rows-repeated="2"><table:table-cell office:value-type="date" office:date-value="2020-10-18"><text:p>10/18/20</text:p></table:table-cell><table:table-cell table:formula="of:=[.B1]*[$First.C1]"/><table:table-cell office:value-type="time" office:time-value="PT12H30M00S"/><table:table-cell><text:p><text:a xlink:href="https://example.com/">link</text:a></text:p></table:table-cell></table:table-row>
+</table:table>
+<table:table table:name="Second"><table:table-row><table:table-cell table:number-columns-spanned="2" table:number-rows-spanned="1" office:value-type="string"><text:p>span</text:p></table:table-cell><table:covered-table-cell/></table:table-row></table:table>
+</office:spreadsheet></office:body></office:document-content>`
+	styles := `<office:document-styles xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"><office:styles><style:style style:name="Bold" style:family="table-cell"><style:text-properties fo:font-weight="bold" style:font-name="Liberation Sans" fo:font-size="12pt" fo:color="#00ff00"/></style:style></office:styles></office:document-styles>`
+	buf := newODSPackage(t, map[string]string{"content.xml": content, "styles.xml": styles})
+
+	f, err := OpenReader(bytes.NewReader(buf))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"First", "Second"}, f.GetSheetList())
+	rows, err := f.GetRows("First")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a  b\tc\nd\ne", "1.5", "1.5", "1"}, rows[0])
+	for axis, expected := range map[string]string{"A1048576": "10-18-20", "C1048575": "12:30:00", "D1048576": "link"} {
+		value, err := f.GetCellValue("First", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, axis)
+	}
+	formula, err := f.GetCellFormula("First", "B1048575")
+	assert.NoError(t, err)
+	assert.Equal(t, "B1*First!C1", formula)
+	width, err := f.GetColWidth("First", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 11.48, width)
+	visible, err := f.GetColVisible("First", "C")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	height, err := f.GetRowHeight("First", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 36.0, height)
+	link, target, err := f.GetCellHyperLink("First", "D1048576")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://example.com/", target)
+
+	styleID, err := f.GetCellStyle("First", "A1")
+	assert.NoError(t, err)
+	s := f.stylesReader()
+	xf := s.CellXfs.Xf[styleID]
+	font := s.Fonts.Font[*xf.FontID]
+	assert.True(t, *font.B)
+	assert.Equal(t, "Liberation Sans", *font.Name.Val)
+	assert.Equal(t, "FF00FF00", font.Color.RGB)
+	assert.Equal(t, "thin", s.Borders.Border[*xf.BorderID].Top.Style)
+	assert.Equal(t, "right", xf.Alignment.Horizontal)
+	assert.Equal(t, "center", xf.Alignment.Vertical)
+
+	mergeCells, err := f.GetMergeCells("Second")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "span", mergeCells[0].GetCellValue())
+	assert.Equal(t, "B1", mergeCells[0].GetEndAxis())
+
+	// Test open ODS with the styled repeated blank rows and columns.
+	content = `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0">
+<office:automatic-styles><style:style style:name="ce1" style:family="table-cell"><style:table-cell-properties fo:border="0.06pt solid #000000"/></style:style></office:automatic-styles>
+<office:body><office:spreadsheet><table:table table:name="Styled">
+<table:table-row table:number-rows-repeated="20000"><table:table-cell office:value-type="string"><text:p>a</text:p></table:table-cell><table:table-cell table:number-columns-repeated="2"/><table:table-cell table:style-name="ce1"/><table:table-cell table:style-name="ce1" table:number-columns-repeated="1020"/></table:table-row>
+<table:table-row table:number-rows-repeated="1028576"><table:table-cell table:style-name="ce1" table:number-columns-repeated="1024"/></table:table-row>
+</table:table></office:spreadsheet></office:body></office:document-content>`
+	f, err = OpenReader(bytes.NewReader(newODSPackage(t, map[string]string{"content.xml": content})), Options{MaxCellsPerSheet: 40000})
+	assert.NoError(t, err)
+	ws, err := f.workSheetReader("Styled")
+	assert.NoError(t, err)
+	assert.Len(t, ws.SheetData.Row, 20000)
+	assert.Len(t, ws.SheetData.Row[19999].C, 4)
+	styleID, err = f.GetCellStyle("Styled", "D20000")
+	assert.NoError(t, err)
+	assert.NotEqual(t, 0, styleID)
+
+	// Test open ODS with the limits.
+	_, err = OpenReader(bytes.NewReader(buf), Options{MaxCellsPerSheet: 10})
+	assert.EqualError(t, err, "the number of cells exceeds the limit 10: First")
+	assert.True(t, errors.Is(err, ErrMaxCells))
+	_, err = OpenReader(bytes.NewReader(buf), Options{MaxCellsPerSheet: 12})
+	assert.NoError(t, err)
+
+	// Test open ODS with invalid contents.
+	for content, expected := range map[string]string{
+		`<x xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"><table:table table:name="S"/><table:table table:name="S"/></x>`:                                                                                                                                               `invalid sheet name "S"`,
+		`<table:table xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" table:name=":"/>`:                                                                                                                                                                                   `invalid sheet name ""`,
+		`<table:table xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" table:name="S"><table:table-row><table:table-cell office:value-type="float" office:value="x"/></table:table-row></table:table>`:     `invalid value "x" of cell S!A1`,
+		`<table:table xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" table:name="S"><table:table-row><table:table-cell office:value-type="date" office:date-value="x"/></table:table-row></table:table>`: `invalid date value "x" of cell S!A1`,
+		`<table:table xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" table:name="S"><table:table-row><table:table-cell office:value-type="time" office:time-value="x"/></table:table-row></table:table>`: `invalid time value "x" of cell S!A1`,
+		`<table:table xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" table:name="S"><table:table-row><table:table-cell>`:                                                                                                                                                 "XML syntax error on line 1: unexpected EOF",
+	} {
+		_, err = OpenReader(bytes.NewReader(newODSPackage(t, map[string]string{"content.xml": content})))
+		assert.EqualError(t, err, expected, content)
+	}
+}
+
+func TestOpenFormula(t *testing.T) {
+	for formula, expected := range map[string]string{
+		"SUM(A1:B2,Sheet2!C3)":           "of:=SUM([.A1:.B2];[$Sheet2.C3])",
+		"'My Sheet'!$A$1+A:A*1:1":        "of:=[$'My Sheet'.$A$1]+[.A:.A]*[.1:.1]",
+		`IF(A1="a""b;c",TRUE,"")`:        `of:=IF([.A1]="a""b;c";TRUE;"")`,
+		"SUM(A1:B2 B1:C3)":               "of:=SUM([.A1:.B2]![.B1:.C3])",
+		"=-(Sales+1)%":                   "of:=-(Sales+1)%",
+		"Sheet2!Sales":                   "of:=Sheet2!Sales",
+		"VLOOKUP(A1,Sheet2!A:B,2,FALSE)": "of:=VLOOKUP([.A1];[$Sheet2.A:.B];2;FALSE)",
+		"'O''Brien'!A1+'Sheet2'!B1":      "of:=[$'O''Brien'.A1]+[$Sheet2.B1]",
+		`IF('It''s'!A1="a'b",1,0)`:       `of:=IF([$'It''s'.A1]="a'b";1;0)`,
+	} {
+		assert.Equal(t, expected, toOpenFormula(formula), formula)
+	}
+	for formula, expected := range map[string]string{
+		"of:=SUM([.A1:.B2];[$Sheet2.C3])":       "SUM(A1:B2,Sheet2!C3)",
+		"of:=[$'My Sheet'.$A$1:.B2]":            "'My Sheet'!$A$1:B2",
+		"oooc:=[$Sheet 2.A1]":                   "'Sheet 2'!A1",
+		`of:=IF([.A1]="a""[b];c";1;2)`:          `IF(A1="a""[b];c",1,2)`,
+		"of:=SUM([.A1:.B2]![.B1:.C3])":          "SUM(A1:B2 B1:C3)",
+		"=[.A1]+[.B1":                           "A1+[.B1",
+		"of:=COUNTIF([$'It''s'.A1];\"a:=b\")":   "COUNTIF('It''s'!A1,\"a:=b\")",
+		"of:=[$Sheet2.A1:$Sheet2.B2]+[$'x'.A1]": "Sheet2!A1:B2+'x'!A1",
+	} {
+		assert.Equal(t, expected, fromOpenFormula(formula), formula)
+	}
+}
+
+func TestParseODSValues(t *testing.T) {
+	for length, expected := range map[string]float64{"1in": 72, "2.54cm": 72, "10mm": 28.35, "1pc": 12, "4px": 3, "12pt": 12} {
+		pt, ok := parseODSLength(length)
+		assert.True(t, ok, length)
+		assert.Equal(t, expected, pt, length)
+	}
+	for _, length := range []string{"", "1", "1em", "xcm"} {
+		_, ok := parseODSLength(length)
+		assert.False(t, ok, length)
+	}
+	for border, expected := range map[string]Border{
+		"0.74pt solid #ff0000": {Color: "#FF0000", Style: 1},
+		"1.5pt solid":          {Color: "#000000", Style: 2},
+		"2.5pt solid #000000":  {Color: "#000000", Style: 5},
+		"0.5pt dashed #000000": {Color: "#000000", Style: 3},
+		"2pt dashed #000000":   {Color: "#000000", Style: 8},
+		"1pt dotted #000000":   {Color: "#000000", Style: 4},
+		"2pt double #000000":   {Color: "#000000", Style: 6},
+	} {
+		b, ok := parseODSBorder(border)
+		assert.True(t, ok, border)
+		assert.Equal(t, expected, b, border)
+	}
+	_, ok := parseODSBorder("none")
+	assert.False(t, ok)
+	d, err := parseODSDuration("-PT01H30M15S")
+	assert.NoError(t, err)
+	assert.Equal(t, -(90*time.Minute + 15*time.Second), d)
+	_, err = parseODSDuration("P1D")
+	assert.EqualError(t, err, `invalid duration "P1D"`)
+	assert.Equal(t, "<text:s/>a b<text:s text:c=\"2\"/>c<text:tab/>&lt;<text:s/>", escapeODSSpaces(" a b  c\t< "))
+}
+
+func newODSPackage(t *testing.T, parts map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(ODSMimeType))
+	assert.NoError(t, err)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(strings.TrimSpace(content)))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
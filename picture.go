@@ -318,11 +318,11 @@ func (f *File) countMedia() int {
 // and drawings that use it will reference the same image.
 func (f *File) addMedia(file []byte, ext string) string {
 	count := f.countMedia()
-	for name, existing := range f.XLSX {
+	for name := range f.XLSX {
 		if !strings.HasPrefix(name, "xl/media/image") {
 			continue
 		}
-		if bytes.Equal(file, existing) {
+		if bytes.Equal(file, f.readXML(name)) {
 			return name
 		}
 	}
@@ -342,8 +342,8 @@ func (f *File) setContentTypePartImageExtensions() {
 			imageTypes[v.Extension] = true
 		}
 	}
-	for k, v := range imageTypes {
-		if !v {
+	for _, k := range []string{"gif", "jpeg", "png", "tiff"} {
+		if !imageTypes[k] {
 			content.Defaults = append(content.Defaults, xlsxDefault{
 				Extension:   k,
 				ContentType: "image/" + k,
@@ -459,7 +459,7 @@ func (f *File) GetPicture(sheet, cell string) (string, []byte, error) {
 	}
 	col--
 	row--
-	xlsx, err := f.workSheetReader(sheet)
+	xlsx, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return "", nil, err
 	}
@@ -511,11 +511,15 @@ func (f *File) getPicture(row, col int, drawingXML, drawingRelationships string)
 	)
 
 	wsDr, _ = f.drawingParser(drawingXML)
-	if ret, buf = f.getPictureFromWsDr(row, col, drawingRelationships, wsDr); len(buf) > 0 {
+	if ret, buf, err = f.getPictureFromWsDr(row, col, drawingRelationships, wsDr); len(buf) > 0 || err != nil {
+		return
+	}
+	var content []byte
+	if content, err = f.readBytes(drawingXML); err != nil {
 		return
 	}
 	deWsDr = new(decodeWsDr)
-	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(drawingXML)))).
+	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
 		Decode(deWsDr); err != nil && err != io.EOF {
 		err = fmt.Errorf("xml decode error: %s", err)
 		return
@@ -532,7 +536,8 @@ func (f *File) getPicture(row, col int, drawingXML, drawingRelationships string)
 			if deTwoCellAnchor.From.Col == col && deTwoCellAnchor.From.Row == row {
 				drawRel = f.getDrawingRelationships(drawingRelationships, deTwoCellAnchor.Pic.BlipFill.Blip.Embed)
 				if _, ok = supportImageTypes[filepath.Ext(drawRel.Target)]; ok {
-					ret, buf = filepath.Base(drawRel.Target), f.XLSX[strings.Replace(drawRel.Target, "..", "xl", -1)]
+					ret = filepath.Base(drawRel.Target)
+					buf, err = f.readBytes(strings.Replace(drawRel.Target, "..", "xl", -1))
 					return
 				}
 			}
@@ -544,7 +549,7 @@ func (f *File) getPicture(row, col int, drawingXML, drawingRelationships string)
 // getPictureFromWsDr provides a function to get picture base name and raw
 // content in worksheet drawing by given coordinates and drawing
 // relationships.
-func (f *File) getPictureFromWsDr(row, col int, drawingRelationships string, wsDr *xlsxWsDr) (ret string, buf []byte) {
+func (f *File) getPictureFromWsDr(row, col int, drawingRelationships string, wsDr *xlsxWsDr) (ret string, buf []byte, err error) {
 	var (
 		ok      bool
 		anchor  *xdrCellAnchor
@@ -556,7 +561,8 @@ func (f *File) getPictureFromWsDr(row, col int, drawingRelationships string, wsD
 				if drawRel = f.getDrawingRelationships(drawingRelationships,
 					anchor.Pic.BlipFill.Blip.Embed); drawRel != nil {
 					if _, ok = supportImageTypes[filepath.Ext(drawRel.Target)]; ok {
-						ret, buf = filepath.Base(drawRel.Target), f.XLSX[strings.Replace(drawRel.Target, "..", "xl", -1)]
+						ret = filepath.Base(drawRel.Target)
+						buf, err = f.readBytes(strings.Replace(drawRel.Target, "..", "xl", -1))
 						return
 					}
 				}
@@ -0,0 +1,297 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	a1CellRefRegexp   = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})(\$?)([0-9]+)$`)
+	a1ColRefRegexp    = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})$`)
+	a1RowRefRegexp    = regexp.MustCompile(`^(\$?)([0-9]+)$`)
+	r1c1CellRefRegexp = regexp.MustCompile(`^[Rr](\[-?[0-9]+\]|[0-9]+)?[Cc](\[-?[0-9]+\]|[0-9]+)?$`)
+	r1c1RowRefRegexp  = regexp.MustCompile(`^[Rr](\[-?[0-9]+\]|[0-9]+)?$`)
+	r1c1ColRefRegexp  = regexp.MustCompile(`^[Cc](\[-?[0-9]+\]|[0-9]+)?$`)
+)
+
+// FormulaToR1C1 provides a function to convert the formula or reference in
+// A1 reference style to R1C1 reference style relative to the given cell.
+// For example, convert the formula of the cell C3:
+//
+//    formula, err := excelize.FormulaToR1C1("=SUM(A1:B2,$A$1,A:A,3:3)", "C3")
+//
+// The formula will be =SUM(R[-2]C[-2]:R[-1]C[-1],R1C1,C[-2],R).
+//
+func FormulaToR1C1(formula, cell string) (string, error) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return "", err
+	}
+	return convertFormulaRefStyle(formula, col, row, true)
+}
+
+// FormulaToA1 provides a function to convert the formula or reference in
+// R1C1 reference style to A1 reference style relative to the given cell.
+// For example, convert the formula of the cell C3:
+//
+//    formula, err := excelize.FormulaToA1("=SUM(R[-2]C[-2]:R[-1]C[-1],R1C1)", "C3")
+//
+// The formula will be =SUM(A1:B2,$A$1).
+//
+func FormulaToA1(formula, cell string) (string, error) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return "", err
+	}
+	return convertFormulaRefStyle(formula, col, row, false)
+}
+
+// SetFormulaRefStyle provides a function to set the reference style of the
+// formulas which set by SetCellFormula and get by GetCellFormula. The
+// supported reference styles are A1 (default) and R1C1. The formulas in
+// R1C1 reference style will be converted relative to the cell of the
+// formula, and the formulas are always stored in A1 reference style in the
+// spreadsheet. For example, set the formula =SUM(A1:A2) on the cell A3 in
+// R1C1 reference style:
+//
+//    if err := f.SetFormulaRefStyle("R1C1"); err != nil {
+//        fmt.Println(err)
+//    }
+//    err := f.SetCellFormula("Sheet1", "A3", "=SUM(R[-2]C:R[-1]C)")
+//
+func (f *File) SetFormulaRefStyle(style string) error {
+	switch style {
+	case "A1", "R1C1":
+		f.formulaRefStyle = style
+		return nil
+	}
+	return fmt.Errorf("unsupported formula reference style %s", style)
+}
+
+// scanFormulaRefTokens provides a function to split the formula into names,
+// references and other characters. The string literals, quoted sheet names
+// and bracketed texts will be kept as a single token. The bracketed offsets
+// of the reference in R1C1 reference style will be a part of the name token
+// if the r1c1 is true.
+func scanFormulaRefTokens(formula string, r1c1 bool) (tokens []string, names []bool) {
+	isName := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '$'
+	}
+	for i := 0; i < len(formula); {
+		r, size := utf8.DecodeRuneInString(formula[i:])
+		if strings.ContainsRune(`"'[`, r) {
+			end := scanFormulaLiteral(formula, i)
+			tokens, names = append(tokens, formula[i:end]), append(names, false)
+			i = end
+			continue
+		}
+		if !isName(r) {
+			tokens, names = append(tokens, formula[i:i+size]), append(names, false)
+			i += size
+			continue
+		}
+		end := i
+		for end < len(formula) {
+			r, size := utf8.DecodeRuneInString(formula[end:])
+			if r == '[' && r1c1 && strings.ContainsRune("RrCc", rune(formula[end-1])) {
+				if idx := strings.IndexByte(formula[end:], ']'); idx != -1 {
+					end += idx + 1
+					continue
+				}
+			}
+			if !isName(r) {
+				break
+			}
+			end += size
+		}
+		tokens, names = append(tokens, formula[i:end]), append(names, true)
+		i = end
+	}
+	return
+}
+
+// convertFormulaRefStyle provides a function to convert the reference style
+// of the formula relative to the given cell coordinates. The toR1C1 specifies
+// converting from A1 to R1C1 reference style, or from R1C1 to A1 reference
+// style.
+func convertFormulaRefStyle(formula string, col, row int, toR1C1 bool) (string, error) {
+	var b strings.Builder
+	tokens, names := scanFormulaRefTokens(formula, !toR1C1)
+	isRef := func(i int) bool {
+		return i < len(tokens) && names[i] && (i+1 == len(tokens) || tokens[i+1] != "(" && tokens[i+1] != "!")
+	}
+	for i := 0; i < len(tokens); i++ {
+		if !isRef(i) {
+			b.WriteString(tokens[i])
+			continue
+		}
+		ref := tokens[i]
+		if i+2 < len(tokens) && tokens[i+1] == ":" && isRef(i+2) {
+			if converted, ok, err := convertRangeRefStyle(ref, tokens[i+2], col, row, toR1C1); ok || err != nil {
+				if err != nil {
+					return "", err
+				}
+				b.WriteString(converted)
+				i += 2
+				continue
+			}
+		}
+		converted, err := convertCellRefStyle(ref, col, row, toR1C1)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(converted)
+	}
+	return b.String(), nil
+}
+
+// convertCellRefStyle provides a function to convert the reference style of
+// the single cell reference relative to the given cell coordinates. The
+// whole row or column reference in R1C1 reference style will also be
+// converted. Other names will be kept as is.
+func convertCellRefStyle(ref string, col, row int, toR1C1 bool) (string, error) {
+	if toR1C1 {
+		match := a1CellRefRegexp.FindStringSubmatch(ref)
+		if match == nil {
+			return ref, nil
+		}
+		c, err := ColumnNameToNumber(match[2])
+		if err != nil {
+			return "", err
+		}
+		r, _ := strconv.Atoi(match[4])
+		return toR1C1Offset("R", r, row, match[3] == "$") + toR1C1Offset("C", c, col, match[1] == "$"), nil
+	}
+	if r1c1CellRefRegexp.MatchString(ref) {
+		idx := strings.IndexAny(ref, "Cc")
+		r, absRow, err := fromR1C1Offset(ref[1:idx], row, TotalRows)
+		if err != nil {
+			return "", err
+		}
+		c, absCol, err := fromR1C1Offset(ref[idx+1:], col, TotalColumns)
+		if err != nil {
+			return "", err
+		}
+		name, err := ColumnNumberToName(c)
+		return toA1Abs(absCol) + name + toA1Abs(absRow) + strconv.Itoa(r), err
+	}
+	if r1c1RowRefRegexp.MatchString(ref) || r1c1ColRefRegexp.MatchString(ref) {
+		converted, _, err := convertRangeRefStyle(ref, ref, col, row, false)
+		return converted, err
+	}
+	return ref, nil
+}
+
+// convertRangeRefStyle provides a function to convert the reference style
+// of the cell range, whole rows or whole columns reference relative to the
+// given cell coordinates. The ok returns false if the given references are
+// not the whole rows or columns reference.
+func convertRangeRefStyle(from, to string, col, row int, toR1C1 bool) (converted string, ok bool, err error) {
+	var refs [2]string
+	for i, ref := range []string{from, to} {
+		switch {
+		case toR1C1 && a1RowRefRegexp.MatchString(from) && a1RowRefRegexp.MatchString(to):
+			r, _ := strconv.Atoi(strings.TrimPrefix(ref, "$"))
+			if r < 1 || r > TotalRows {
+				return "", true, newInvalidRowNumberError(r)
+			}
+			refs[i] = toR1C1Offset("R", r, row, strings.HasPrefix(ref, "$"))
+		case toR1C1 && a1ColRefRegexp.MatchString(from) && a1ColRefRegexp.MatchString(to):
+			c, err := ColumnNameToNumber(strings.TrimPrefix(ref, "$"))
+			if err != nil {
+				return "", true, err
+			}
+			refs[i] = toR1C1Offset("C", c, col, strings.HasPrefix(ref, "$"))
+		case !toR1C1 && r1c1RowRefRegexp.MatchString(from) && r1c1RowRefRegexp.MatchString(to):
+			r, abs, err := fromR1C1Offset(ref[1:], row, TotalRows)
+			if err != nil {
+				return "", true, err
+			}
+			refs[i] = toA1Abs(abs) + strconv.Itoa(r)
+		case !toR1C1 && r1c1ColRefRegexp.MatchString(from) && r1c1ColRefRegexp.MatchString(to):
+			c, abs, err := fromR1C1Offset(ref[1:], col, TotalColumns)
+			if err != nil {
+				return "", true, err
+			}
+			name, err := ColumnNumberToName(c)
+			if err != nil {
+				return "", true, err
+			}
+			refs[i] = toA1Abs(abs) + name
+		default:
+			if !toR1C1 {
+				return "", false, nil
+			}
+			// convert each cell reference of the cell range
+			if refs[0], err = convertCellRefStyle(from, col, row, true); err != nil {
+				return "", true, err
+			}
+			if refs[1], err = convertCellRefStyle(to, col, row, true); err != nil {
+				return "", true, err
+			}
+			return refs[0] + ":" + refs[1], true, err
+		}
+	}
+	if toR1C1 && refs[0] == refs[1] {
+		return refs[0], true, err
+	}
+	return refs[0] + ":" + refs[1], true, err
+}
+
+// toR1C1Offset provides a function to get the row or column part of the
+// reference in R1C1 reference style by given prefix, row or column number,
+// the row or column number of the base cell and whether the reference is
+// absolute.
+func toR1C1Offset(prefix string, num, base int, abs bool) string {
+	if abs {
+		return prefix + strconv.Itoa(num)
+	}
+	if num == base {
+		return prefix
+	}
+	return prefix + "[" + strconv.Itoa(num-base) + "]"
+}
+
+// fromR1C1Offset provides a function to get the row or column number and
+// whether it is absolute by given row or column part of the reference in
+// R1C1 reference style, without the R or C prefix, and the row or column
+// number of the base cell and the maximum row or column number.
+func fromR1C1Offset(offset string, base, max int) (num int, abs bool, err error) {
+	switch {
+	case offset == "":
+		num = base
+	case strings.HasPrefix(offset, "["):
+		num, err = strconv.Atoi(strings.Trim(offset, "[]"))
+		num += base
+	default:
+		num, err = strconv.Atoi(offset)
+		abs = true
+	}
+	if err == nil && (num < 1 || num > max) {
+		err = fmt.Errorf("invalid reference offset %s", offset)
+	}
+	return
+}
+
+// toA1Abs returns the absolute reference mark in A1 reference style.
+func toA1Abs(abs bool) string {
+	if abs {
+		return "$"
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package excelize
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormulaRefStyle(t *testing.T) {
+	for _, c := range []struct{ cell, a1, r1c1 string }{
+		{"C3", "=SUM(A1:B2,$A$1,A:A,3:3)", "=SUM(R[-2]C[-2]:R[-1]C[-1],R1C1,C[-2],R)"},
+		{"C3", "=C3+$C3+C$3+Sheet1!D4*'My Sheet'!B2", "=RC+RC3+R3C+Sheet1!R[1]C[1]*'My Sheet'!R[-1]C[-1]"},
+		{"B2", "=SUM($A:B,1:$3)+LOG10(A1)", "=SUM(C1:C,R[-1]:R3)+LOG10(R[-1]C[-1])"},
+		{"B2", `=IF(A1="A1",Table1[A1],rate)`, `=IF(R[-1]C[-1]="A1",Table1[A1],rate)`},
+		{"A1", "=$A:$A+$2:$2", "=C1+R2"},
+		{"A1", "A1:B2", "RC:R[1]C[1]"},
+	} {
+		r1c1, err := FormulaToR1C1(c.a1, c.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, c.r1c1, r1c1)
+		a1, err := FormulaToA1(c.r1c1, c.cell)
+		assert.NoError(t, err)
+		assert.Equal(t, c.a1, a1)
+	}
+	// Test convert formula in R1C1 reference style with lower case.
+	a1, err := FormulaToA1("=sum(r[1]c:r2c1)", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "=sum(B3:$A$2)", a1)
+
+	// Test convert formula with invalid cell or reference.
+	_, err = FormulaToR1C1("=A1", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = FormulaToA1("=A1", "A")
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+	_, err = FormulaToR1C1("=0:1", "A1")
+	assert.EqualError(t, err, "invalid row number 0")
+	_, err = FormulaToR1C1("=ZZZ1", "A1")
+	assert.EqualError(t, err, "column number exceeds maximum limit")
+	_, err = FormulaToR1C1("=A1:ZZZ1", "A1")
+	assert.EqualError(t, err, "column number exceeds maximum limit")
+	_, err = FormulaToR1C1("=A:ZZZ", "A1")
+	assert.EqualError(t, err, "column number exceeds maximum limit")
+	for _, formula := range []string{"=R[-1]C", "=RC[-1]", "=R[-1]", "=C[-1]:C", "=R0C1", "=R1048577C1"} {
+		_, err = FormulaToA1(formula, "A1")
+		assert.Error(t, err, formula)
+	}
+	_, err = FormulaToA1("=C16385", "A1")
+	assert.EqualError(t, err, "invalid reference offset 16385")
+
+	// Test set and get cell formula in R1C1 reference style.
+	f := NewFile()
+	assert.EqualError(t, f.SetFormulaRefStyle("RC"), "unsupported formula reference style RC")
+	assert.NoError(t, f.SetFormulaRefStyle("R1C1"))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{1, 2}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A3", "=SUM(R[-2]C:R[-2]C[1])"))
+	formula, err := f.GetCellFormula("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "=SUM(R[-2]C:R[-2]C[1])", formula)
+	result, err := f.CalcCellValue("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", result)
+	assert.EqualError(t, f.SetCellFormula("Sheet1", "A3", "=R[-3]C"), "invalid reference offset [-3]")
+	assert.NoError(t, f.SetFormulaRefStyle("A1"))
+	formula, err = f.GetCellFormula("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "=SUM(A1:B1)", formula)
+	// Test get cell formula in R1C1 reference style with invalid reference.
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A3", "=ZZZ1"))
+	assert.NoError(t, f.SetFormulaRefStyle("R1C1"))
+	_, err = f.GetCellFormula("Sheet1", "A3")
+	assert.EqualError(t, err, "column number exceeds maximum limit")
+
+	// Test get the shared formula in R1C1 reference style.
+	f, err = OpenReader(bytes.NewReader(buildPackage(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1"><v>1</v></c><c r="B1"><v>2</v></c><c r="C1"><v>3</v></c></row><row r="3"><c r="A3"><f t="shared" ref="A3:B3" si="0">A1+B1</f></c><c r="B3"><f t="shared" si="0"/></c></row></sheetData></worksheet>`,
+	})))
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetFormulaRefStyle("R1C1"))
+	for _, cell := range []string{"A3", "B3"} {
+		formula, err = f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, "R[-2]C+R[-2]C[1]", formula, cell)
+	}
+}
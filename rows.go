@@ -12,18 +12,25 @@
 package excelize
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/binary"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"math"
+	"os"
 	"strconv"
+	"time"
 )
 
 // GetRows return all the rows in a sheet by given worksheet name (case
-// sensitive). For example:
+// sensitive). The raw value of the cells will be returned without applying
+// the number format if the RawCellValue of the read options is true. For
+// example:
 //
 //    rows, err := f.GetRows("Sheet1")
 //    if err != nil {
@@ -37,36 +44,57 @@ import (
 //        fmt.Println()
 //    }
 //
-func (f *File) GetRows(sheet string) ([][]string, error) {
+func (f *File) GetRows(sheet string, opts ...ReadOptions) ([][]string, error) {
 	rows, err := f.Rows(sheet)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	results := make([][]string, 0, 64)
 	for rows.Next() {
-		row, err := rows.Columns()
+		row, err := rows.Columns(opts...)
 		if err != nil {
-			break
+			return results, err
 		}
 		results = append(results, row)
 	}
-	return results, nil
+	return results, rows.Error()
 }
 
 // Rows defines an iterator to a sheet.
 type Rows struct {
-	err                        error
-	curRow, totalRow, stashRow int
-	sheet                      string
-	rows                       []xlsxRow
-	f                          *File
-	decoder                    *xml.Decoder
+	err             error
+	curRow, seekRow int
+	pending         bool
+	sheet           string
+	f               *File
+	ws              *xlsxWorksheet
+	rowIdx          int
+	sst             *xlsxSST
+	sstIndex        *sharedStringsIndex
+	sharedFormulas  map[string]string
+	cells           int
+	reader          io.ReadCloser
+	decoder         *xml.Decoder
 }
 
-// Next will return true if find the next row element.
+// Next will return true if find the next row element. The rows which don't
+// exist in the worksheet between the existing rows will be iterated as empty
+// rows.
 func (rows *Rows) Next() bool {
 	rows.curRow++
-	return rows.curRow <= rows.totalRow
+	if rows.pending && rows.seekRow < rows.curRow {
+		// skip the row which columns have not been read
+		if rows.err = rows.skipRow(); rows.err != nil {
+			rows.Close()
+			return false
+		}
+	}
+	if !rows.pending && !rows.seekNext() {
+		rows.Close()
+		return false
+	}
+	return true
 }
 
 // Error will return the error when the error occurs.
@@ -74,20 +102,208 @@ func (rows *Rows) Error() error {
 	return rows.err
 }
 
-// Columns return the current row's column values.
-func (rows *Rows) Columns() ([]string, error) {
-	var (
-		err          error
-		inElement    string
-		row, cellCol int
-		columns      []string
-	)
+// CurrentRow returns the row number of the current row, the rows which don't
+// exist in the worksheet are also counted.
+func (rows *Rows) CurrentRow() int {
+	return rows.curRow
+}
 
-	if rows.stashRow >= rows.curRow {
-		return columns, err
+// Close closes the open worksheet XML reader of the rows iterator. The
+// reader will be closed automatically after all rows have been iterated.
+func (rows *Rows) Close() error {
+	if rows.reader == nil {
+		return nil
 	}
+	err := rows.reader.Close()
+	rows.reader = nil
+	return err
+}
 
-	d := rows.f.sharedStringsReader()
+// seekNext provides a function to find the start of the next row element in
+// the worksheet, and returns false if there are no more rows.
+func (rows *Rows) seekNext() bool {
+	if rows.ws != nil {
+		rows.ws.Lock()
+		defer rows.ws.Unlock()
+		if rows.rowIdx >= len(rows.ws.SheetData.Row) {
+			return false
+		}
+		if r := rows.ws.SheetData.Row[rows.rowIdx].R; r != 0 {
+			rows.seekRow = r
+		} else {
+			rows.seekRow++
+		}
+		rows.pending = true
+		return true
+	}
+	for {
+		token, err := rows.decoder.Token()
+		if err != nil {
+			if err != io.EOF {
+				rows.err = err
+			}
+			return false
+		}
+		startElement, ok := token.(xml.StartElement)
+		if !ok || startElement.Name.Local != "row" {
+			continue
+		}
+		rows.seekRow++
+		for _, attr := range startElement.Attr {
+			if attr.Name.Local == "r" {
+				if rows.seekRow, rows.err = strconv.Atoi(attr.Value); rows.err != nil {
+					return false
+				}
+			}
+		}
+		rows.pending = true
+		return true
+	}
+}
+
+// skipRow provides a function to skip the row element which has been found
+// by seekNext.
+func (rows *Rows) skipRow() error {
+	rows.pending = false
+	if rows.ws != nil {
+		rows.rowIdx++
+		return nil
+	}
+	return rows.decoder.Skip()
+}
+
+// Columns return the current row's column values. The raw value of the cells
+// will be returned without applying the number format if the RawCellValue of
+// the read options is true.
+func (rows *Rows) Columns(opts ...ReadOptions) ([]string, error) {
+	var columns []string
+	raw := parseReadOptions(opts...).RawCellValue
+	err := rows.readRow(func(col int, c *xlsxC) error {
+		val, err := rows.getCellValue(c, raw)
+		if err != nil {
+			return err
+		}
+		columns = append(appendSpace(col-len(columns), columns), val)
+		return nil
+	})
+	return columns, err
+}
+
+// RowCell directly maps the cell in the current row of the rows iterator.
+// Axis is the cell coordinates. Type is the data type of the cell in the
+// worksheet, such as "s" for the shared string, "str" for the formula
+// string, "inlineStr" for the inline string, "b" for the boolean, "e" for the
+// error, "d" for the date in ISO 8601 format, and empty or "n" for the
+// number. StyleID is the style index of the cell. Formula is the formula of
+// the cell as it is returned by GetCellFormula. Value is the raw value of
+// the cell, the text of the shared string and inline string will be
+// resolved. Formatted is the value of the cell after applying the number
+// format.
+type RowCell struct {
+	Axis      string
+	Type      string
+	StyleID   int
+	Formula   string
+	Value     string
+	Formatted string
+}
+
+// Cells return the structured cells of the current row, the cells which
+// don't exist in the worksheet will not be included. For example, load the
+// numeric cells of the worksheet named Sheet1:
+//
+//    rows, err := f.Rows("Sheet1")
+//    if err != nil {
+//        fmt.Println(err)
+//        return
+//    }
+//    for rows.Next() {
+//        cells, err := rows.Cells()
+//        if err != nil {
+//            fmt.Println(err)
+//            return
+//        }
+//        for _, cell := range cells {
+//            if cell.Type == "" || cell.Type == "n" {
+//                value, _ := strconv.ParseFloat(cell.Value, 64)
+//                fmt.Println(cell.Axis, value)
+//            }
+//        }
+//    }
+//
+func (rows *Rows) Cells() ([]RowCell, error) {
+	var cells []RowCell
+	err := rows.readRow(func(col int, c *xlsxC) error {
+		val, err := rows.getCellRawValue(c)
+		if err != nil {
+			return err
+		}
+		cell := RowCell{Axis: c.R, Type: c.T, StyleID: c.S, Value: val}
+		if cell.Axis == "" {
+			if cell.Axis, err = CoordinatesToCellName(col, rows.curRow); err != nil {
+				return err
+			}
+		}
+		if c.F != nil {
+			cell.Formula = c.F.Content
+			if c.F.T == STCellFormulaTypeShared {
+				cell.Formula = rows.sharedFormulas[c.F.Si]
+			}
+		}
+		rows.f.Lock()
+		cell.Formatted = rows.f.formattedValue(c.S, val)
+		rows.f.Unlock()
+		cells = append(cells, cell)
+		return nil
+	})
+	return cells, err
+}
+
+// CellTime provides a function to get the date and time value of the cell in
+// the current row, the serial number of the date will be converted with the
+// 1900 or 1904 date system of the workbook.
+func (rows *Rows) CellTime(cell RowCell) (time.Time, error) {
+	return rows.f.cellTime(cell.Axis, cell.Type, cell.Value)
+}
+
+// readRow provides a function to read the cells of the current row, the
+// given function will be called with the column number for each cell.
+func (rows *Rows) readRow(fn func(col int, c *xlsxC) error) error {
+	var (
+		err     error
+		cellCol int
+	)
+	if !rows.pending || rows.seekRow != rows.curRow {
+		return err
+	}
+	rows.pending = false
+	if rows.sharedFormulas == nil {
+		rows.sharedFormulas = make(map[string]string)
+	}
+	visit := func(c *xlsxC) error {
+		if cellCol, err = getCellCol(cellCol, c); err != nil {
+			return err
+		}
+		if rows.cells++; rows.f.maxCells > 0 && rows.cells > rows.f.maxCells {
+			return newMaxCellsError(rows.sheet, rows.f.maxCells)
+		}
+		if c.F != nil && c.F.T == STCellFormulaTypeShared && c.F.Ref != "" {
+			rows.sharedFormulas[c.F.Si] = c.F.Content
+		}
+		return fn(cellCol, c)
+	}
+	if rows.ws != nil {
+		rows.ws.Lock()
+		defer rows.ws.Unlock()
+		row := &rows.ws.SheetData.Row[rows.rowIdx]
+		rows.rowIdx++
+		for i := range row.C {
+			if err = visit(&row.C[i]); err != nil {
+				return err
+			}
+		}
+		return err
+	}
 	for {
 		token, _ := rows.decoder.Token()
 		if token == nil {
@@ -95,43 +311,73 @@ func (rows *Rows) Columns() ([]string, error) {
 		}
 		switch startElement := token.(type) {
 		case xml.StartElement:
-			inElement = startElement.Name.Local
-			if inElement == "row" {
-				for _, attr := range startElement.Attr {
-					if attr.Name.Local == "r" {
-						row, err = strconv.Atoi(attr.Value)
-						if err != nil {
-							return columns, err
-						}
-						if row > rows.curRow {
-							rows.stashRow = row - 1
-							return columns, err
-						}
-					}
-				}
-			}
-			if inElement == "c" {
-				cellCol++
+			if startElement.Name.Local == "c" {
 				colCell := xlsxC{}
 				_ = rows.decoder.DecodeElement(&colCell, &startElement)
-				if colCell.R != "" {
-					cellCol, _, err = CellNameToCoordinates(colCell.R)
-					if err != nil {
-						return columns, err
-					}
+				if err = visit(&colCell); err != nil {
+					return err
 				}
-				blank := cellCol - len(columns)
-				val, _ := colCell.getValueFrom(rows.f, d)
-				columns = append(appendSpace(blank, columns), val)
 			}
 		case xml.EndElement:
-			inElement = startElement.Name.Local
-			if inElement == "row" {
-				return columns, err
+			if startElement.Name.Local == "row" {
+				return err
 			}
 		}
 	}
-	return columns, err
+	return err
+}
+
+// getCellCol provides a function to get the column number of the cell by
+// given column number of the previous cell in the row.
+func getCellCol(prevCol int, c *xlsxC) (int, error) {
+	if c.R == "" {
+		return prevCol + 1, nil
+	}
+	col, _, err := CellNameToCoordinates(c.R)
+	return col, err
+}
+
+// getCellValue provides a function to get the value of the cell, the number
+// format will not be applied if the raw is true.
+func (rows *Rows) getCellValue(c *xlsxC, raw bool) (string, error) {
+	val, err := rows.getCellRawValue(c)
+	if err != nil || raw {
+		return val, err
+	}
+	rows.f.Lock()
+	defer rows.f.Unlock()
+	return rows.f.formattedValue(c.S, val), nil
+}
+
+// getCellRawValue provides a function to get the raw value of the cell, the
+// text of the shared string and inline string will be resolved. The shared
+// string will be looked up from the disk-backed index of the shared strings
+// table if it has been used.
+func (rows *Rows) getCellRawValue(c *xlsxC) (string, error) {
+	switch c.T {
+	case "s":
+		idx, err := strconv.Atoi(c.V)
+		if c.V == "" || err != nil || idx < 0 {
+			return c.V, nil
+		}
+		if rows.sstIndex != nil {
+			val, ok, err := rows.sstIndex.get(idx)
+			if err != nil || !ok {
+				return c.V, err
+			}
+			return val, nil
+		}
+		rows.f.Lock()
+		defer rows.f.Unlock()
+		if len(rows.sst.SI) > idx {
+			return rows.sst.SI[idx].String(), nil
+		}
+	case "inlineStr":
+		if c.IS != nil {
+			return c.IS.String(), nil
+		}
+	}
+	return c.V, nil
 }
 
 // appendSpace append blank characters to slice by given length and source slice.
@@ -152,7 +398,12 @@ func (err ErrSheetNotExist) Error() string {
 }
 
 // Rows returns a rows iterator, used for streaming reading data for a
-// worksheet with a large data. For example:
+// worksheet with a large data. The worksheet which hasn't been loaded into
+// memory will be read from the spreadsheet package in a single pass, and the
+// shared strings will be looked up from the disk-backed index if the size of
+// the shared strings table exceeds the UnzipXMLSizeLimit of the options. The
+// Close function of the iterator should be called if the iteration stopped
+// before all rows have been read. For example:
 //
 //    rows, err := f.Rows("Sheet1")
 //    if err != nil {
@@ -175,44 +426,23 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 	if !ok {
 		return nil, ErrSheetNotExist{sheet}
 	}
-	if f.Sheet[name] != nil {
-		// flush data
-		output, _ := xml.Marshal(f.Sheet[name])
-		f.saveFileList(name, f.replaceNameSpaceBytes(name, output))
+	var err error
+	rows := Rows{f: f, sheet: name, ws: f.Sheet[name]}
+	if rows.sstIndex, err = f.sharedStringsIndexReader(); err != nil {
+		return nil, err
 	}
-	var (
-		err       error
-		inElement string
-		row       int
-		rows      Rows
-	)
-	decoder := f.xmlNewDecoder(bytes.NewReader(f.readXML(name)))
-	for {
-		token, _ := decoder.Token()
-		if token == nil {
-			break
-		}
-		switch startElement := token.(type) {
-		case xml.StartElement:
-			inElement = startElement.Name.Local
-			if inElement == "row" {
-				row++
-				for _, attr := range startElement.Attr {
-					if attr.Name.Local == "r" {
-						row, err = strconv.Atoi(attr.Value)
-						if err != nil {
-							return &rows, err
-						}
-					}
-				}
-				rows.totalRow = row
-			}
-		default:
-		}
+	if rows.sstIndex == nil {
+		rows.sst = f.sharedStringsReadOnly()
+	} else {
+		rows.sst = &xlsxSST{}
+	}
+	if rows.ws != nil {
+		return &rows, nil
 	}
-	rows.f = f
-	rows.sheet = name
-	rows.decoder = f.xmlNewDecoder(bytes.NewReader(f.readXML(name)))
+	if rows.reader, err = f.openPart(name); err != nil {
+		return nil, err
+	}
+	rows.decoder = f.xmlNewDecoder(rows.reader)
 	return &rows, nil
 }
 
@@ -242,7 +472,7 @@ func (f *File) SetRowHeight(sheet string, row int, height float64) error {
 // getRowHeight provides a function to get row height in pixels by given sheet
 // name and row index.
 func (f *File) getRowHeight(sheet string, row int) int {
-	xlsx, _ := f.workSheetReader(sheet)
+	xlsx, _ := f.workSheetReadOnly(sheet)
 	for i := range xlsx.SheetData.Row {
 		v := &xlsx.SheetData.Row[i]
 		if v.R == row+1 && v.Ht != 0 {
@@ -263,7 +493,7 @@ func (f *File) GetRowHeight(sheet string, row int) (float64, error) {
 		return defaultRowHeightPixels, newInvalidRowNumberError(row)
 	}
 	var ht = defaultRowHeight
-	ws, err := f.workSheetReader(sheet)
+	ws, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return ht, err
 	}
@@ -283,46 +513,169 @@ func (f *File) GetRowHeight(sheet string, row int) (float64, error) {
 }
 
 // sharedStringsReader provides a function to get the pointer to the structure
-// after deserialization of xl/sharedStrings.xml.
+// after deserialization of xl/sharedStrings.xml. The shared strings table
+// will be serialized when saving the spreadsheet.
 func (f *File) sharedStringsReader() *xlsxSST {
+	sst := f.sharedStringsReadOnly()
+	f.setModified("xl/sharedStrings.xml")
+	return sst
+}
+
+// sharedStringsReadOnly provides a function to get the pointer to the
+// structure after deserialization of xl/sharedStrings.xml for reading only.
+func (f *File) sharedStringsReadOnly() *xlsxSST {
 	var err error
 
 	f.Lock()
 	defer f.Unlock()
-	if f.SharedStrings == nil {
-		var sharedStrings xlsxSST
-		ss := f.readXML("xl/sharedStrings.xml")
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(ss))).
-			Decode(&sharedStrings); err != nil && err != io.EOF {
-			log.Printf("xml decode error: %s", err)
-		}
-		if sharedStrings.UniqueCount == 0 {
-			sharedStrings.UniqueCount = sharedStrings.Count
-		}
-		f.SharedStrings = &sharedStrings
-		for i := range sharedStrings.SI {
-			if sharedStrings.SI[i].T != nil {
-				f.sharedStringsMap[sharedStrings.SI[i].T.Val] = i
-			}
+	if f.SharedStrings != nil {
+		return f.SharedStrings
+	}
+	var sharedStrings xlsxSST
+	ss := f.readXML("xl/sharedStrings.xml")
+	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(ss))).
+		Decode(&sharedStrings); err != nil && err != io.EOF {
+		log.Printf("xml decode error: %s", err)
+	}
+	if sharedStrings.UniqueCount == 0 {
+		sharedStrings.UniqueCount = sharedStrings.Count
+	}
+	f.SharedStrings = &sharedStrings
+	f.setUnmodified("xl/sharedStrings.xml", &sharedStrings)
+	for i := range sharedStrings.SI {
+		if sharedStrings.SI[i].T != nil {
+			f.sharedStringsMap[sharedStrings.SI[i].T.Val] = i
+		}
+	}
+	f.addContentTypePart(0, "sharedStrings")
+	rels := f.relsReader("xl/_rels/workbook.xml.rels")
+	for _, rel := range rels.Relationships {
+		if rel.Target == "sharedStrings.xml" {
+			return &sharedStrings
 		}
-		f.addContentTypePart(0, "sharedStrings")
-		rels := f.relsReader("xl/_rels/workbook.xml.rels")
-		for _, rel := range rels.Relationships {
-			if rel.Target == "sharedStrings.xml" {
-				return f.SharedStrings
+	}
+	// Update xl/_rels/workbook.xml.rels
+	f.addRels("xl/_rels/workbook.xml.rels", SourceRelationshipSharedStrings, "sharedStrings.xml", "")
+	return &sharedStrings
+}
+
+// sharedStringsIndex directly maps the disk-backed index of the shared
+// strings table. The text of the shared string items are stored in the data
+// file, and the offsets of the items in the data file are stored in the index
+// file as the 8 bytes big-endian integers.
+type sharedStringsIndex struct {
+	data, index *os.File
+	count       int
+	size        int64
+}
+
+// sharedStringsIndexReader provides a function to get the disk-backed index
+// of the shared strings table, the index will be created in the system
+// temporary directory for the shared strings table which size exceeds the
+// UnzipXMLSizeLimit of the options, and hasn't been loaded into memory. It
+// returns nil if the index isn't needed.
+func (f *File) sharedStringsIndexReader() (*sharedStringsIndex, error) {
+	f.Lock()
+	defer f.Unlock()
+	if f.SharedStrings != nil {
+		return nil, nil
+	}
+	if f.sstIndex != nil || f.unzipSizeLimit <= 0 || f.partSize("xl/sharedStrings.xml") <= f.unzipSizeLimit {
+		return f.sstIndex, nil
+	}
+	reader, err := f.openPart("xl/sharedStrings.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	idx := &sharedStringsIndex{}
+	if idx.data, err = ioutil.TempFile(os.TempDir(), "excelize-"); err != nil {
+		return nil, err
+	}
+	if idx.index, err = ioutil.TempFile(os.TempDir(), "excelize-"); err != nil {
+		idx.close()
+		return nil, err
+	}
+	data, index := bufio.NewWriter(idx.data), bufio.NewWriter(idx.index)
+	decoder, offset := f.xmlNewDecoder(reader), make([]byte, 8)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			idx.close()
+			return nil, err
+		}
+		if startElement, ok := token.(xml.StartElement); ok && startElement.Name.Local == "si" {
+			var si xlsxSI
+			if err = decoder.DecodeElement(&si, &startElement); err != nil {
+				idx.close()
+				return nil, err
 			}
+			binary.BigEndian.PutUint64(offset, uint64(idx.size))
+			val := si.String()
+			_, _ = index.Write(offset)
+			_, _ = data.WriteString(val)
+			idx.count, idx.size = idx.count+1, idx.size+int64(len(val))
 		}
-		// Update xl/_rels/workbook.xml.rels
-		f.addRels("xl/_rels/workbook.xml.rels", SourceRelationshipSharedStrings, "sharedStrings.xml", "")
 	}
+	if err = data.Flush(); err == nil {
+		err = index.Flush()
+	}
+	if err != nil {
+		idx.close()
+		return nil, err
+	}
+	f.sstIndex = idx
+	return idx, nil
+}
+
+// get provides a function to get the text of the shared string item by given
+// index, and returns false if the item doesn't exist.
+func (idx *sharedStringsIndex) get(i int) (string, bool, error) {
+	if i < 0 || i >= idx.count {
+		return "", false, nil
+	}
+	offsets := make([]byte, 16)
+	if i+1 == idx.count {
+		offsets = offsets[:8]
+	}
+	if _, err := idx.index.ReadAt(offsets, int64(i)*8); err != nil {
+		return "", false, err
+	}
+	start, end := int64(binary.BigEndian.Uint64(offsets[:8])), idx.size
+	if len(offsets) == 16 {
+		end = int64(binary.BigEndian.Uint64(offsets[8:]))
+	}
+	buf := make([]byte, end-start)
+	if _, err := idx.data.ReadAt(buf, start); err != nil && err != io.EOF {
+		return "", false, err
+	}
+	return string(buf), true, nil
+}
 
-	return f.SharedStrings
+// close provides a function to close and remove the files of the index.
+func (idx *sharedStringsIndex) close() error {
+	var err error
+	for _, file := range []*os.File{idx.data, idx.index} {
+		if file == nil {
+			continue
+		}
+		if e := file.Close(); e != nil && err == nil {
+			err = e
+		}
+		if e := os.Remove(file.Name()); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
 }
 
 // getValueFrom return a value from a column/row cell, this function is
 // inteded to be used with for range on rows an argument with the xlsx opened
-// file.
-func (xlsx *xlsxC) getValueFrom(f *File, d *xlsxSST) (string, error) {
+// file. The number format will not be applied if the raw is true.
+func (xlsx *xlsxC) getValueFrom(f *File, d *xlsxSST, raw bool) (string, error) {
 	f.Lock()
 	defer f.Unlock()
 	switch xlsx.T {
@@ -331,19 +684,19 @@ func (xlsx *xlsxC) getValueFrom(f *File, d *xlsxSST) (string, error) {
 			xlsxSI := 0
 			xlsxSI, _ = strconv.Atoi(xlsx.V)
 			if len(d.SI) > xlsxSI {
-				return f.formattedValue(xlsx.S, d.SI[xlsxSI].String()), nil
+				return f.formatValue(xlsx.S, raw, d.SI[xlsxSI].String()), nil
 			}
 		}
-		return f.formattedValue(xlsx.S, xlsx.V), nil
+		return f.formatValue(xlsx.S, raw, xlsx.V), nil
 	case "str":
-		return f.formattedValue(xlsx.S, xlsx.V), nil
+		return f.formatValue(xlsx.S, raw, xlsx.V), nil
 	case "inlineStr":
 		if xlsx.IS != nil {
-			return f.formattedValue(xlsx.S, xlsx.IS.String()), nil
+			return f.formatValue(xlsx.S, raw, xlsx.IS.String()), nil
 		}
-		return f.formattedValue(xlsx.S, xlsx.V), nil
+		return f.formatValue(xlsx.S, raw, xlsx.V), nil
 	default:
-		return f.formattedValue(xlsx.S, xlsx.V), nil
+		return f.formatValue(xlsx.S, raw, xlsx.V), nil
 	}
 }
 
@@ -377,7 +730,7 @@ func (f *File) GetRowVisible(sheet string, row int) (bool, error) {
 		return false, newInvalidRowNumberError(row)
 	}
 
-	xlsx, err := f.workSheetReader(sheet)
+	xlsx, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return false, err
 	}
@@ -419,7 +772,7 @@ func (f *File) GetRowOutlineLevel(sheet string, row int) (uint8, error) {
 	if row < 1 {
 		return 0, newInvalidRowNumberError(row)
 	}
-	xlsx, err := f.workSheetReader(sheet)
+	xlsx, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return 0, err
 	}
@@ -3,6 +3,7 @@ package excelize
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -44,8 +45,11 @@ func TestRows(t *testing.T) {
 
 	f = NewFile()
 	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet><sheetData><row r="1"><c r="A1" t="s"><v>1</v></c></row><row r="A"><c r="2" t="str"><v>B</v></c></row></sheetData></worksheet>`)
-	_, err = f.Rows("Sheet1")
-	assert.EqualError(t, err, `strconv.Atoi: parsing "A": invalid syntax`)
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.False(t, rows.Next())
+	assert.EqualError(t, rows.Error(), `strconv.Atoi: parsing "A": invalid syntax`)
 }
 
 func TestRowsIterator(t *testing.T) {
@@ -132,10 +136,117 @@ func TestRowHeight(t *testing.T) {
 	convertColWidthToPixels(0)
 }
 
+func TestRowsSharedStringsIndex(t *testing.T) {
+	expected, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	expectedRows, err := expected.GetRows("Sheet2")
+	assert.NoError(t, err)
+
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"), Options{LazyLoad: true, UnzipXMLSizeLimit: 1})
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRows, rows)
+	assert.Nil(t, f.SharedStrings)
+	if !assert.NotNil(t, f.sstIndex) {
+		t.FailNow()
+	}
+	val, ok, err := f.sstIndex.get(f.sstIndex.count)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, val)
+	dataFile, indexFile := f.sstIndex.data.Name(), f.sstIndex.index.Name()
+	assert.NoError(t, f.Close())
+	for _, name := range []string{dataFile, indexFile} {
+		_, err = os.Stat(name)
+		assert.True(t, os.IsNotExist(err))
+	}
+
+	// Test iterate rows of the worksheet in memory.
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet2", "A1", "Display"))
+	expectedRows[0][0] = "Display"
+	rows, err = f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRows, rows)
+}
+
+func TestRowsCells(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(`{"number_format":2}`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "text"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 1.5))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D1", true))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A3", "=B1*2"))
+	expected := [][]RowCell{
+		{
+			{Axis: "A1", Type: "s", Value: "text", Formatted: "text"},
+			{Axis: "B1", StyleID: style, Value: "1.5", Formatted: "1.50"},
+			{Axis: "D1", Type: "b", Value: "1", Formatted: "1"},
+		},
+		nil,
+		{{Axis: "A3", Formula: "=B1*2"}},
+	}
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	for _, f := range []*File{f, nil} {
+		if f == nil {
+			// Test read cells from the worksheet which hasn't been loaded.
+			f, err = OpenReader(buf)
+			assert.NoError(t, err)
+		}
+		rows, err := f.Rows("Sheet1")
+		assert.NoError(t, err)
+		var cells [][]RowCell
+		for rows.Next() {
+			row, err := rows.Cells()
+			assert.NoError(t, err)
+			cells = append(cells, row)
+		}
+		assert.NoError(t, rows.Error())
+		assert.Equal(t, expected, cells)
+	}
+
+	// Test read shared formula, inline string and the cell without reference.
+	f = NewFile()
+	f.Sheet["xl/worksheets/sheet1.xml"] = nil
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet><sheetData><row r="1"><c r="A1"><f t="shared" ref="A1:A2" si="0">B1+1</f><v>1</v></c><c t="inlineStr"><is><t>inline</t></is></c></row><row r="2"><c r="A2"><f t="shared" si="0"/><v>1</v></c><c t="s"><v>-1</v></c></row></sheetData></worksheet>`)
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var cells [][]RowCell
+	for rows.Next() {
+		row, err := rows.Cells()
+		assert.NoError(t, err)
+		cells = append(cells, row)
+	}
+	assert.Equal(t, [][]RowCell{
+		{
+			{Axis: "A1", Formula: "B1+1", Value: "1", Formatted: "1"},
+			{Axis: "B1", Type: "inlineStr", Value: "inline", Formatted: "inline"},
+		},
+		{
+			{Axis: "A2", Formula: "B1+1", Value: "1", Formatted: "1"},
+			{Axis: "B2", Type: "s", Value: "-1", Formatted: "-1"},
+		},
+	}, cells)
+
+	// Test read cells with invalid cell reference.
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet><sheetData><row r="1"><c r="A"><v>1</v></c></row></sheetData></worksheet>`)
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	_, err = rows.Cells()
+	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
+}
+
 func TestColumns(t *testing.T) {
 	f := NewFile()
 	rows, err := f.Rows("Sheet1")
 	assert.NoError(t, err)
+	rows.ws = nil
 
 	rows.decoder = f.xmlNewDecoder(bytes.NewReader([]byte(`<worksheet><sheetData><row r="2"><c r="A1" t="s"><v>1</v></c></row></sheetData></worksheet>`)))
 	_, err = rows.Columns()
@@ -146,16 +257,17 @@ func TestColumns(t *testing.T) {
 	assert.NoError(t, err)
 
 	rows.decoder = f.xmlNewDecoder(bytes.NewReader([]byte(`<worksheet><sheetData><row r="A"><c r="A1" t="s"><v>1</v></c></row><row r="A"><c r="2" t="str"><v>B</v></c></row></sheetData></worksheet>`)))
-	rows.stashRow, rows.curRow = 0, 1
-	_, err = rows.Columns()
-	assert.EqualError(t, err, `strconv.Atoi: parsing "A": invalid syntax`)
+	rows.curRow = 1
+	assert.False(t, rows.Next())
+	assert.EqualError(t, rows.Error(), `strconv.Atoi: parsing "A": invalid syntax`)
 
 	rows.decoder = f.xmlNewDecoder(bytes.NewReader([]byte(`<worksheet><sheetData><row r="1"><c r="A1" t="s"><v>1</v></c></row><row r="A"><c r="2" t="str"><v>B</v></c></row></sheetData></worksheet>`)))
 	_, err = rows.Columns()
 	assert.NoError(t, err)
 
-	rows.curRow = 3
+	rows.curRow = 0
 	rows.decoder = f.xmlNewDecoder(bytes.NewReader([]byte(`<worksheet><sheetData><row r="1"><c r="A" t="s"><v>1</v></c></row></sheetData></worksheet>`)))
+	assert.True(t, rows.Next())
 	_, err = rows.Columns()
 	assert.EqualError(t, err, `cannot convert cell "A" to coordinates: invalid cell name "A"`)
 
@@ -165,6 +277,34 @@ func TestColumns(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRowsSparse(t *testing.T) {
+	f := NewFile()
+	newRows := func(sheetXML string) *Rows {
+		return &Rows{f: f, sst: f.sharedStringsReader(), decoder: f.xmlNewDecoder(bytes.NewReader([]byte(sheetXML)))}
+	}
+	rows := newRows(`<worksheet><sheetData><row r="2"><c r="A1" t="str"><v>A</v></c></row></sheetData></worksheet>`)
+	assert.True(t, rows.Next())
+	assert.Equal(t, 1, rows.CurrentRow())
+	columns, err := rows.Columns()
+	assert.NoError(t, err)
+	assert.Empty(t, columns)
+	assert.True(t, rows.Next())
+	assert.Equal(t, 2, rows.CurrentRow())
+	columns, err = rows.Columns()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A"}, columns)
+	assert.False(t, rows.Next())
+	assert.NoError(t, rows.Error())
+
+	// Test skip the row which columns have not been read.
+	rows = newRows(`<worksheet><sheetData><row r="1"><c r="A1" t="str"><v>A</v></c></row><row><c t="str"><v>B</v></c></row></sheetData></worksheet>`)
+	assert.True(t, rows.Next())
+	assert.True(t, rows.Next())
+	columns, err = rows.Columns()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B"}, columns)
+}
+
 func TestSharedStringsReader(t *testing.T) {
 	f := NewFile()
 	f.XLSX["xl/sharedStrings.xml"] = MacintoshCyrillicCharset
@@ -823,7 +963,7 @@ func TestGetValueFrom(t *testing.T) {
 	c := &xlsxC{T: "inlineStr"}
 	f := NewFile()
 	d := &xlsxSST{}
-	val, err := c.getValueFrom(f, d)
+	val, err := c.getValueFrom(f, d, false)
 	assert.NoError(t, err)
 	assert.Equal(t, "", val)
 }
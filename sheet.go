@@ -89,42 +89,59 @@ func (f *File) contentTypesWriter() {
 }
 
 // workbookReader provides a function to get the pointer to the xl/workbook.xml
-// structure after deserialization.
+// structure after deserialization. The workbook will be serialized when
+// saving the spreadsheet.
 func (f *File) workbookReader() *xlsxWorkbook {
+	wb := f.workbookReadOnly()
+	f.setModified("xl/workbook.xml")
+	return wb
+}
+
+// workbookReadOnly provides a function to get the pointer to the
+// xl/workbook.xml structure after deserialization for reading only.
+func (f *File) workbookReadOnly() *xlsxWorkbook {
 	var err error
-	if f.WorkBook == nil {
-		f.WorkBook = new(xlsxWorkbook)
-		if _, ok := f.xmlAttr["xl/workbook.xml"]; !ok {
-			d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/workbook.xml"))))
-			f.xmlAttr["xl/workbook.xml"] = append(f.xmlAttr["xl/workbook.xml"], getRootElement(d)...)
-			f.addNameSpaces("xl/workbook.xml", SourceRelationship)
-		}
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/workbook.xml")))).
-			Decode(f.WorkBook); err != nil && err != io.EOF {
-			log.Printf("xml decode error: %s", err)
-		}
+	if f.WorkBook != nil {
+		return f.WorkBook
+	}
+	wb := new(xlsxWorkbook)
+	if _, ok := f.xmlAttr["xl/workbook.xml"]; !ok {
+		d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/workbook.xml"))))
+		f.xmlAttr["xl/workbook.xml"] = addNamespacePrefixes(append(f.xmlAttr["xl/workbook.xml"], getRootElement(d)...))
+		f.addNameSpaces("xl/workbook.xml", SourceRelationship)
 	}
-	return f.WorkBook
+	content := namespaceStrictToTransitional(f.readXML("xl/workbook.xml"))
+	if err = f.xmlNewDecoder(bytes.NewReader(content)).
+		Decode(wb); err != nil && err != io.EOF {
+		log.Printf("xml decode error: %s", err)
+	}
+	f.setUnknownElements("xl/workbook.xml", content, wb.AnyElements)
+	f.WorkBook = wb
+	f.setUnmodified("xl/workbook.xml", wb)
+	return wb
 }
 
 // workBookWriter provides a function to save xl/workbook.xml after serialize
-// structure.
+// structure, the workbook which has not been modified will be skipped.
 func (f *File) workBookWriter() {
-	if f.WorkBook != nil {
+	if f.WorkBook != nil && !f.isUnmodified("xl/workbook.xml", f.WorkBook) {
 		output, _ := xml.Marshal(f.WorkBook)
+		output = f.spliceUnknownElements("xl/workbook.xml", output)
 		f.saveFileList("xl/workbook.xml", replaceRelationshipsBytes(f.replaceNameSpaceBytes("xl/workbook.xml", output)))
 	}
 }
 
 // workSheetWriter provides a function to save xl/worksheets/sheet%d.xml after
-// serialize structure.
+// serialize structure, the worksheets which have not been modified will be
+// skipped.
 func (f *File) workSheetWriter() {
 	for p, sheet := range f.Sheet {
-		if sheet != nil {
+		if sheet != nil && !f.isUnmodified(p, sheet) {
 			for k, v := range sheet.SheetData.Row {
 				f.Sheet[p].SheetData.Row[k].C = trimCell(v.C)
 			}
 			output, _ := xml.Marshal(sheet)
+			output = f.spliceUnknownElements(p, output)
 			f.saveFileList(p, replaceRelationshipsBytes(f.replaceNameSpaceBytes(p, output)))
 			ok := f.checked[p]
 			if ok {
@@ -271,7 +288,7 @@ func (f *File) SetActiveSheet(index int) {
 // spreadsheet. If not found the active sheet will be return integer 0.
 func (f *File) GetActiveSheetIndex() (index int) {
 	var sheetID = f.getActiveSheetID()
-	wb := f.workbookReader()
+	wb := f.workbookReadOnly()
 	if wb != nil {
 		for idx, sheet := range wb.Sheets.Sheet {
 			if sheet.SheetID == sheetID {
@@ -285,7 +302,7 @@ func (f *File) GetActiveSheetIndex() (index int) {
 // getActiveSheetID provides a function to get active sheet index of the
 // spreadsheet. If not found the active sheet will be return integer 0.
 func (f *File) getActiveSheetID() int {
-	wb := f.workbookReader()
+	wb := f.workbookReadOnly()
 	if wb != nil {
 		if wb.BookViews != nil && len(wb.BookViews.WorkBookView) > 0 {
 			activeTab := wb.BookViews.WorkBookView[0].ActiveTab
@@ -308,6 +325,9 @@ func (f *File) getActiveSheetID() int {
 func (f *File) SetSheetName(oldName, newName string) {
 	oldName = trimSheetName(oldName)
 	newName = trimSheetName(newName)
+	if newName == oldName {
+		return
+	}
 	content := f.workbookReader()
 	for k, v := range content.Sheets.Sheet {
 		if v.Name == oldName {
@@ -322,7 +342,7 @@ func (f *File) SetSheetName(oldName, newName string) {
 // spreadsheet by given worksheet ID. If given sheet ID is invalid, will
 // return an empty string.
 func (f *File) getSheetNameByID(ID int) string {
-	wb := f.workbookReader()
+	wb := f.workbookReadOnly()
 	if wb == nil || ID < 1 {
 		return ""
 	}
@@ -384,7 +404,7 @@ func (f *File) GetSheetIndex(name string) int {
 //    }
 //
 func (f *File) GetSheetMap() map[int]string {
-	wb := f.workbookReader()
+	wb := f.workbookReadOnly()
 	sheetMap := map[int]string{}
 	if wb != nil {
 		for _, sheet := range wb.Sheets.Sheet {
@@ -397,7 +417,7 @@ func (f *File) GetSheetMap() map[int]string {
 // GetSheetList provides a function to get worksheets, chart sheets, and
 // dialog sheets name list of the workbook.
 func (f *File) GetSheetList() (list []string) {
-	wb := f.workbookReader()
+	wb := f.workbookReadOnly()
 	if wb != nil {
 		for _, sheet := range wb.Sheets.Sheet {
 			list = append(list, sheet.Name)
@@ -409,7 +429,7 @@ func (f *File) GetSheetList() (list []string) {
 // getSheetMap provides a function to get worksheet name and XML file path map
 // of XLSX.
 func (f *File) getSheetMap() map[string]string {
-	content := f.workbookReader()
+	content := f.workbookReadOnly()
 	rels := f.relsReader("xl/_rels/workbook.xml.rels")
 	maps := map[string]string{}
 	for _, v := range content.Sheets.Sheet {
@@ -484,7 +504,9 @@ func (f *File) DeleteSheet(name string) {
 			delete(f.XLSX, rels)
 			delete(f.Relationships, rels)
 			delete(f.Sheet, sheetXML)
+			f.setModified(sheetXML)
 			delete(f.xmlAttr, sheetXML)
+			delete(f.unknownElements, sheetXML)
 			f.SheetCount--
 		}
 	}
@@ -561,11 +583,16 @@ func (f *File) copySheet(from, to int) error {
 	fromRels := "xl/worksheets/_rels/sheet" + strconv.Itoa(f.getSheetID(fromSheet)) + ".xml.rels"
 	_, ok := f.XLSX[fromRels]
 	if ok {
-		f.XLSX[toRels] = f.XLSX[fromRels]
+		if f.XLSX[toRels], err = f.readBytes(fromRels); err != nil {
+			return err
+		}
 	}
 	fromSheetXMLPath, _ := f.sheetMap[trimSheetName(fromSheet)]
 	fromSheetAttr, _ := f.xmlAttr[fromSheetXMLPath]
 	f.xmlAttr[path] = fromSheetAttr
+	if unknown, ok := f.unknownElements[fromSheetXMLPath]; ok {
+		f.unknownElements[path] = unknown
+	}
 	return err
 }
 
@@ -746,7 +773,7 @@ func (f *File) SetPanes(sheet, panes string) error {
 //    f.GetSheetVisible("Sheet1")
 //
 func (f *File) GetSheetVisible(name string) bool {
-	content := f.workbookReader()
+	content := f.workbookReadOnly()
 	visible := false
 	for k, v := range content.Sheets.Sheet {
 		if v.Name == trimSheetName(name) {
@@ -802,8 +829,13 @@ func (f *File) searchSheet(name, value string, regSearch bool) (result []string,
 		d                   *xlsxSST
 	)
 
-	d = f.sharedStringsReader()
-	decoder := f.xmlNewDecoder(bytes.NewReader(f.readXML(name)))
+	d = f.sharedStringsReadOnly()
+	reader, err := f.openPart(name)
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	decoder := f.xmlNewDecoder(reader)
 	for {
 		var token xml.Token
 		token, err = decoder.Token()
@@ -825,7 +857,7 @@ func (f *File) searchSheet(name, value string, regSearch bool) (result []string,
 			if inElement == "c" {
 				colCell := xlsxC{}
 				_ = decoder.DecodeElement(&colCell, &startElement)
-				val, _ := colCell.getValueFrom(f, d)
+				val, _ := colCell.getValueFrom(f, d, false)
 				if regSearch {
 					regex := regexp.MustCompile(value)
 					if !regex.MatchString(val) {
@@ -1336,7 +1368,7 @@ func (f *File) SetPageLayout(sheet string, opts ...PageLayoutOption) error {
 //   FitToHeight(int)
 //   FitToWidth(int)
 func (f *File) GetPageLayout(sheet string, opts ...PageLayoutOptionPtr) error {
-	s, err := f.workSheetReader(sheet)
+	s, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return err
 	}
@@ -1421,7 +1453,7 @@ func (f *File) DeleteDefinedName(definedName *DefinedName) error {
 // or worksheet.
 func (f *File) GetDefinedName() []DefinedName {
 	var definedNames []DefinedName
-	wb := f.workbookReader()
+	wb := f.workbookReadOnly()
 	if wb.DefinedNames != nil {
 		for _, dn := range wb.DefinedNames.DefinedName {
 			definedName := DefinedName{
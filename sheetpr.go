@@ -182,7 +182,7 @@ func (f *File) SetSheetPrOptions(name string, opts ...SheetPrOption) error {
 //   AutoPageBreaks(bool)
 //   OutlineSummaryBelow(bool)
 func (f *File) GetSheetPrOptions(name string, opts ...SheetPrOptionPtr) error {
-	sheet, err := f.workSheetReader(name)
+	sheet, err := f.workSheetReadOnly(name)
 	if err != nil {
 		return err
 	}
@@ -348,7 +348,7 @@ func (f *File) SetPageMargins(sheet string, opts ...PageMarginsOptions) error {
 //   PageMarginRight(float64)
 //   PageMarginTop(float64)
 func (f *File) GetPageMargins(sheet string, opts ...PageMarginsOptionsPtr) error {
-	s, err := f.workSheetReader(sheet)
+	s, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return err
 	}
@@ -542,7 +542,7 @@ func (f *File) SetSheetFormatPr(sheet string, opts ...SheetFormatPrOptions) erro
 //   ThickTop(bool)
 //   ThickBottom(bool)
 func (f *File) GetSheetFormatPr(sheet string, opts ...SheetFormatPrOptionsPtr) error {
-	s, err := f.workSheetReader(sheet)
+	s, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return err
 	}
@@ -1002,34 +1002,46 @@ func is12HourTime(format string) bool {
 }
 
 // stylesReader provides a function to get the pointer to the structure after
-// deserialization of xl/styles.xml.
+// deserialization of xl/styles.xml. The style sheet will be serialized when
+// saving the spreadsheet.
 func (f *File) stylesReader() *xlsxStyleSheet {
+	styles := f.stylesReadOnly()
+	f.setModified("xl/styles.xml")
+	return styles
+}
+
+// stylesReadOnly provides a function to get the pointer to the structure
+// after deserialization of xl/styles.xml for reading only.
+func (f *File) stylesReadOnly() *xlsxStyleSheet {
 	var err error
 
-	if f.Styles == nil {
-		f.Styles = new(xlsxStyleSheet)
-		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/styles.xml")))).
-			Decode(f.Styles); err != nil && err != io.EOF {
-			log.Printf("xml decode error: %s", err)
-		}
+	if f.Styles != nil {
+		return f.Styles
 	}
-
-	return f.Styles
+	styles := new(xlsxStyleSheet)
+	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML("xl/styles.xml")))).
+		Decode(styles); err != nil && err != io.EOF {
+		log.Printf("xml decode error: %s", err)
+	}
+	f.Styles = styles
+	f.setUnmodified("xl/styles.xml", styles)
+	return styles
 }
 
 // styleSheetWriter provides a function to save xl/styles.xml after serialize
-// structure.
+// structure, the style sheet which has not been modified will be skipped.
 func (f *File) styleSheetWriter() {
-	if f.Styles != nil {
+	if f.Styles != nil && !f.isUnmodified("xl/styles.xml", f.Styles) {
 		output, _ := xml.Marshal(f.Styles)
 		f.saveFileList("xl/styles.xml", f.replaceNameSpaceBytes("xl/styles.xml", output))
 	}
 }
 
 // sharedStringsWriter provides a function to save xl/sharedStrings.xml after
-// serialize structure.
+// serialize structure, the shared strings table which has not been modified
+// will be skipped.
 func (f *File) sharedStringsWriter() {
-	if f.SharedStrings != nil {
+	if f.SharedStrings != nil && !f.isUnmodified("xl/sharedStrings.xml", f.SharedStrings) {
 		output, _ := xml.Marshal(f.SharedStrings)
 		f.saveFileList("xl/sharedStrings.xml", f.replaceNameSpaceBytes("xl/sharedStrings.xml", output))
 	}
@@ -2513,17 +2525,38 @@ func setCellXfs(style *xlsxStyleSheet, fontID, numFmtID, fillID, borderID int, a
 }
 
 // GetCellStyle provides a function to get cell style index by given worksheet
-// name and cell coordinates.
+// name and cell coordinates. The style of the row or column will be returned
+// if the cell doesn't exist or hasn't been styled, and the worksheet will not
+// be changed.
 func (f *File) GetCellStyle(sheet, axis string) (int, error) {
-	xlsx, err := f.workSheetReader(sheet)
+	xlsx, err := f.workSheetReadOnly(sheet)
 	if err != nil {
 		return 0, err
 	}
-	cellData, col, _, err := f.prepareCell(xlsx, sheet, axis)
+	xlsx.Lock()
+	defer xlsx.Unlock()
+	if axis, err = f.mergeCellsParser(xlsx, axis); err != nil {
+		return 0, err
+	}
+	col, row, err := CellNameToCoordinates(axis)
 	if err != nil {
 		return 0, err
 	}
-	return f.prepareCellStyle(xlsx, col, cellData.S), err
+	for rowIdx := range xlsx.SheetData.Row {
+		rowData := &xlsx.SheetData.Row[rowIdx]
+		if rowData.R != row {
+			continue
+		}
+		for colIdx := range rowData.C {
+			if cellData := &rowData.C[colIdx]; cellData.R == axis && cellData.S != 0 {
+				return cellData.S, err
+			}
+		}
+		if rowData.CustomFormat {
+			return rowData.S, err
+		}
+	}
+	return f.prepareCellStyle(xlsx, col, 0), err
 }
 
 // SetCellStyle provides a function to add style attribute for cells by given
@@ -1,6 +1,7 @@
 package excelize
 
 import (
+	"bytes"
 	"fmt"
 	"path/filepath"
 	"testing"
@@ -238,6 +239,29 @@ func TestSetCellStyle(t *testing.T) {
 	assert.EqualError(t, f.SetCellStyle("SheetN", "A1", "A2", 1), "sheet SheetN is not exist")
 }
 
+func TestGetCellStyle(t *testing.T) {
+	f, err := OpenReader(bytes.NewReader(buildPackage(t, map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><cols><col min="2" max="2" style="2"/></cols><sheetData><row r="2" s="1" customFormat="1"><c r="C2" s="3"/></row><row r="3"><c r="A3"/></row></sheetData><mergeCells count="1"><mergeCell ref="C2:D2"/></mergeCells></worksheet>`,
+	})))
+	assert.NoError(t, err)
+	ws, err := f.workSheetReadOnly("Sheet1")
+	assert.NoError(t, err)
+	rows := len(ws.SheetData.Row)
+	for cell, expected := range map[string]int{"A1": 0, "B1": 2, "A2": 1, "B2": 1, "C2": 3, "D2": 3, "A3": 0, "B3": 2, "B4": 2, "E3": 0} {
+		style, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, style, cell)
+	}
+	// Test get cell style doesn't create the rows and cells.
+	assert.Len(t, ws.SheetData.Row, rows)
+	assert.Len(t, ws.SheetData.Row[2].C, 1)
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(readZipBytes(t, buf.Bytes())["xl/worksheets/sheet1.xml"]), `r="B4"`)
+	_, err = f.GetCellStyle("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN is not exist")
+}
+
 func TestGetStyleID(t *testing.T) {
 	assert.Equal(t, -1, NewFile().getStyleID(&xlsxStyleSheet{}, nil))
 }
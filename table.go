@@ -12,9 +12,11 @@
 package excelize
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
@@ -106,6 +108,38 @@ func (f *File) countTables() int {
 	return count
 }
 
+// getSheetTables provides a function to get the tables in the worksheet by
+// given worksheet part name and the worksheet structure.
+func (f *File) getSheetTables(name string, ws *xlsxWorksheet) ([]*xlsxTable, error) {
+	if ws.TableParts == nil {
+		return nil, nil
+	}
+	relsPath := getRelsPath(name)
+	rels := f.relsReader(relsPath)
+	if rels == nil {
+		return nil, nil
+	}
+	var tables []*xlsxTable
+	for _, part := range ws.TableParts.TableParts {
+		for _, rel := range rels.Relationships {
+			if rel.ID != part.RID || rel.Type != SourceRelationshipTable {
+				continue
+			}
+			path := getRelsTargetPath(relsPath, rel.Target)
+			if _, ok := f.XLSX[path]; !ok {
+				continue
+			}
+			table := new(xlsxTable)
+			if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readXML(path)))).
+				Decode(table); err != nil && err != io.EOF {
+				return tables, err
+			}
+			tables = append(tables, table)
+		}
+	}
+	return tables, nil
+}
+
 // addSheetTable provides a function to add tablePart element to
 // xl/worksheets/sheet%d.xml by given worksheet name and relationship index.
 func (f *File) addSheetTable(sheet string, rID int) error {
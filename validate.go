@@ -0,0 +1,700 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The rules checked by Validate.
+const (
+	ValidationRuleSheetName     = "SheetName"
+	ValidationRuleDefinedName   = "DefinedName"
+	ValidationRuleRelationship  = "Relationship"
+	ValidationRuleRowOrder      = "RowOrder"
+	ValidationRuleCellOrder     = "CellOrder"
+	ValidationRuleDuplicateCell = "DuplicateCell"
+	ValidationRuleCellReference = "CellReference"
+	ValidationRuleStyleIndex    = "StyleIndex"
+	ValidationRuleMergeOverlap  = "MergeOverlap"
+	ValidationRuleTableOverlap  = "TableOverlap"
+)
+
+// ValidateOptions directly maps the options for validating the spreadsheet.
+// Repair specifies if fix the problems which could be repaired, the repaired
+// parts will be serialized when saving the spreadsheet.
+type ValidateOptions struct {
+	Repair bool
+}
+
+// ValidationIssue directly maps a problem found by validating the
+// spreadsheet. Sheet specifies the name of the worksheet, which is empty for
+// the workbook level problems. Ref specifies the cell reference, range,
+// relationship ID or defined name of the problem. Rule specifies the checked
+// rule, and Repaired specifies if the problem has been fixed by the repair.
+type ValidationIssue struct {
+	Sheet    string
+	Ref      string
+	Rule     string
+	Message  string
+	Repaired bool
+}
+
+// String returns the text representation of the validation issue.
+func (issue ValidationIssue) String() string {
+	location := issue.Ref
+	if issue.Sheet != "" {
+		location = strings.TrimSuffix(issue.Sheet+"!"+issue.Ref, "!")
+	}
+	text := fmt.Sprintf("[%s] %s", issue.Rule, issue.Message)
+	if location != "" {
+		text = location + ": " + text
+	}
+	if issue.Repaired {
+		text += " (repaired)"
+	}
+	return text
+}
+
+// validator directly maps the state of validating the spreadsheet.
+type validator struct {
+	f      *File
+	repair bool
+	issues []ValidationIssue
+}
+
+// add provides a function to record the problem found by validating.
+func (v *validator) add(sheet, ref, rule, message string, repaired bool) {
+	v.issues = append(v.issues, ValidationIssue{
+		Sheet: sheet, Ref: ref, Rule: rule, Message: message, Repaired: repaired,
+	})
+}
+
+// Validate provides a function to check the spreadsheet against the
+// constraints of the Office Open XML, which would cause the spreadsheet
+// application to report the problems with the content. The following rules
+// will be checked:
+//
+//    SheetName     - sheet names are not empty, not longer than 31 characters,
+//                    unique, and don't contain the characters :\/?*[]
+//    DefinedName   - defined names have valid syntax and scope, refer to a
+//                    formula, and are unique in the scope
+//    Relationship  - the targets of internal relationships exist, and the
+//                    relationships referred by the workbook and worksheets
+//                    exist
+//    RowOrder      - rows are in ascending order without duplicates, the
+//                    rows of the modified worksheets are always sorted, so
+//                    it only applies to the unmodified worksheets
+//    CellOrder     - cells in the row are in ascending column order
+//    DuplicateCell - cell references are unique
+//    CellReference - cell and range references are valid and match the row
+//    StyleIndex    - style indexes of cells, rows and columns exist
+//    MergeOverlap  - merged cells don't overlap with each other
+//    TableOverlap  - tables don't overlap with other tables or merged cells
+//
+// The problems will be fixed if the Repair of the options is true: the rows
+// and cells will be sorted and the duplicated cells will keep the last one,
+// the invalid style indexes will be reset to the default style, the
+// overlapped merged cells, the duplicated defined names and the defined names
+// with invalid scope will be removed, the dangling relationships and the
+// elements which refer to them will be removed. The invalid names and
+// references will not be changed. For example, validate and repair the
+// spreadsheet:
+//
+//    issues, err := f.Validate(excelize.ValidateOptions{Repair: true})
+//    if err != nil {
+//        fmt.Println(err)
+//    }
+//    for _, issue := range issues {
+//        fmt.Println(issue)
+//    }
+//
+func (f *File) Validate(opts ...ValidateOptions) ([]ValidationIssue, error) {
+	var options ValidateOptions
+	for _, o := range opts {
+		options = o
+	}
+	v := &validator{f: f, repair: options.Repair}
+	wb := f.workbookReadOnly()
+	v.checkSheetNames(wb)
+	v.checkDefinedNames(wb)
+	v.checkRelationships()
+	for _, sheet := range wb.Sheets.Sheet {
+		name, ok := f.sheetMap[trimSheetName(sheet.Name)]
+		if !ok || !strings.HasPrefix(name, "xl/worksheets/") {
+			continue
+		}
+		if err := v.checkWorksheet(sheet.Name, name); err != nil {
+			return v.issues, err
+		}
+	}
+	return v.issues, nil
+}
+
+// checkSheetNames provides a function to check the names of the sheets in
+// the workbook.
+func (v *validator) checkSheetNames(wb *xlsxWorkbook) {
+	names := make(map[string]bool)
+	for _, sheet := range wb.Sheets.Sheet {
+		var message string
+		switch {
+		case sheet.Name == "":
+			message = "the sheet name is empty"
+		case utf8.RuneCountInString(sheet.Name) > 31:
+			message = "the sheet name exceeds 31 characters"
+		case strings.ContainsAny(sheet.Name, ":\\/?*[]"):
+			message = "the sheet name contains invalid characters"
+		case strings.HasPrefix(sheet.Name, "'") || strings.HasSuffix(sheet.Name, "'"):
+			message = "the sheet name begins or ends with an apostrophe"
+		case strings.EqualFold(sheet.Name, "History"):
+			message = "the sheet name is reserved"
+		case names[strings.ToLower(sheet.Name)]:
+			message = "the sheet name is duplicated"
+		}
+		names[strings.ToLower(sheet.Name)] = true
+		if message != "" {
+			v.add(sheet.Name, "", ValidationRuleSheetName, message, false)
+		}
+	}
+}
+
+// definedNameRefExp defined the regular expression to match the defined name
+// which conflicts with the R1C1 reference.
+var definedNameRefExp = regexp.MustCompile(`^(?i:R[0-9]*C[0-9]*|R[0-9]*|C[0-9]*)$`)
+
+// checkDefinedNameSyntax provides a function to check the syntax of the
+// defined name, returns the description of the problem, or an empty string
+// if the name is valid.
+func checkDefinedNameSyntax(name string) string {
+	if name == "" {
+		return "the defined name is empty"
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		return "the defined name exceeds 255 characters"
+	}
+	for i, r := range name {
+		if unicode.IsLetter(r) || r == '_' || r == '\\' || (i > 0 && (unicode.IsDigit(r) || r == '.' || r == '?')) {
+			continue
+		}
+		return fmt.Sprintf("the defined name contains invalid character %q", r)
+	}
+	if _, _, err := CellNameToCoordinates(name); err == nil || definedNameRefExp.MatchString(name) {
+		return "the defined name conflicts with the cell reference"
+	}
+	return ""
+}
+
+// checkDefinedNames provides a function to check the syntax, scope and
+// uniqueness of the defined names in the workbook.
+func (v *validator) checkDefinedNames(wb *xlsxWorkbook) {
+	if wb.DefinedNames == nil {
+		return
+	}
+	var (
+		definedNames []xlsxDefinedName
+		repaired     bool
+		scopes       = make(map[string]bool)
+	)
+	for _, dn := range wb.DefinedNames.DefinedName {
+		var sheet, key = "", strings.ToLower(dn.Name)
+		if dn.LocalSheetID != nil {
+			if id := *dn.LocalSheetID; id < 0 || id >= len(wb.Sheets.Sheet) {
+				v.add("", dn.Name, ValidationRuleDefinedName, fmt.Sprintf("the scope %d of the defined name is out of range", id), v.repair)
+				if v.repair {
+					repaired = true
+					continue
+				}
+			} else {
+				sheet = wb.Sheets.Sheet[id].Name
+			}
+			key = strconv.Itoa(*dn.LocalSheetID) + "!" + key
+		}
+		if message := checkDefinedNameSyntax(dn.Name); message != "" {
+			v.add(sheet, dn.Name, ValidationRuleDefinedName, message, false)
+		} else if strings.TrimSpace(dn.Data) == "" {
+			v.add(sheet, dn.Name, ValidationRuleDefinedName, "the defined name refers to nothing", false)
+		}
+		if scopes[key] {
+			v.add(sheet, dn.Name, ValidationRuleDefinedName, "the defined name is duplicated in the scope", v.repair)
+			if v.repair {
+				repaired = true
+				continue
+			}
+		}
+		scopes[key] = true
+		definedNames = append(definedNames, dn)
+	}
+	if repaired {
+		v.f.workbookReader().DefinedNames.DefinedName = definedNames
+	}
+}
+
+// hasPart provides a function to check if the part exists in the
+// spreadsheet by given part name, including the parts which have not been
+// serialized yet.
+func (f *File) hasPart(name string) bool {
+	if _, ok := f.XLSX[name]; ok {
+		return true
+	}
+	if _, ok := f.Sheet[name]; ok {
+		return true
+	}
+	if _, ok := f.Drawings[name]; ok {
+		return true
+	}
+	if _, ok := f.Comments[name]; ok {
+		return true
+	}
+	if _, ok := f.VMLDrawing[name]; ok {
+		return true
+	}
+	if _, ok := f.Relationships[name]; ok {
+		return true
+	}
+	return name == "xl/sharedStrings.xml" && f.SharedStrings != nil
+}
+
+// getSheetNameByPath provides a function to get the worksheet name by given
+// worksheet part name, returns an empty string if the part is not a sheet.
+func (v *validator) getSheetNameByPath(name string) string {
+	for _, sheet := range v.f.workbookReadOnly().Sheets.Sheet {
+		if v.f.sheetMap[trimSheetName(sheet.Name)] == name {
+			return sheet.Name
+		}
+	}
+	return ""
+}
+
+// checkRelationships provides a function to check the targets of the
+// internal relationships exist, and the relationships referred by the
+// workbook exist.
+func (v *validator) checkRelationships() {
+	var paths []string
+	for name := range v.f.XLSX {
+		if strings.HasSuffix(name, ".rels") {
+			paths = append(paths, name)
+		}
+	}
+	for name, rels := range v.f.Relationships {
+		if _, ok := v.f.XLSX[name]; !ok && rels != nil {
+			paths = append(paths, name)
+		}
+	}
+	sort.Strings(paths)
+	for _, relsPath := range paths {
+		rels := v.f.relsReader(relsPath)
+		if rels == nil {
+			continue
+		}
+		var (
+			relationships []xlsxRelationship
+			repaired      bool
+			sheet         = v.getSheetNameByPath(strings.TrimSuffix(strings.Replace(relsPath, "_rels/", "", 1), ".rels"))
+		)
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode != "External" {
+				if target := getRelsTargetPath(relsPath, rel.Target); !v.f.hasPart(target) {
+					v.add(sheet, rel.ID, ValidationRuleRelationship, fmt.Sprintf("the target %s of the relationship in %s does not exist", target, relsPath), v.repair)
+					if v.repair {
+						repaired = true
+						continue
+					}
+				}
+			}
+			relationships = append(relationships, rel)
+		}
+		if repaired {
+			rels.Relationships = relationships
+		}
+	}
+	wbRels := v.f.relsReader("xl/_rels/workbook.xml.rels")
+	for _, sheet := range v.f.workbookReadOnly().Sheets.Sheet {
+		if !hasRelationship(wbRels, sheet.ID) {
+			v.add(sheet.Name, sheet.ID, ValidationRuleRelationship, "the relationship of the sheet does not exist", false)
+		}
+	}
+}
+
+// hasRelationship provides a function to check if the relationship exists by
+// given relationships and relationship ID.
+func hasRelationship(rels *xlsxRelationships, rID string) bool {
+	if rels == nil {
+		return false
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == rID {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWorksheet provides a function to check the worksheet by given
+// worksheet name and part name. The worksheet which has not been modified
+// will be checked by the part content, and be marked as modified if it has
+// been repaired. The rows of the worksheet which has been modified have been
+// sorted and deduplicated by checkSheet, so the RowOrder rule never applies
+// to it.
+func (v *validator) checkWorksheet(sheet, name string) error {
+	v.f.Lock()
+	ws, loaded := v.f.Sheet[name]
+	v.f.Unlock()
+	if !loaded || ws == nil || v.f.isUnmodified(name, ws) {
+		content, err := v.f.readBytes(name)
+		if err != nil {
+			return err
+		}
+		v.f.Lock()
+		ws, err = v.f.decodeWorkSheet(name, content)
+		v.f.Unlock()
+		if err != nil {
+			return err
+		}
+		loaded = false
+	}
+	repaired := v.checkSheetData(sheet, ws)
+	repaired = v.checkStyleIndexes(sheet, ws) || repaired
+	repaired = v.checkSheetRelationships(sheet, name, ws) || repaired
+	mergeRepaired, err := v.checkMergeCells(sheet, name, ws)
+	if err != nil {
+		return err
+	}
+	if !repaired && !mergeRepaired {
+		return nil
+	}
+	checkSheet(ws)
+	if err = checkRow(ws); err != nil {
+		return err
+	}
+	if !loaded {
+		// mark the repaired worksheet as modified, and replace the worksheet
+		// which has been loaded for reading only
+		v.f.Lock()
+		v.f.setModified(name)
+		v.f.Sheet[name] = ws
+		v.f.Unlock()
+	}
+	return nil
+}
+
+// getCellCoordinates provides a function to get the column and row number of
+// the cell by given cell and the column number of the previous cell. The
+// cell without the reference is placed after the previous cell.
+func getCellCoordinates(c *xlsxC, prevCol, row int) (int, int, error) {
+	if c.R == "" {
+		return prevCol + 1, row, nil
+	}
+	return CellNameToCoordinates(c.R)
+}
+
+// checkSheetData provides a function to check the order and references of
+// the rows and cells in the worksheet, returns if the sheet data has been
+// repaired.
+func (v *validator) checkSheetData(sheet string, ws *xlsxWorksheet) bool {
+	var repairable bool
+	prevRow := 0
+	for i := range ws.SheetData.Row {
+		r := &ws.SheetData.Row[i]
+		rowNum := r.R
+		if rowNum == 0 {
+			rowNum = prevRow + 1
+		}
+		switch {
+		case rowNum < 0 || rowNum > TotalRows:
+			v.add(sheet, strconv.Itoa(r.R), ValidationRuleCellReference, "the row number is out of range", false)
+			continue
+		case rowNum == prevRow:
+			v.add(sheet, strconv.Itoa(rowNum), ValidationRuleRowOrder, "the row is duplicated", v.repair)
+			repairable = true
+		case rowNum < prevRow:
+			v.add(sheet, strconv.Itoa(rowNum), ValidationRuleRowOrder, "the row is not in ascending order", v.repair)
+			repairable = true
+		default:
+			prevRow = rowNum
+		}
+		prevCol, cols := 0, make(map[int]bool, len(r.C))
+		for j := range r.C {
+			c := &r.C[j]
+			col, row, err := getCellCoordinates(c, prevCol, rowNum)
+			if err != nil {
+				v.add(sheet, c.R, ValidationRuleCellReference, "the cell reference is invalid", false)
+				continue
+			}
+			switch {
+			case row != rowNum:
+				v.add(sheet, c.R, ValidationRuleCellReference, fmt.Sprintf("the cell reference doesn't match the row number %d", rowNum), false)
+			case cols[col]:
+				cell, _ := CoordinatesToCellName(col, row)
+				v.add(sheet, cell, ValidationRuleDuplicateCell, "the cell is duplicated", v.repair)
+				repairable = true
+			case col < prevCol:
+				v.add(sheet, c.R, ValidationRuleCellOrder, "the cell is not in ascending column order", v.repair)
+				repairable = true
+			}
+			cols[col] = true
+			if col > prevCol {
+				prevCol = col
+			}
+		}
+	}
+	if !repairable || !v.repair {
+		return false
+	}
+	repairSheetData(ws)
+	return true
+}
+
+// repairSheetData provides a function to sort the rows and cells in the
+// worksheet, the duplicated rows will be merged and the duplicated cells
+// will keep the last one. The references of the rows and cells will be
+// filled.
+func repairSheetData(ws *xlsxWorksheet) {
+	rows := ws.SheetData.Row
+	prevRow := 0
+	for i := range rows {
+		if rows[i].R == 0 {
+			rows[i].R = prevRow + 1
+		}
+		prevRow = rows[i].R
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].R < rows[j].R })
+	var sheetData []xlsxRow
+	for _, r := range rows {
+		if l := len(sheetData); l > 0 && sheetData[l-1].R == r.R {
+			sheetData[l-1].C = append(sheetData[l-1].C, r.C...)
+			continue
+		}
+		sheetData = append(sheetData, r)
+	}
+	for i := range sheetData {
+		r := &sheetData[i]
+		prevCol, cols := 0, make([]int, len(r.C))
+		for j := range r.C {
+			col, row, err := getCellCoordinates(&r.C[j], prevCol, r.R)
+			if err != nil || row != r.R {
+				col = prevCol
+			} else if r.C[j].R == "" {
+				r.C[j].R, _ = CoordinatesToCellName(col, row)
+			}
+			cols[j], prevCol = col, col
+		}
+		idx := make([]int, len(r.C))
+		for j := range idx {
+			idx[j] = j
+		}
+		sort.SliceStable(idx, func(a, b int) bool { return cols[idx[a]] < cols[idx[b]] })
+		cells := make([]xlsxC, 0, len(r.C))
+		for k, j := range idx {
+			if k+1 < len(idx) && cols[idx[k+1]] == cols[j] && r.C[j].R != "" && r.C[idx[k+1]].R == r.C[j].R {
+				continue
+			}
+			cells = append(cells, r.C[j])
+		}
+		r.C = cells
+	}
+	ws.SheetData.Row = sheetData
+}
+
+// checkStyleIndexes provides a function to check the style indexes of the
+// cells, rows and columns in the worksheet exist, returns if the style
+// indexes have been repaired.
+func (v *validator) checkStyleIndexes(sheet string, ws *xlsxWorksheet) bool {
+	var count int
+	if styles := v.f.stylesReadOnly(); styles != nil && styles.CellXfs != nil {
+		count = len(styles.CellXfs.Xf)
+	}
+	var repaired bool
+	invalid := func(ref string, idx *int) {
+		if *idx == 0 || (*idx > 0 && *idx < count) {
+			return
+		}
+		v.add(sheet, ref, ValidationRuleStyleIndex, fmt.Sprintf("the style index %d does not exist", *idx), v.repair)
+		if v.repair {
+			*idx, repaired = 0, true
+		}
+	}
+	if ws.Cols != nil {
+		for i := range ws.Cols.Col {
+			col := &ws.Cols.Col[i]
+			min, _ := ColumnNumberToName(col.Min)
+			max, _ := ColumnNumberToName(col.Max)
+			invalid(min+":"+max, &col.Style)
+		}
+	}
+	for i := range ws.SheetData.Row {
+		r := &ws.SheetData.Row[i]
+		invalid(strconv.Itoa(r.R), &r.S)
+		for j := range r.C {
+			invalid(r.C[j].R, &r.C[j].S)
+		}
+	}
+	return repaired
+}
+
+// checkSheetRelationships provides a function to check the relationships
+// referred by the worksheet exist, returns if the elements which refer to
+// the dangling relationships have been removed.
+func (v *validator) checkSheetRelationships(sheet, name string, ws *xlsxWorksheet) bool {
+	var repaired bool
+	rels := v.f.relsReader(getRelsPath(name))
+	dangling := func(element, rID string) bool {
+		if hasRelationship(rels, rID) {
+			return false
+		}
+		v.add(sheet, rID, ValidationRuleRelationship, fmt.Sprintf("the relationship of the %s does not exist", element), v.repair)
+		repaired = repaired || v.repair
+		return v.repair
+	}
+	if ws.Drawing != nil && dangling("drawing", ws.Drawing.RID) {
+		ws.Drawing = nil
+	}
+	if ws.LegacyDrawing != nil && dangling("legacy drawing", ws.LegacyDrawing.RID) {
+		ws.LegacyDrawing = nil
+	}
+	if ws.LegacyDrawingHF != nil && dangling("header and footer legacy drawing", ws.LegacyDrawingHF.RID) {
+		ws.LegacyDrawingHF = nil
+	}
+	if ws.Picture != nil && dangling("background picture", ws.Picture.RID) {
+		ws.Picture = nil
+	}
+	if ws.Hyperlinks != nil {
+		var hyperlinks []xlsxHyperlink
+		for _, link := range ws.Hyperlinks.Hyperlink {
+			if link.RID != "" && dangling("hyperlink "+link.Ref, link.RID) {
+				continue
+			}
+			hyperlinks = append(hyperlinks, link)
+		}
+		if len(hyperlinks) != len(ws.Hyperlinks.Hyperlink) {
+			if ws.Hyperlinks.Hyperlink = hyperlinks; len(hyperlinks) == 0 {
+				ws.Hyperlinks = nil
+			}
+		}
+	}
+	if ws.TableParts != nil {
+		var tableParts []*xlsxTablePart
+		for _, part := range ws.TableParts.TableParts {
+			if dangling("table", part.RID) {
+				continue
+			}
+			tableParts = append(tableParts, part)
+		}
+		if len(tableParts) != len(ws.TableParts.TableParts) {
+			ws.TableParts.TableParts, ws.TableParts.Count = tableParts, len(tableParts)
+			if len(tableParts) == 0 {
+				ws.TableParts = nil
+			}
+		}
+	}
+	return repaired
+}
+
+// parseRangeRef provides a function to get the sorted coordinates of the
+// cell range by given range reference, the single cell reference is
+// supported.
+func parseRangeRef(ref string) ([]int, error) {
+	cells := strings.Split(ref, ":")
+	if len(cells) == 1 {
+		cells = append(cells, cells[0])
+	}
+	if len(cells) != 2 {
+		return nil, newInvalidRangeError(ref)
+	}
+	coordinates, err := areaRangeToCoordinates(cells[0], cells[1])
+	if err != nil {
+		return nil, err
+	}
+	_ = sortCoordinates(coordinates)
+	return coordinates, nil
+}
+
+// checkMergeCells provides a function to check the merged cells and the
+// tables in the worksheet don't overlap, returns if the merged cells have
+// been repaired.
+func (v *validator) checkMergeCells(sheet, name string, ws *xlsxWorksheet) (bool, error) {
+	var (
+		repaired bool
+		areas    [][]int
+	)
+	tables, err := v.f.getSheetTables(name, ws)
+	if err != nil {
+		return false, err
+	}
+	for i, table := range tables {
+		area, err := parseRangeRef(table.Ref)
+		if err != nil {
+			v.add(sheet, table.Ref, ValidationRuleCellReference, fmt.Sprintf("the range of the table %s is invalid", table.Name), false)
+			areas = append(areas, nil)
+			continue
+		}
+		for j := 0; j < i; j++ {
+			if areas[j] != nil && isOverlap(area, areas[j]) {
+				v.add(sheet, table.Ref, ValidationRuleTableOverlap, fmt.Sprintf("the table %s overlaps with the table %s", table.Name, tables[j].Name), false)
+			}
+		}
+		areas = append(areas, area)
+	}
+	if ws.MergeCells == nil {
+		return false, nil
+	}
+	var (
+		mergeCells []*xlsxMergeCell
+		merged     []*xlsxMergeCell
+		mergedArea [][]int
+	)
+	for _, mergeCell := range ws.MergeCells.Cells {
+		area, err := parseRangeRef(mergeCell.Ref)
+		if err != nil {
+			v.add(sheet, mergeCell.Ref, ValidationRuleCellReference, "the range of the merged cells is invalid", v.repair)
+			if v.repair {
+				repaired = true
+				continue
+			}
+			mergeCells = append(mergeCells, mergeCell)
+			continue
+		}
+		var message string
+		for i, m := range mergedArea {
+			if isOverlap(area, m) {
+				message = fmt.Sprintf("the merged cells overlap with the merged cells %s", merged[i].Ref)
+				break
+			}
+		}
+		for i, t := range areas {
+			if t != nil && message == "" && isOverlap(area, t) {
+				message = fmt.Sprintf("the merged cells overlap with the table %s", tables[i].Name)
+			}
+		}
+		if message != "" {
+			v.add(sheet, mergeCell.Ref, ValidationRuleMergeOverlap, message, v.repair)
+			if v.repair {
+				repaired = true
+				continue
+			}
+		}
+		mergeCells = append(mergeCells, mergeCell)
+		merged, mergedArea = append(merged, mergeCell), append(mergedArea, area)
+	}
+	if repaired {
+		ws.MergeCells.Cells, ws.MergeCells.Count = mergeCells, len(mergeCells)
+		if len(mergeCells) == 0 {
+			ws.MergeCells = nil
+		}
+	}
+	return repaired, nil
+}
@@ -0,0 +1,155 @@
+package excelize
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	f := NewFile()
+	f.NewSheet("Sheet2")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "value"))
+	assert.NoError(t, f.MergeCell("Sheet1", "B2", "C3"))
+	assert.NoError(t, f.AddTable("Sheet1", "E1", "F5", `{}`))
+	assert.NoError(t, f.AddComment("Sheet1", "A1", `{"author":"Excelize","text":"comment"}`))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A1", "https://github.com/360EntSecGroup-Skylar/excelize", "External"))
+	assert.NoError(t, f.AddPicture("Sheet2", "A1", filepath.Join("test", "images", "excel.png"), ""))
+	style, err := f.NewStyle(&Style{NumFmt: 14})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$1"}))
+	issues, err := f.Validate()
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	issues, err = f.Validate()
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestValidateRepair(t *testing.T) {
+	b := buildPackage(t, map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/><sheet name="Bad:Name" sheetId="2" r:id="rId9"/></sheets><definedNames><definedName name="Amount">Sheet1!$A$1</definedName><definedName name="amount">Sheet1!$A$2</definedName><definedName name="1st">Sheet1!$A$1</definedName><definedName name="AB12">Sheet1!$A$1</definedName><definedName name="R1C1">Sheet1!$A$1</definedName><definedName name="Empty"></definedName><definedName name="Scoped" localSheetId="5">Sheet1!$A$1</definedName></definedNames></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme" Target="theme/theme1.xml"/><Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml" Target="../customXml/item1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml":   `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheetData><row r="2"><c r="B2" t="str"><v>B2</v></c><c r="A2" t="str"><v>A2</v></c></row><row r="1"><c r="A1" s="99" t="str"><v>A1</v></c></row><row r="2"><c r="C2" t="str"><v>C2</v></c></row><row r="3"><c r="A3" t="str"><v>first</v></c><c r="A3" t="str"><v>last</v></c><c r="B4" t="str"><v>B4</v></c></row></sheetData><mergeCells count="3"><mergeCell ref="A5:B6"/><mergeCell ref="B6:C7"/><mergeCell ref="A5:B"/></mergeCells><drawing r:id="rId2"/></worksheet>`,
+	})
+	f, err := OpenReader(bytes.NewReader(b))
+	assert.NoError(t, err)
+	expected := []ValidationIssue{
+		{Sheet: "Bad:Name", Rule: ValidationRuleSheetName, Message: "the sheet name contains invalid characters"},
+		{Ref: "amount", Rule: ValidationRuleDefinedName, Message: "the defined name is duplicated in the scope", Repaired: true},
+		{Ref: "1st", Rule: ValidationRuleDefinedName, Message: "the defined name contains invalid character '1'"},
+		{Ref: "AB12", Rule: ValidationRuleDefinedName, Message: "the defined name conflicts with the cell reference"},
+		{Ref: "R1C1", Rule: ValidationRuleDefinedName, Message: "the defined name conflicts with the cell reference"},
+		{Ref: "Empty", Rule: ValidationRuleDefinedName, Message: "the defined name refers to nothing"},
+		{Ref: "Scoped", Rule: ValidationRuleDefinedName, Message: "the scope 5 of the defined name is out of range", Repaired: true},
+		{Ref: "rId4", Rule: ValidationRuleRelationship, Message: "the target customXml/item1.xml of the relationship in xl/_rels/workbook.xml.rels does not exist", Repaired: true},
+		{Sheet: "Bad:Name", Ref: "rId9", Rule: ValidationRuleRelationship, Message: "the relationship of the sheet does not exist"},
+		{Sheet: "Sheet1", Ref: "A2", Rule: ValidationRuleCellOrder, Message: "the cell is not in ascending column order", Repaired: true},
+		{Sheet: "Sheet1", Ref: "1", Rule: ValidationRuleRowOrder, Message: "the row is not in ascending order", Repaired: true},
+		{Sheet: "Sheet1", Ref: "2", Rule: ValidationRuleRowOrder, Message: "the row is duplicated", Repaired: true},
+		{Sheet: "Sheet1", Ref: "A3", Rule: ValidationRuleDuplicateCell, Message: "the cell is duplicated", Repaired: true},
+		{Sheet: "Sheet1", Ref: "B4", Rule: ValidationRuleCellReference, Message: "the cell reference doesn't match the row number 3"},
+		{Sheet: "Sheet1", Ref: "A1", Rule: ValidationRuleStyleIndex, Message: "the style index 99 does not exist", Repaired: true},
+		{Sheet: "Sheet1", Ref: "rId2", Rule: ValidationRuleRelationship, Message: "the relationship of the drawing does not exist", Repaired: true},
+		{Sheet: "Sheet1", Ref: "B6:C7", Rule: ValidationRuleMergeOverlap, Message: "the merged cells overlap with the merged cells A5:B6", Repaired: true},
+		{Sheet: "Sheet1", Ref: "A5:B", Rule: ValidationRuleCellReference, Message: "the range of the merged cells is invalid", Repaired: true},
+	}
+	// Test validate without repair.
+	issues, err := f.Validate()
+	assert.NoError(t, err)
+	for i := range expected {
+		expected[i].Repaired = false
+	}
+	assert.Equal(t, expected, issues)
+	issues, err = f.Validate()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, issues)
+	assert.Equal(t, "Sheet1!A1: [StyleIndex] the style index 99 does not exist", issues[14].String())
+	assert.Equal(t, "Bad:Name: [SheetName] the sheet name contains invalid characters", issues[0].String())
+	assert.Equal(t, "Scoped: [DefinedName] the scope 5 of the defined name is out of range", issues[6].String())
+
+	// Test validate with repair.
+	issues, err = f.Validate(ValidateOptions{Repair: true})
+	assert.NoError(t, err)
+	for _, issue := range issues {
+		for i := range expected {
+			if expected[i].Ref == issue.Ref && expected[i].Rule == issue.Rule {
+				expected[i].Repaired = issue.Repaired
+			}
+		}
+	}
+	assert.Equal(t, expected, issues)
+	assert.Equal(t, "Sheet1!B6:C7: [MergeOverlap] the merged cells overlap with the merged cells A5:B6 (repaired)", issues[16].String())
+	assert.NotNil(t, f.Sheet["xl/worksheets/sheet1.xml"])
+	assert.NotEmpty(t, f.xmlAttr["xl/worksheets/sheet1.xml"])
+	var remains []ValidationIssue
+	for _, issue := range issues {
+		if !issue.Repaired {
+			remains = append(remains, issue)
+		}
+	}
+	issues, err = f.Validate()
+	assert.NoError(t, err)
+	assert.Equal(t, remains, issues)
+
+	// Test the rows of the worksheet loaded for modifying have been sorted.
+	g, err := OpenReader(bytes.NewReader(b))
+	assert.NoError(t, err)
+	_, err = g.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	issues, err = g.Validate()
+	assert.NoError(t, err)
+	for _, issue := range issues {
+		assert.NotEqual(t, ValidationRuleRowOrder, issue.Rule)
+	}
+	assert.Contains(t, issues, ValidationIssue{Sheet: "Sheet1", Ref: "A3", Rule: ValidationRuleDuplicateCell, Message: "the cell is duplicated"})
+
+	// Test the repaired spreadsheet after saving.
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A1"}, {"A2", "B2", "C2"}, {"last", "B4"}}, rows)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Len(t, f.GetDefinedName(), 5)
+	issues, err = f.Validate()
+	assert.NoError(t, err)
+	assert.Equal(t, remains, issues)
+}
+
+func TestValidateOverlapTables(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.AddTable("Sheet1", "A1", "B3", `{"table_name":"Table1"}`))
+	assert.NoError(t, f.AddTable("Sheet1", "C1", "D3", `{"table_name":"Table2"}`))
+	assert.NoError(t, f.MergeCell("Sheet1", "F1", "G2"))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.MergeCells.Cells = append(ws.MergeCells.Cells, &xlsxMergeCell{Ref: "D3:E4"})
+	f.XLSX["xl/tables/table2.xml"] = bytes.Replace(f.XLSX["xl/tables/table2.xml"], []byte(`ref="C1:D3"`), []byte(`ref="B1:D3"`), 1)
+	issues, err := f.Validate(ValidateOptions{Repair: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidationIssue{
+		{Sheet: "Sheet1", Ref: "B1:D3", Rule: ValidationRuleTableOverlap, Message: "the table Table2 overlaps with the table Table1"},
+		{Sheet: "Sheet1", Ref: "D3:E4", Rule: ValidationRuleMergeOverlap, Message: "the merged cells overlap with the table Table2", Repaired: true},
+	}, issues)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+
+	// Test validate with the invalid worksheet.
+	f = NewFile()
+	f.XLSX["xl/worksheets/sheet1.xml"] = []byte(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1"`)
+	delete(f.Sheet, "xl/worksheets/sheet1.xml")
+	_, err = f.Validate()
+	assert.EqualError(t, err, "XML syntax error on line 1: unexpected EOF")
+}
@@ -0,0 +1,1037 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// Record types of the BIFF8 workbook stream.
+const (
+	xlsRecordFormula       = 0x0006
+	xlsRecordEOF           = 0x000A
+	xlsRecordExternSheet   = 0x0017
+	xlsRecordLbl           = 0x0018
+	xlsRecordDate1904      = 0x0022
+	xlsRecordFilePass      = 0x002F
+	xlsRecordContinue      = 0x003C
+	xlsRecordColInfo       = 0x007D
+	xlsRecordBoundSheet    = 0x0085
+	xlsRecordMulRK         = 0x00BD
+	xlsRecordXF            = 0x00E0
+	xlsRecordMergeCells    = 0x00E5
+	xlsRecordSST           = 0x00FC
+	xlsRecordLabelSST      = 0x00FD
+	xlsRecordSupBook       = 0x01AE
+	xlsRecordNumber        = 0x0203
+	xlsRecordLabel         = 0x0204
+	xlsRecordBoolErr       = 0x0205
+	xlsRecordString        = 0x0207
+	xlsRecordRow           = 0x0208
+	xlsRecordRK            = 0x027E
+	xlsRecordFormat        = 0x041E
+	xlsRecordSharedFormula = 0x04BC
+	xlsRecordBOF           = 0x0809
+)
+
+// xlsRecordSizes defined the minimum size of the records which will be read.
+var xlsRecordSizes = map[uint16]int{
+	xlsRecordFormula: 22, xlsRecordExternSheet: 2, xlsRecordLbl: 15, xlsRecordDate1904: 2,
+	xlsRecordColInfo: 10, xlsRecordBoundSheet: 8, xlsRecordMulRK: 6, xlsRecordXF: 4,
+	xlsRecordMergeCells: 2, xlsRecordSST: 8, xlsRecordLabelSST: 10, xlsRecordSupBook: 4,
+	xlsRecordNumber: 14, xlsRecordLabel: 9, xlsRecordBoolErr: 8, xlsRecordString: 3,
+	xlsRecordRow: 16, xlsRecordRK: 10, xlsRecordFormat: 5, xlsRecordSharedFormula: 10,
+	xlsRecordBOF: 4,
+}
+
+// xlsErrors defined the error values of the cells by the BIFF8 error codes.
+var xlsErrors = map[byte]string{
+	0x00: formulaErrorNULL, 0x07: formulaErrorDIV, 0x0F: formulaErrorVALUE, 0x17: formulaErrorREF,
+	0x1D: formulaErrorNAME, 0x24: formulaErrorNUM, 0x2A: formulaErrorNA,
+}
+
+// xlsOperators defined the binary operators of the parsed formula tokens.
+var xlsOperators = map[byte]string{
+	0x03: "+", 0x04: "-", 0x05: "*", 0x06: "/", 0x07: "^", 0x08: "&", 0x09: "<", 0x0A: "<=",
+	0x0B: "=", 0x0C: ">=", 0x0D: ">", 0x0E: "<>", 0x0F: " ", 0x10: ",", 0x11: ":",
+}
+
+// xlsBuiltInNames defined the built-in defined names by the name codes.
+var xlsBuiltInNames = map[byte]string{
+	0x00: "_xlnm.Consolidate_Area", 0x03: "_xlnm.Extract", 0x04: "_xlnm.Database",
+	0x05: "_xlnm.Criteria", 0x06: "_xlnm.Print_Area", 0x07: "_xlnm.Print_Titles",
+	0x0C: "_xlnm.Sheet_Title", 0x0D: "_xlnm._FilterDatabase",
+}
+
+// xlsFunction directly maps the function name and the number of the fixed
+// arguments of the built-in function, args is -1 for the functions with
+// variable number of arguments.
+type xlsFunction struct {
+	name string
+	args int
+}
+
+// xlsFunctions defined the commonly used built-in functions by the function
+// index of the BIFF8 function table.
+var xlsFunctions = map[uint16]xlsFunction{
+	0: {"COUNT", -1}, 1: {"IF", -1}, 2: {"ISNA", 1}, 3: {"ISERROR", 1}, 4: {"SUM", -1},
+	5: {"AVERAGE", -1}, 6: {"MIN", -1}, 7: {"MAX", -1}, 8: {"ROW", -1}, 9: {"COLUMN", -1},
+	10: {"NA", 0}, 11: {"NPV", -1}, 12: {"STDEV", -1}, 13: {"DOLLAR", -1}, 14: {"FIXED", -1},
+	15: {"SIN", 1}, 16: {"COS", 1}, 17: {"TAN", 1}, 18: {"ATAN", 1}, 19: {"PI", 0},
+	20: {"SQRT", 1}, 21: {"EXP", 1}, 22: {"LN", 1}, 23: {"LOG10", 1}, 24: {"ABS", 1},
+	25: {"INT", 1}, 26: {"SIGN", 1}, 27: {"ROUND", 2}, 28: {"LOOKUP", -1}, 29: {"INDEX", -1},
+	30: {"REPT", 2}, 31: {"MID", 3}, 32: {"LEN", 1}, 33: {"VALUE", 1}, 34: {"TRUE", 0},
+	35: {"FALSE", 0}, 36: {"AND", -1}, 37: {"OR", -1}, 38: {"NOT", 1}, 39: {"MOD", 2},
+	40: {"DCOUNT", 3}, 41: {"DSUM", 3}, 42: {"DAVERAGE", 3}, 43: {"DMIN", 3}, 44: {"DMAX", 3},
+	45: {"DSTDEV", 3}, 46: {"VAR", -1}, 47: {"DVAR", 3}, 48: {"TEXT", 2}, 49: {"LINEST", -1},
+	50: {"TREND", -1}, 51: {"LOGEST", -1}, 52: {"GROWTH", -1}, 56: {"PV", -1}, 57: {"FV", -1},
+	58: {"NPER", -1}, 59: {"PMT", -1}, 60: {"RATE", -1}, 61: {"MIRR", 3}, 62: {"IRR", -1},
+	63: {"RAND", 0}, 64: {"MATCH", -1}, 65: {"DATE", 3}, 66: {"TIME", 3}, 67: {"DAY", 1},
+	68: {"MONTH", 1}, 69: {"YEAR", 1}, 70: {"WEEKDAY", -1}, 71: {"HOUR", 1}, 72: {"MINUTE", 1},
+	73: {"SECOND", 1}, 74: {"NOW", 0}, 75: {"AREAS", 1}, 76: {"ROWS", 1}, 77: {"COLUMNS", 1},
+	78: {"OFFSET", -1}, 82: {"SEARCH", -1}, 83: {"TRANSPOSE", 1}, 86: {"TYPE", 1},
+	97: {"ATAN2", 2}, 98: {"ASIN", 1}, 99: {"ACOS", 1}, 100: {"CHOOSE", -1}, 101: {"HLOOKUP", -1},
+	102: {"VLOOKUP", -1}, 105: {"ISREF", 1}, 109: {"LOG", -1}, 111: {"CHAR", 1}, 112: {"LOWER", 1},
+	113: {"UPPER", 1}, 114: {"PROPER", 1}, 115: {"LEFT", -1}, 116: {"RIGHT", -1}, 117: {"EXACT", 2},
+	118: {"TRIM", 1}, 119: {"REPLACE", 4}, 120: {"SUBSTITUTE", -1}, 121: {"CODE", 1},
+	124: {"FIND", -1}, 125: {"CELL", -1}, 126: {"ISERR", 1}, 127: {"ISTEXT", 1},
+	128: {"ISNUMBER", 1}, 129: {"ISBLANK", 1}, 130: {"T", 1}, 131: {"N", 1},
+	140: {"DATEVALUE", 1}, 141: {"TIMEVALUE", 1}, 142: {"SLN", 3}, 143: {"SYD", 4},
+	144: {"DDB", -1}, 148: {"INDIRECT", -1}, 162: {"CLEAN", 1}, 163: {"MDETERM", 1},
+	164: {"MINVERSE", 1}, 165: {"MMULT", 2}, 167: {"IPMT", -1}, 168: {"PPMT", -1},
+	169: {"COUNTA", -1}, 183: {"PRODUCT", -1}, 184: {"FACT", 1}, 189: {"DPRODUCT", 3},
+	190: {"ISNONTEXT", 1}, 193: {"STDEVP", -1}, 194: {"VARP", -1}, 195: {"DSTDEVP", 3},
+	196: {"DVARP", 3}, 197: {"TRUNC", -1}, 198: {"ISLOGICAL", 1}, 199: {"DCOUNTA", 3},
+	212: {"ROUNDUP", 2}, 213: {"ROUNDDOWN", 2}, 216: {"RANK", -1}, 219: {"ADDRESS", -1},
+	220: {"DAYS360", -1}, 221: {"TODAY", 0}, 222: {"VDB", -1}, 227: {"MEDIAN", -1},
+	228: {"SUMPRODUCT", -1}, 229: {"SINH", 1}, 230: {"COSH", 1}, 231: {"TANH", 1},
+	232: {"ASINH", 1}, 233: {"ACOSH", 1}, 234: {"ATANH", 1}, 247: {"DB", -1},
+	252: {"FREQUENCY", 2}, 261: {"ERROR.TYPE", 1}, 269: {"AVEDEV", -1}, 276: {"COMBIN", 2},
+	277: {"CONFIDENCE", 3}, 285: {"FLOOR", 2}, 288: {"CEILING", 2}, 298: {"ODD", 1},
+	313: {"SLOPE", 2}, 314: {"INTERCEPT", 2}, 318: {"DEVSQ", -1}, 321: {"SUMSQ", -1},
+	325: {"LARGE", 2}, 326: {"SMALL", 2}, 336: {"CONCATENATE", -1}, 337: {"POWER", 2},
+	342: {"RADIANS", 1}, 343: {"DEGREES", 1}, 344: {"SUBTOTAL", -1}, 345: {"SUMIF", -1},
+	346: {"COUNTIF", 2}, 347: {"COUNTBLANK", 1}, 354: {"ROMAN", -1}, 359: {"HYPERLINK", -1},
+	361: {"AVERAGEA", -1}, 362: {"MAXA", -1}, 363: {"MINA", -1},
+}
+
+// xlsRecord directly maps the record of the BIFF8 workbook stream, the data
+// of the following CONTINUE records are kept separately.
+type xlsRecord struct {
+	id        uint16
+	offset    int
+	data      []byte
+	continues [][]byte
+}
+
+// xlsSheet directly maps the BoundSheet8 record of the sheet.
+type xlsSheet struct {
+	name   string
+	offset uint32
+	state  byte
+	kind   byte
+}
+
+// xlsFormulaCell defined the formula cell which refers to the shared
+// formula of the anchor cell.
+type xlsFormulaCell struct {
+	axis                 string
+	row, col             int
+	anchorRow, anchorCol int
+}
+
+// xlsReader defined the state of reading the BIFF8 workbook stream.
+type xlsReader struct {
+	f              *File
+	records        []xlsRecord
+	sheets         []xlsSheet
+	sst            []string
+	formats        map[int]string
+	xfs            []int
+	styleIDs       map[int]int
+	names          []string
+	supBooks       []bool
+	externSheets   [][3]uint16
+	sheet          string
+	sharedFormulas map[[2]int][]byte
+	pending        []xlsFormulaCell
+	stringCell     string
+	cells          int
+	maxCells       int
+}
+
+// xlsStream defined the reader of the record data with the following
+// CONTINUE records, the string characters which span the CONTINUE records
+// will be read with the new option flags at the beginning of the CONTINUE
+// records.
+type xlsStream struct {
+	data   []byte
+	bounds map[int]bool
+	pos    int
+}
+
+// getXLSWorkbookStream provides a function to get the workbook stream from
+// the compound file, returns nil if the compound file isn't a workbook of
+// the Excel 97-2003 binary file format.
+func getXLSWorkbookStream(b []byte) ([]byte, error) {
+	doc, err := mscfb.New(bytes.NewReader(b))
+	if err != nil {
+		return nil, nil
+	}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name == "Workbook" || entry.Name == "Book" {
+			return ioutil.ReadAll(doc)
+		}
+	}
+	return nil, nil
+}
+
+// openXLS provides a function to read the BIFF8 workbook stream into a new
+// spreadsheet file. The sheet names, cell values, formulas, shared strings,
+// number formats, merged cells, row heights and column widths of the
+// worksheets will be converted, and the chart sheets and macro sheets will
+// be ignored. The cached values will be kept for the formulas which contain
+// unsupported tokens. The size of the workbook stream and the number of
+// cells in each worksheet will be checked by the limits of the given
+// options while converting.
+func openXLS(stream []byte, opts Options) (*File, error) {
+	if opts.UnzipSizeLimit > 0 && int64(len(stream)) > opts.UnzipSizeLimit {
+		return nil, newUnzipSizeLimitError(opts.UnzipSizeLimit)
+	}
+	r := &xlsReader{
+		f: NewFile(), formats: make(map[int]string), styleIDs: make(map[int]int),
+		sharedFormulas: make(map[[2]int][]byte), maxCells: opts.MaxCellsPerSheet,
+	}
+	if err := r.readRecords(stream); err != nil {
+		return nil, err
+	}
+	if err := r.readGlobals(); err != nil {
+		return nil, err
+	}
+	for i, sheet := range r.sheets {
+		if sheet.kind != 0 {
+			continue
+		}
+		if err := r.readSheet(i); err != nil {
+			return nil, err
+		}
+	}
+	return r.f, nil
+}
+
+// readRecords provides a function to split the workbook stream into
+// records, the CONTINUE records will be attached to the previous record.
+func (r *xlsReader) readRecords(stream []byte) error {
+	for pos := 0; pos+4 <= len(stream); {
+		id, size := binary.LittleEndian.Uint16(stream[pos:]), int(binary.LittleEndian.Uint16(stream[pos+2:]))
+		if pos+4+size > len(stream) {
+			return fmt.Errorf("invalid BIFF record 0x%04X", id)
+		}
+		data := stream[pos+4 : pos+4+size]
+		if id == xlsRecordContinue && len(r.records) > 0 {
+			last := &r.records[len(r.records)-1]
+			last.continues = append(last.continues, data)
+		} else {
+			if min, ok := xlsRecordSizes[id]; ok && size < min {
+				return fmt.Errorf("invalid BIFF record 0x%04X", id)
+			}
+			r.records = append(r.records, xlsRecord{id: id, offset: pos, data: data})
+		}
+		pos += 4 + size
+	}
+	if len(r.records) == 0 || r.records[0].id != xlsRecordBOF {
+		return errors.New("invalid BIFF workbook stream")
+	}
+	return nil
+}
+
+// readGlobals provides a function to read the workbook globals substream,
+// and create the worksheets in the order of the sheets.
+func (r *xlsReader) readGlobals() error {
+	for _, rec := range r.records {
+		data := rec.data
+		switch rec.id {
+		case xlsRecordBOF:
+			if binary.LittleEndian.Uint16(data) != 0x0600 {
+				return errors.New("unsupported BIFF version, only BIFF8 workbooks are supported")
+			}
+		case xlsRecordFilePass:
+			return errors.New("unsupported encrypted BIFF workbook")
+		case xlsRecordDate1904:
+			if data[0] == 1 {
+				wb := r.f.workbookReader()
+				if wb.WorkbookPr == nil {
+					wb.WorkbookPr = &xlsxWorkbookPr{}
+				}
+				wb.WorkbookPr.Date1904 = true
+			}
+		case xlsRecordFormat:
+			s := newXLSStream(rec, 2)
+			value, err := s.unicodeString(2)
+			if err != nil {
+				return err
+			}
+			r.formats[int(binary.LittleEndian.Uint16(data))] = value
+		case xlsRecordXF:
+			r.xfs = append(r.xfs, int(binary.LittleEndian.Uint16(data[2:])))
+		case xlsRecordBoundSheet:
+			s := newXLSStream(rec, 6)
+			name, err := s.unicodeString(1)
+			if err != nil {
+				return err
+			}
+			r.sheets = append(r.sheets, xlsSheet{name: name, offset: binary.LittleEndian.Uint32(data), state: data[4] & 3, kind: data[5]})
+		case xlsRecordSST:
+			if err := r.readSST(rec); err != nil {
+				return err
+			}
+		case xlsRecordSupBook:
+			r.supBooks = append(r.supBooks, binary.LittleEndian.Uint16(data[2:]) == 0x0401)
+		case xlsRecordExternSheet:
+			for i := 0; i < int(binary.LittleEndian.Uint16(data)) && 2+i*6+6 <= len(data); i++ {
+				r.externSheets = append(r.externSheets, [3]uint16{
+					binary.LittleEndian.Uint16(data[2+i*6:]), binary.LittleEndian.Uint16(data[4+i*6:]), binary.LittleEndian.Uint16(data[6+i*6:]),
+				})
+			}
+		case xlsRecordLbl:
+			r.names = append(r.names, r.readName(rec))
+		}
+		if rec.id == xlsRecordEOF {
+			break
+		}
+	}
+	return r.newSheets()
+}
+
+// newSheets provides a function to create the worksheets by the sheet
+// names, the first worksheet will use the default worksheet of the file.
+func (r *xlsReader) newSheets() error {
+	var count int
+	for _, sheet := range r.sheets {
+		if sheet.kind != 0 {
+			continue
+		}
+		if name := trimSheetName(sheet.name); name == "" || name != sheet.name || count > 0 && r.f.GetSheetIndex(name) != -1 {
+			return fmt.Errorf("invalid sheet name %q", sheet.name)
+		}
+		if count++; count == 1 {
+			r.f.SetSheetName("Sheet1", sheet.name)
+		} else {
+			r.f.NewSheet(sheet.name)
+		}
+	}
+	if count == 0 {
+		return errors.New("no worksheet in BIFF workbook")
+	}
+	for _, sheet := range r.sheets {
+		if sheet.kind == 0 && sheet.state != 0 {
+			if err := r.f.SetSheetVisible(sheet.name, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readSST provides a function to read the shared string table.
+func (r *xlsReader) readSST(rec xlsRecord) error {
+	s := newXLSStream(rec, 8)
+	count := int(binary.LittleEndian.Uint32(rec.data[4:]))
+	for i := 0; i < count && s.pos < len(s.data); i++ {
+		value, err := s.richString()
+		if err != nil {
+			return err
+		}
+		r.sst = append(r.sst, value)
+	}
+	return nil
+}
+
+// readName provides a function to read the name of the defined name record.
+func (r *xlsReader) readName(rec xlsRecord) string {
+	if binary.LittleEndian.Uint16(rec.data)&0x20 != 0 {
+		s := newXLSStream(rec, 14)
+		if b, err := s.bytes(1); err == nil && b[0]&1 == 0 {
+			if b, err = s.bytes(1); err == nil {
+				return xlsBuiltInNames[b[0]]
+			}
+		}
+		return ""
+	}
+	s := newXLSStream(rec, 14)
+	flags, err := s.bytes(1)
+	if err != nil {
+		return ""
+	}
+	name, _ := s.characters(int(rec.data[3]), flags[0], 14)
+	return name
+}
+
+// readSheet provides a function to read the worksheet substream by given
+// index of the sheet.
+func (r *xlsReader) readSheet(index int) error {
+	sheet := r.sheets[index]
+	start := -1
+	for i, rec := range r.records {
+		if rec.offset == int(sheet.offset) && rec.id == xlsRecordBOF {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return fmt.Errorf("invalid offset of sheet %q", sheet.name)
+	}
+	r.sheet, r.pending, r.stringCell, r.cells = sheet.name, nil, "", 0
+	r.sharedFormulas = make(map[[2]int][]byte)
+	for i, depth := start, 0; i < len(r.records); i++ {
+		rec := r.records[i]
+		switch rec.id {
+		case xlsRecordBOF:
+			depth++
+			continue
+		case xlsRecordEOF:
+			if depth--; depth == 0 {
+				return nil
+			}
+			continue
+		}
+		if depth != 1 {
+			continue
+		}
+		if err := r.readSheetRecord(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSheetRecord provides a function to read the record of the worksheet
+// substream.
+func (r *xlsReader) readSheetRecord(rec xlsRecord) error {
+	data := rec.data
+	if rec.id != xlsRecordString && rec.id != xlsRecordSharedFormula {
+		r.stringCell = ""
+	}
+	if err := r.countCells(rec); err != nil {
+		return err
+	}
+	switch rec.id {
+	case xlsRecordRow:
+		row, height, flags := int(binary.LittleEndian.Uint16(data))+1, binary.LittleEndian.Uint16(data[6:])&0x7FFF, binary.LittleEndian.Uint32(data[12:])
+		if flags&0x40 != 0 {
+			if err := r.f.SetRowHeight(r.sheet, row, float64(height)/20); err != nil {
+				return err
+			}
+		}
+		if flags&0x20 != 0 {
+			return r.f.SetRowVisible(r.sheet, row, false)
+		}
+	case xlsRecordColInfo:
+		return r.setColumns(data)
+	case xlsRecordNumber:
+		return r.setCellValue(data, math.Float64frombits(binary.LittleEndian.Uint64(data[6:])))
+	case xlsRecordRK:
+		return r.setCellValue(data, decodeXLSRK(binary.LittleEndian.Uint32(data[6:])))
+	case xlsRecordMulRK:
+		for pos := 4; pos+6 <= len(data)-2; pos += 6 {
+			cell := make([]byte, 6)
+			copy(cell, data[:2])
+			binary.LittleEndian.PutUint16(cell[2:], binary.LittleEndian.Uint16(data[2:])+uint16((pos-4)/6))
+			copy(cell[4:], data[pos:pos+2])
+			if err := r.setCellValue(cell, decodeXLSRK(binary.LittleEndian.Uint32(data[pos+2:]))); err != nil {
+				return err
+			}
+		}
+	case xlsRecordLabelSST:
+		idx := int(binary.LittleEndian.Uint32(data[6:]))
+		if idx >= len(r.sst) {
+			return fmt.Errorf("invalid shared string index %d", idx)
+		}
+		return r.setCellValue(data, r.sst[idx])
+	case xlsRecordLabel:
+		value, err := newXLSStream(rec, 6).unicodeString(2)
+		if err != nil {
+			return err
+		}
+		return r.setCellValue(data, value)
+	case xlsRecordBoolErr:
+		if data[7] == 0 {
+			return r.setCellValue(data, data[6] != 0)
+		}
+		return r.setCellValue(data, xlsErrors[data[6]])
+	case xlsRecordFormula:
+		return r.setCellFormula(data)
+	case xlsRecordString:
+		if r.stringCell == "" {
+			return nil
+		}
+		value, err := newXLSStream(rec, 0).unicodeString(2)
+		if err != nil {
+			return err
+		}
+		axis := r.stringCell
+		r.stringCell = ""
+		return r.setCachedValue(axis, "str", value)
+	case xlsRecordSharedFormula:
+		return r.setSharedFormula(data)
+	case xlsRecordMergeCells:
+		for i := 0; i < int(binary.LittleEndian.Uint16(data)) && 2+i*8+8 <= len(data); i++ {
+			ref := data[2+i*8:]
+			hCell, _ := CoordinatesToCellName(int(binary.LittleEndian.Uint16(ref[4:]))+1, int(binary.LittleEndian.Uint16(ref))+1)
+			vCell, _ := CoordinatesToCellName(int(binary.LittleEndian.Uint16(ref[6:]))+1, int(binary.LittleEndian.Uint16(ref[2:]))+1)
+			if err := r.f.MergeCell(r.sheet, hCell, vCell); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// countCells provides a function to count the cells of the worksheet by
+// given cell record, returns an error if the number of cells exceeds the
+// MaxCellsPerSheet of the options.
+func (r *xlsReader) countCells(rec xlsRecord) error {
+	switch rec.id {
+	case xlsRecordNumber, xlsRecordRK, xlsRecordLabelSST, xlsRecordLabel, xlsRecordBoolErr, xlsRecordFormula:
+		r.cells++
+	case xlsRecordMulRK:
+		if len(rec.data) > 6 {
+			r.cells += (len(rec.data) - 6) / 6
+		}
+	}
+	if r.maxCells > 0 && r.cells > r.maxCells {
+		return newMaxCellsError(r.sheet, r.maxCells)
+	}
+	return nil
+}
+
+// setColumns provides a function to set the width and visibility of the
+// columns by given ColInfo record data.
+func (r *xlsReader) setColumns(data []byte) error {
+	first, last := int(binary.LittleEndian.Uint16(data))+1, int(binary.LittleEndian.Uint16(data[2:]))+1
+	if last > TotalColumns {
+		last = TotalColumns
+	}
+	if first > last {
+		return nil
+	}
+	startCol, _ := ColumnNumberToName(first)
+	endCol, _ := ColumnNumberToName(last)
+	width := math.Round(float64(binary.LittleEndian.Uint16(data[4:]))/256*100) / 100
+	if err := r.f.SetColWidth(r.sheet, startCol, endCol, width); err != nil {
+		return err
+	}
+	if binary.LittleEndian.Uint16(data[8:])&1 != 0 {
+		return r.f.SetColVisible(r.sheet, startCol+":"+endCol, false)
+	}
+	return nil
+}
+
+// getXLSCellName provides a function to get the cell name by given cell
+// record data which starts with the row, column and XF index.
+func getXLSCellName(data []byte) (string, error) {
+	return CoordinatesToCellName(int(binary.LittleEndian.Uint16(data[2:]))+1, int(binary.LittleEndian.Uint16(data))+1)
+}
+
+// setCellValue provides a function to set the value and number format of
+// the cell by given cell record data and value.
+func (r *xlsReader) setCellValue(data []byte, value interface{}) error {
+	axis, err := getXLSCellName(data)
+	if err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case float64:
+		err = r.f.SetCellFloat(r.sheet, axis, v, -1, 64)
+	case string:
+		if isFormulaError(v) {
+			err = r.setCachedValue(axis, "e", v)
+		} else {
+			err = r.f.SetCellStr(r.sheet, axis, v)
+		}
+	case bool:
+		err = r.f.SetCellBool(r.sheet, axis, v)
+	}
+	if err != nil {
+		return err
+	}
+	return r.setCellStyle(axis, int(binary.LittleEndian.Uint16(data[4:])))
+}
+
+// setCachedValue provides a function to set the data type and value of the
+// cell without changing the formula of the cell.
+func (r *xlsReader) setCachedValue(axis, typ, value string) error {
+	ws, err := r.f.workSheetReader(r.sheet)
+	if err != nil {
+		return err
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	cellData, _, _, err := r.f.prepareCell(ws, r.sheet, axis)
+	if err != nil {
+		return err
+	}
+	cellData.T, cellData.V = typ, value
+	return nil
+}
+
+// setCellStyle provides a function to set the number format of the cell by
+// given XF index, the styles will be created once for each number format.
+func (r *xlsReader) setCellStyle(axis string, xf int) error {
+	if xf >= len(r.xfs) || r.xfs[xf] == 0 {
+		return nil
+	}
+	numFmt := r.xfs[xf]
+	styleID, ok := r.styleIDs[numFmt]
+	if !ok {
+		style := &Style{NumFmt: numFmt}
+		if _, ok := builtInNumFmt[numFmt]; !ok {
+			format, ok := r.formats[numFmt]
+			if !ok {
+				return nil
+			}
+			style = &Style{CustomNumFmt: &format}
+		}
+		var err error
+		if styleID, err = r.f.NewStyle(style); err != nil {
+			return err
+		}
+		r.styleIDs[numFmt] = styleID
+	}
+	return r.f.SetCellStyle(r.sheet, axis, axis, styleID)
+}
+
+// setCellFormula provides a function to set the cached value and formula
+// of the cell by given Formula record data. The cached string value will be
+// read from the following String record.
+func (r *xlsReader) setCellFormula(data []byte) error {
+	axis, err := getXLSCellName(data)
+	if err != nil {
+		return err
+	}
+	value := data[6:14]
+	if binary.LittleEndian.Uint16(value[6:]) != 0xFFFF {
+		err = r.setCellValue(data, math.Float64frombits(binary.LittleEndian.Uint64(value)))
+	} else {
+		switch value[0] {
+		case 0:
+			r.stringCell = axis
+		case 1:
+			err = r.setCellValue(data, value[2] != 0)
+		case 2:
+			err = r.setCellValue(data, xlsErrors[value[2]])
+		case 3:
+			err = r.setCachedValue(axis, "str", "")
+		}
+		if err == nil {
+			err = r.setCellStyle(axis, int(binary.LittleEndian.Uint16(data[4:])))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	cce := int(binary.LittleEndian.Uint16(data[20:]))
+	if 22+cce > len(data) {
+		return nil
+	}
+	rgce := data[22 : 22+cce]
+	row, col := int(binary.LittleEndian.Uint16(data)), int(binary.LittleEndian.Uint16(data[2:]))
+	if len(rgce) == 5 && rgce[0] == 0x01 {
+		cell := xlsFormulaCell{
+			axis: axis, row: row, col: col,
+			anchorRow: int(binary.LittleEndian.Uint16(rgce[1:])), anchorCol: int(binary.LittleEndian.Uint16(rgce[3:])),
+		}
+		if shared, ok := r.sharedFormulas[[2]int{cell.anchorRow, cell.anchorCol}]; ok {
+			return r.setFormula(axis, shared, row, col)
+		}
+		r.pending = append(r.pending, cell)
+		return nil
+	}
+	return r.setFormula(axis, rgce, row, col)
+}
+
+// setSharedFormula provides a function to read the shared formula by given
+// ShrFmla record data, and set the formulas of the pending cells which refer
+// to the shared formula.
+func (r *xlsReader) setSharedFormula(data []byte) error {
+	cce := int(binary.LittleEndian.Uint16(data[8:]))
+	if 10+cce > len(data) {
+		return nil
+	}
+	key := [2]int{int(binary.LittleEndian.Uint16(data)), int(data[4])}
+	r.sharedFormulas[key] = data[10 : 10+cce]
+	pending := r.pending[:0]
+	for _, cell := range r.pending {
+		if cell.anchorRow != key[0] || cell.anchorCol != key[1] {
+			pending = append(pending, cell)
+			continue
+		}
+		if err := r.setFormula(cell.axis, r.sharedFormulas[key], cell.row, cell.col); err != nil {
+			return err
+		}
+	}
+	r.pending = pending
+	return nil
+}
+
+// setFormula provides a function to set the formula of the cell by given
+// parsed formula tokens and the zero-based coordinates of the cell, the
+// formula with unsupported tokens will be ignored.
+func (r *xlsReader) setFormula(axis string, rgce []byte, row, col int) error {
+	formula, err := r.decodeFormula(rgce, row, col)
+	if err != nil {
+		return nil
+	}
+	ws, err := r.f.workSheetReader(r.sheet)
+	if err != nil {
+		return err
+	}
+	ws.Lock()
+	defer ws.Unlock()
+	cellData, _, _, err := r.f.prepareCell(ws, r.sheet, axis)
+	if err != nil {
+		return err
+	}
+	cellData.F = &xlsxF{Content: formula}
+	return nil
+}
+
+// decodeFormula provides a function to convert the parsed formula tokens to
+// the formula by given tokens and the zero-based coordinates of the cell
+// which the relative references are based on.
+func (r *xlsReader) decodeFormula(rgce []byte, row, col int) (string, error) {
+	var (
+		stack []string
+		i     int
+	)
+	need := func(n int) error {
+		if i+n > len(rgce) {
+			return errors.New("invalid formula tokens")
+		}
+		return nil
+	}
+	pop := func(n int) ([]string, error) {
+		if n > len(stack) {
+			return nil, errors.New("invalid formula tokens")
+		}
+		args := append([]string{}, stack[len(stack)-n:]...)
+		stack = stack[:len(stack)-n]
+		return args, nil
+	}
+	for i < len(rgce) {
+		ptg := rgce[i]
+		if i++; ptg >= 0x20 && ptg < 0x80 {
+			ptg = 0x20 | ptg&0x1F
+		}
+		var size int
+		switch ptg {
+		case 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11:
+			args, err := pop(2)
+			if err != nil {
+				return "", err
+			}
+			stack = append(stack, args[0]+xlsOperators[ptg]+args[1])
+		case 0x12, 0x13, 0x14, 0x15:
+			args, err := pop(1)
+			if err != nil {
+				return "", err
+			}
+			stack = append(stack, map[byte]string{0x12: "+", 0x13: "-", 0x15: "("}[ptg]+args[0]+map[byte]string{0x14: "%", 0x15: ")"}[ptg])
+		case 0x16:
+			stack = append(stack, "")
+		case 0x17:
+			if err := need(2); err != nil {
+				return "", err
+			}
+			s := &xlsStream{data: rgce, pos: i + 2}
+			value, err := s.characters(int(rgce[i]), rgce[i+1], i)
+			if err != nil {
+				return "", err
+			}
+			i = s.pos
+			stack = append(stack, `"`+strings.Replace(value, `"`, `""`, -1)+`"`)
+		case 0x19:
+			if err := need(3); err != nil {
+				return "", err
+			}
+			grbit := rgce[i]
+			if size = 3; grbit&0x04 != 0 {
+				size += 2 * (int(binary.LittleEndian.Uint16(rgce[i+1:])) + 1)
+			}
+			if grbit&0x10 != 0 {
+				args, err := pop(1)
+				if err != nil {
+					return "", err
+				}
+				stack = append(stack, "SUM("+args[0]+")")
+			}
+		case 0x1C:
+			if size = 1; need(1) == nil {
+				stack = append(stack, xlsErrors[rgce[i]])
+			}
+		case 0x1D:
+			if size = 1; need(1) == nil {
+				stack = append(stack, map[bool]string{true: "TRUE", false: "FALSE"}[rgce[i] != 0])
+			}
+		case 0x1E:
+			if size = 2; need(2) == nil {
+				stack = append(stack, strconv.Itoa(int(binary.LittleEndian.Uint16(rgce[i:]))))
+			}
+		case 0x1F:
+			if size = 8; need(8) == nil {
+				stack = append(stack, strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(rgce[i:])), 'g', -1, 64))
+			}
+		case 0x21, 0x22:
+			var (
+				fn   xlsFunction
+				ok   bool
+				argc int
+			)
+			if ptg == 0x21 {
+				if size = 2; need(2) != nil {
+					break
+				}
+				fn, ok = xlsFunctions[binary.LittleEndian.Uint16(rgce[i:])]
+				argc = fn.args
+			} else {
+				if size = 3; need(3) != nil {
+					break
+				}
+				argc = int(rgce[i] & 0x7F)
+				fn, ok = xlsFunctions[binary.LittleEndian.Uint16(rgce[i+1:])&0x7FFF]
+			}
+			if !ok || argc < 0 {
+				return "", errors.New("unsupported function in formula")
+			}
+			args, err := pop(argc)
+			if err != nil {
+				return "", err
+			}
+			stack = append(stack, fn.name+"("+strings.Join(args, ",")+")")
+		case 0x23:
+			if size = 4; need(4) == nil {
+				idx := int(binary.LittleEndian.Uint32(rgce[i:]))
+				if idx < 1 || idx > len(r.names) || r.names[idx-1] == "" {
+					return "", errors.New("unsupported name in formula")
+				}
+				stack = append(stack, r.names[idx-1])
+			}
+		case 0x24, 0x2C:
+			if size = 4; need(4) == nil {
+				stack = append(stack, getXLSCellRef(rgce[i:], rgce[i+2:], ptg == 0x2C, row, col))
+			}
+		case 0x25, 0x2D:
+			if size = 8; need(8) == nil {
+				stack = append(stack, getXLSCellRef(rgce[i:], rgce[i+4:], ptg == 0x2D, row, col)+":"+getXLSCellRef(rgce[i+2:], rgce[i+6:], ptg == 0x2D, row, col))
+			}
+		case 0x26, 0x27, 0x28:
+			size = 6
+		case 0x29:
+			size = 2
+		case 0x2A, 0x2B:
+			if size = 4; ptg == 0x2B {
+				size = 8
+			}
+			stack = append(stack, formulaErrorREF)
+		case 0x3A, 0x3B, 0x3C, 0x3D:
+			size = map[byte]int{0x3A: 6, 0x3B: 10, 0x3C: 6, 0x3D: 10}[ptg]
+			if err := need(size); err != nil {
+				return "", err
+			}
+			sheet, err := r.getExternSheet(int(binary.LittleEndian.Uint16(rgce[i:])))
+			if err != nil {
+				return "", err
+			}
+			ref := formulaErrorREF
+			switch ptg {
+			case 0x3A:
+				ref = getXLSCellRef(rgce[i+2:], rgce[i+4:], false, row, col)
+			case 0x3B:
+				ref = getXLSCellRef(rgce[i+2:], rgce[i+6:], false, row, col) + ":" + getXLSCellRef(rgce[i+4:], rgce[i+8:], false, row, col)
+			}
+			stack = append(stack, sheet+"!"+ref)
+		default:
+			return "", fmt.Errorf("unsupported formula token 0x%02X", ptg)
+		}
+		if err := need(size); err != nil {
+			return "", err
+		}
+		i += size
+	}
+	if len(stack) != 1 {
+		return "", errors.New("invalid formula tokens")
+	}
+	return stack[0], nil
+}
+
+// getExternSheet provides a function to get the quoted sheet name of the 3D
+// reference by given index of the XTI array.
+func (r *xlsReader) getExternSheet(ixti int) (string, error) {
+	if ixti >= len(r.externSheets) {
+		return "", errors.New("invalid sheet reference in formula")
+	}
+	xti := r.externSheets[ixti]
+	if int(xti[0]) >= len(r.supBooks) || !r.supBooks[xti[0]] || int(xti[1]) >= len(r.sheets) || int(xti[2]) >= len(r.sheets) {
+		return "", errors.New("unsupported sheet reference in formula")
+	}
+	if xti[1] == xti[2] {
+		return quoteSheetName(r.sheets[xti[1]].name), nil
+	}
+	return quoteSheetName(r.sheets[xti[1]].name + ":" + r.sheets[xti[2]].name), nil
+}
+
+// getXLSCellRef provides a function to get the cell reference by given row
+// and column fields of the reference token, the relative reference of the
+// shared formula is based on the zero-based coordinates of the cell.
+func getXLSCellRef(rw, colField []byte, relative bool, row, col int) string {
+	r, c := int(binary.LittleEndian.Uint16(rw)), binary.LittleEndian.Uint16(colField)
+	colNum, rowRel, colRel := int(c&0x3FFF), c&0x8000 != 0, c&0x4000 != 0
+	if relative && rowRel {
+		r = (row + int(int16(r))) & 0xFFFF
+	}
+	if relative && colRel {
+		colNum = (col + int(int8(c&0xFF))) & 0xFF
+	}
+	name, _ := ColumnNumberToName(colNum + 1)
+	if !colRel {
+		name = "$" + name
+	}
+	if !rowRel {
+		name += "$"
+	}
+	return name + strconv.Itoa(r+1)
+}
+
+// decodeXLSRK provides a function to decode the RK number.
+func decodeXLSRK(rk uint32) float64 {
+	var value float64
+	if rk&2 != 0 {
+		value = float64(int32(rk) >> 2)
+	} else {
+		value = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&1 != 0 {
+		value /= 100
+	}
+	return value
+}
+
+// newXLSStream provides a function to create the reader of the record data
+// with the following CONTINUE records by given record and start position.
+func newXLSStream(rec xlsRecord, pos int) *xlsStream {
+	s := &xlsStream{data: rec.data, bounds: make(map[int]bool), pos: pos}
+	if len(rec.continues) > 0 {
+		s.data = append([]byte{}, rec.data...)
+		for _, data := range rec.continues {
+			s.bounds[len(s.data)] = true
+			s.data = append(s.data, data...)
+		}
+	}
+	return s
+}
+
+// bytes provides a function to read the given number of bytes.
+func (s *xlsStream) bytes(n int) ([]byte, error) {
+	if n < 0 || s.pos+n > len(s.data) {
+		return nil, errors.New("invalid BIFF string")
+	}
+	b := s.data[s.pos : s.pos+n]
+	s.pos += n
+	return b, nil
+}
+
+// unicodeString provides a function to read the string with the count of
+// characters in given size of bytes, and the option flags.
+func (s *xlsStream) unicodeString(size int) (string, error) {
+	start := s.pos
+	b, err := s.bytes(size + 1)
+	if err != nil {
+		return "", err
+	}
+	cch := int(b[0])
+	if size == 2 {
+		cch = int(binary.LittleEndian.Uint16(b))
+	}
+	return s.characters(cch, b[size], start)
+}
+
+// richString provides a function to read the rich extended string of the
+// shared string table, the formatting runs and phonetic data are skipped.
+func (s *xlsStream) richString() (string, error) {
+	start := s.pos
+	b, err := s.bytes(3)
+	if err != nil {
+		return "", err
+	}
+	cch, flags := int(binary.LittleEndian.Uint16(b)), b[2]
+	var runs, ext int
+	if flags&0x08 != 0 {
+		if b, err = s.bytes(2); err != nil {
+			return "", err
+		}
+		runs = int(binary.LittleEndian.Uint16(b))
+	}
+	if flags&0x04 != 0 {
+		if b, err = s.bytes(4); err != nil {
+			return "", err
+		}
+		ext = int(binary.LittleEndian.Uint32(b))
+	}
+	value, err := s.characters(cch, flags, start)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.bytes(runs*4 + ext)
+	return value, err
+}
+
+// characters provides a function to read the given count of characters
+// with the option flags, the characters are stored as UTF-16 or the low
+// bytes of UTF-16 by the high byte flag. The new option flags will be read
+// at the beginning of the CONTINUE records except for the string which
+// starts at the beginning of the CONTINUE record.
+func (s *xlsStream) characters(cch int, flags byte, start int) (string, error) {
+	chars := make([]uint16, 0, cch)
+	for len(chars) < cch {
+		if s.bounds[s.pos] && s.pos != start {
+			b, err := s.bytes(1)
+			if err != nil {
+				return "", err
+			}
+			flags = b[0]
+		}
+		if flags&1 != 0 {
+			b, err := s.bytes(2)
+			if err != nil {
+				return "", err
+			}
+			chars = append(chars, binary.LittleEndian.Uint16(b))
+			continue
+		}
+		b, err := s.bytes(1)
+		if err != nil {
+			return "", err
+		}
+		chars = append(chars, uint16(b[0]))
+	}
+	return string(utf16.Decode(chars)), nil
+}
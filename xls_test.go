@@ -0,0 +1,321 @@
+package excelize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenXLS(t *testing.T) {
+	f, err := OpenReader(bytes.NewReader(newCFBFile(newXLSWorkbookStream("Data"))))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Data", "Other Sheet"}, f.GetSheetList())
+
+	// Test open BIFF8 workbook with the default first sheet name.
+	f, err = OpenReader(bytes.NewReader(newCFBFile(newXLSWorkbookStream("Sheet1"))))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Other Sheet"}, f.GetSheetList())
+	assert.False(t, f.GetSheetVisible("Other Sheet"))
+
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Hello", "abcdef", "Rich", "label"},
+		{"12.5", "42", "1.23"},
+		{"10-18-20", "1.5"},
+		{"1", "#DIV/0!", "67", "55.73"},
+		{"xy", "#DIV/0!"},
+		{"1", "9"},
+		{"2"},
+	}, rows)
+	for axis, expected := range map[string]string{
+		"C4": "A2*2+B2", "D4": "SUM(A2:C2)", "A5": `"x"&"y"`, "B5": "'Other Sheet'!$A$1*Rate",
+		"A6": "B5+1", "A7": "B6+1", "B6": "",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, axis)
+	}
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	for axis, expected := range map[string]string{"A4": "b", "B4": "e", "A5": "str", "B5": "e"} {
+		col, row, err := CellNameToCoordinates(axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ws.SheetData.Row[row-1].C[col-1].T, axis)
+	}
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A8", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "B9", mergeCells[0].GetEndAxis())
+	width, err := f.GetColWidth("Sheet1", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	visible, err := f.GetColVisible("Sheet1", "D")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	height, err := f.GetRowHeight("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	visible, err = f.GetRowVisible("Sheet1", 6)
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	styleID, err := f.GetCellStyle("Sheet1", "B3")
+	assert.NoError(t, err)
+	numFmtID := *f.stylesReader().CellXfs.Xf[styleID].NumFmtID
+	var formatCode string
+	for _, numFmt := range f.stylesReader().NumFmts.NumFmt {
+		if numFmt.NumFmtID == numFmtID {
+			formatCode = numFmt.FormatCode
+		}
+	}
+	assert.Equal(t, "0.000", formatCode)
+	value, err := f.GetCellValue("Other Sheet", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "5", value)
+
+	// Test open BIFF8 workbook with the limits.
+	b := newCFBFile(newXLSWorkbookStream("Sheet1"))
+	_, err = OpenReader(bytes.NewReader(b), Options{MaxCellsPerSheet: 10})
+	assert.EqualError(t, err, "the number of cells exceeds the limit 10: Sheet1")
+	assert.True(t, errors.Is(err, ErrMaxCells))
+	_, err = OpenReader(bytes.NewReader(b), Options{UnzipSizeLimit: 100})
+	assert.True(t, errors.Is(err, ErrUnzipSizeLimit))
+	_, err = OpenReader(bytes.NewReader(b), Options{MaxCellsPerSheet: 100, UnzipSizeLimit: int64(len(b))})
+	assert.NoError(t, err)
+
+	// Test open BIFF8 workbook with invalid records.
+	stream := newXLSWorkbookStream("Sheet1")
+	for _, c := range []struct {
+		stream   []byte
+		expected string
+	}{
+		{nil, "invalid BIFF workbook stream"},
+		{newXLSRecord(xlsRecordBOF, []byte{0, 5, 0, 0}), "unsupported BIFF version, only BIFF8 workbooks are supported"},
+		{append(newXLSRecord(xlsRecordBOF, []byte{0, 6, 5, 0}), newXLSRecord(xlsRecordFilePass, nil)...), "unsupported encrypted BIFF workbook"},
+		{append(newXLSRecord(xlsRecordBOF, []byte{0, 6, 5, 0}), newXLSRecord(xlsRecordNumber, nil)...), "invalid BIFF record 0x0203"},
+		{append(newXLSRecord(xlsRecordBOF, []byte{0, 6, 5, 0}), 0x03, 0x02, 0xFF, 0), "invalid BIFF record 0x0203"},
+		{newXLSRecord(xlsRecordBOF, []byte{0, 6, 5, 0}), "no worksheet in BIFF workbook"},
+		{append(newXLSRecord(xlsRecordBOF, []byte{0, 6, 5, 0}), newXLSBoundSheet("A:B", 0, 0, 0)...), `invalid sheet name "A:B"`},
+		{append(newXLSRecord(xlsRecordBOF, []byte{0, 6, 5, 0}), newXLSBoundSheet("A", 100, 0, 0)...), `invalid offset of sheet "A"`},
+		{stream[:len(stream)-len(newXLSRecord(xlsRecordEOF, nil))-2], "invalid BIFF record 0x0203"},
+	} {
+		_, err = openXLS(c.stream, Options{})
+		assert.EqualError(t, err, c.expected)
+	}
+}
+
+func TestDecodeXLSFormula(t *testing.T) {
+	r := &xlsReader{names: []string{""}}
+	for _, c := range []struct {
+		rgce     []byte
+		expected string
+	}{
+		{[]byte{0x1E, 1, 0, 0x1E, 2, 0, 0x07, 0x15, 0x13, 0x14}, "-(1^2)%"},
+		{[]byte{0x1D, 1, 0x1C, 0x2A, 0x16, 0x42, 3, 1, 0}, "IF(TRUE,#N/A,)"},
+		{[]byte{0x1F, 0, 0, 0, 0, 0, 0, 0xF8, 0x3F, 0x41, 19, 0, 0x08}, "1.5&PI()"},
+		{[]byte{0x17, 2, 1, 'a', 0, '"', 0}, `"a"""`},
+		{[]byte{0x2A, 0, 0, 0, 0, 0x2B, 0, 0, 0, 0, 0, 0, 0, 0, 0x0F}, "#REF! #REF!"},
+		{[]byte{0x25, 0, 0, 1, 0, 0, 0, 1, 0, 0x15}, "($A$1:$B$2)"},
+		{[]byte{0x19, 0x04, 1, 0, 0, 0, 0, 0, 0x1E, 1, 0, 0x19, 0x40, 0, 0, 0x26, 0, 0, 0, 0, 0, 0, 0x29, 0, 0}, "1"},
+	} {
+		formula, err := r.decodeFormula(c.rgce, 0, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, formula)
+	}
+	for _, c := range []struct {
+		rgce     []byte
+		expected string
+	}{
+		{[]byte{0x03}, "invalid formula tokens"},
+		{[]byte{0x1E, 1}, "invalid formula tokens"},
+		{[]byte{0x1E, 1, 0, 0x1E, 1, 0}, "invalid formula tokens"},
+		{[]byte{0x22, 1, 0xFF, 0}, "unsupported function in formula"},
+		{[]byte{0x23, 1, 0, 0, 0}, "unsupported name in formula"},
+		{[]byte{0x3A, 0, 0, 0, 0, 0, 0}, "invalid sheet reference in formula"},
+		{[]byte{0x18}, "unsupported formula token 0x18"},
+	} {
+		_, err := r.decodeFormula(c.rgce, 0, 0)
+		assert.EqualError(t, err, c.expected)
+	}
+	r.externSheets, r.supBooks = [][3]uint16{{0, 0, 0}}, []bool{false}
+	_, err := r.decodeFormula([]byte{0x3A, 0, 0, 0, 0, 0, 0}, 0, 0)
+	assert.EqualError(t, err, "unsupported sheet reference in formula")
+	assert.Equal(t, -1.25, decodeXLSRK(uint32(math.Float64bits(-1.25)>>32)))
+}
+
+// newXLSWorkbookStream provides a function to create the BIFF8 workbook
+// stream for testing by given name of the first worksheet.
+func newXLSWorkbookStream(first string) []byte {
+	xf := func(numFmt uint16) []byte {
+		data := make([]byte, 20)
+		binary.LittleEndian.PutUint16(data[2:], numFmt)
+		return data
+	}
+	name := []byte{0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 'R', 'a', 't', 'e'}
+	sst := append(newXLSRecord(xlsRecordSST, []byte{3, 0, 0, 0, 3, 0, 0, 0, 5, 0, 0, 'H', 'e', 'l', 'l', 'o', 6, 0, 0, 'a', 'b', 'c'}),
+		append(newXLSRecord(xlsRecordContinue, []byte{1, 'd', 0, 'e', 0, 'f', 0}),
+			newXLSRecord(xlsRecordContinue, []byte{4, 0, 0x08, 1, 0, 'R', 'i', 'c', 'h', 0, 0, 0, 0})...)...)
+	globals := func(offsets []uint32) []byte {
+		var b []byte
+		for _, data := range [][]byte{
+			newXLSRecord(xlsRecordBOF, []byte{0, 6, 5, 0}),
+			newXLSRecord(xlsRecordFormat, append([]byte{164, 0, 5, 0, 0}, "0.000"...)),
+			newXLSRecord(xlsRecordXF, xf(0)), newXLSRecord(xlsRecordXF, xf(14)), newXLSRecord(xlsRecordXF, xf(164)),
+			newXLSBoundSheet(first, offsets[0], 0, 0),
+			newXLSBoundSheet("Chart", offsets[1], 0, 2),
+			newXLSBoundSheet("Other Sheet", offsets[2], 1, 0),
+			newXLSRecord(xlsRecordSupBook, []byte{3, 0, 1, 4}),
+			newXLSRecord(xlsRecordExternSheet, []byte{1, 0, 0, 0, 2, 0, 2, 0}),
+			newXLSRecord(xlsRecordLbl, name),
+			sst,
+			newXLSRecord(xlsRecordEOF, nil),
+		} {
+			b = append(b, data...)
+		}
+		return b
+	}
+	cell := func(row, col, xf uint16, data ...byte) []byte {
+		b := make([]byte, 6)
+		binary.LittleEndian.PutUint16(b, row)
+		binary.LittleEndian.PutUint16(b[2:], col)
+		binary.LittleEndian.PutUint16(b[4:], xf)
+		return append(b, data...)
+	}
+	number := func(value float64) []byte {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, math.Float64bits(value))
+		return b
+	}
+	formula := func(row, col uint16, value []byte, rgce ...byte) []byte {
+		data := append(cell(row, col, 0, value...), 0, 0, 0, 0, 0, 0, byte(len(rgce)), 0)
+		return newXLSRecord(xlsRecordFormula, append(data, rgce...))
+	}
+	rk := func(value uint32) []byte {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, value)
+		return b
+	}
+	var data []byte
+	for _, rec := range [][]byte{
+		newXLSRecord(xlsRecordBOF, []byte{0, 6, 0x10, 0}),
+		newXLSRecord(xlsRecordColInfo, []byte{1, 0, 2, 0, 0, 20, 0, 0, 0, 0}),
+		newXLSRecord(xlsRecordColInfo, []byte{3, 0, 3, 0, 0, 10, 0, 0, 1, 0}),
+		newXLSRecord(xlsRecordRow, []byte{1, 0, 0, 0, 3, 0, 0x58, 2, 0, 0, 0, 0, 0x40, 0, 0, 0}),
+		newXLSRecord(xlsRecordRow, []byte{5, 0, 0, 0, 3, 0, 0xFF, 0, 0, 0, 0, 0, 0x20, 0, 0, 0}),
+		newXLSRecord(xlsRecordLabelSST, cell(0, 0, 0, 0, 0, 0, 0)),
+		newXLSRecord(xlsRecordLabelSST, cell(0, 1, 0, 1, 0, 0, 0)),
+		newXLSRecord(xlsRecordLabelSST, cell(0, 2, 0, 2, 0, 0, 0)),
+		newXLSRecord(xlsRecordLabel, cell(0, 3, 0, 5, 0, 0, 'l', 'a', 'b', 'e', 'l')),
+		newXLSRecord(xlsRecordNumber, cell(1, 0, 0, number(12.5)...)),
+		newXLSRecord(xlsRecordRK, cell(1, 1, 0, rk(42<<2|2)...)),
+		newXLSRecord(xlsRecordRK, cell(1, 2, 0, rk(123<<2|3)...)),
+		newXLSRecord(xlsRecordMulRK, append(append(append([]byte{2, 0, 0, 0, 1, 0}, rk(44122<<2|2)...), append([]byte{2, 0}, rk(0x3FF80000)...)...), 1, 0)),
+		newXLSRecord(xlsRecordBoolErr, cell(3, 0, 0, 1, 0)),
+		newXLSRecord(xlsRecordBoolErr, cell(3, 1, 0, 0x07, 1)),
+		formula(3, 2, number(67), 0x24, 1, 0, 0, 0xC0, 0x1E, 2, 0, 0x05, 0x24, 1, 0, 1, 0xC0, 0x03),
+		formula(3, 3, number(55.73), 0x25, 1, 0, 1, 0, 0, 0xC0, 2, 0xC0, 0x19, 0x10, 0, 0),
+		formula(4, 0, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}, 0x17, 1, 0, 'x', 0x17, 1, 0, 'y', 0x08),
+		newXLSRecord(xlsRecordString, []byte{2, 0, 0, 'x', 'y'}),
+		formula(4, 1, []byte{2, 0, 0x07, 0, 0, 0, 0xFF, 0xFF}, 0x3A, 0, 0, 0, 0, 0, 0, 0x23, 1, 0, 0, 0, 0x05),
+		formula(5, 0, number(1), 0x01, 5, 0, 0, 0),
+		newXLSRecord(xlsRecordSharedFormula, []byte{5, 0, 6, 0, 0, 0, 0, 2, 9, 0, 0x2C, 0xFF, 0xFF, 1, 0xC0, 0x1E, 1, 0, 0x03}),
+		formula(5, 1, number(9), 0x22, 0, 0xFF, 0),
+		formula(6, 0, number(2), 0x01, 5, 0, 0, 0),
+		newXLSRecord(xlsRecordMergeCells, []byte{1, 0, 7, 0, 8, 0, 0, 0, 1, 0}),
+		newXLSRecord(xlsRecordBOF, []byte{0, 6, 0x20, 0}),
+		newXLSRecord(xlsRecordNumber, cell(0, 0, 0, number(100)...)),
+		newXLSRecord(xlsRecordEOF, nil),
+		newXLSRecord(xlsRecordEOF, nil),
+	} {
+		data = append(data, rec...)
+	}
+	chart := append(newXLSRecord(xlsRecordBOF, []byte{0, 6, 0x20, 0}), newXLSRecord(xlsRecordEOF, nil)...)
+	other := append(append(newXLSRecord(xlsRecordBOF, []byte{0, 6, 0x10, 0}), newXLSRecord(xlsRecordNumber, cell(0, 0, 0, number(5)...))...), newXLSRecord(xlsRecordEOF, nil)...)
+	size := uint32(len(globals([]uint32{0, 0, 0})))
+	stream := globals([]uint32{size, size + uint32(len(data)), size + uint32(len(data)+len(chart))})
+	return append(append(append(stream, data...), chart...), other...)
+}
+
+// newXLSRecord provides a function to create the BIFF8 record by given
+// record type and data.
+func newXLSRecord(id uint16, data []byte) []byte {
+	b := make([]byte, 4, 4+len(data))
+	binary.LittleEndian.PutUint16(b, id)
+	binary.LittleEndian.PutUint16(b[2:], uint16(len(data)))
+	return append(b, data...)
+}
+
+// newXLSBoundSheet provides a function to create the BoundSheet8 record by
+// given sheet name, stream offset, visibility state and sheet type.
+func newXLSBoundSheet(name string, offset uint32, state, kind byte) []byte {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint32(data, offset)
+	data[4], data[5], data[6], data[7] = state, kind, byte(len(utf16.Encode([]rune(name)))), 0
+	return newXLSRecord(xlsRecordBoundSheet, append(data, name...))
+}
+
+// newCFBFile provides a function to create the compound file which
+// contains the workbook stream for testing, the stream will be padded to
+// the size of the mini stream cutoff.
+func newCFBFile(stream []byte) []byte {
+	const endOfChain, freeSect, noStream = 0xFFFFFFFE, 0xFFFFFFFF, 0xFFFFFFFF
+	for len(stream) < 4096 {
+		stream = append(stream, 0)
+	}
+	sectors := (len(stream) + 511) / 512
+	header := make([]byte, 512)
+	copy(header, oleIdentifier)
+	for offset, value := range map[int]uint16{24: 0x3E, 26: 3, 28: 0xFFFE, 30: 9, 32: 6} {
+		binary.LittleEndian.PutUint16(header[offset:], value)
+	}
+	for offset, value := range map[int]uint32{44: 1, 48: 1, 56: 4096, 60: endOfChain, 68: endOfChain, 76: 0} {
+		binary.LittleEndian.PutUint32(header[offset:], value)
+	}
+	for i := 1; i < 109; i++ {
+		binary.LittleEndian.PutUint32(header[76+i*4:], freeSect)
+	}
+	fat := make([]byte, 512)
+	for i := 0; i < 128; i++ {
+		value := uint32(freeSect)
+		switch {
+		case i == 0:
+			value = 0xFFFFFFFD
+		case i == 1 || i == sectors+1:
+			value = endOfChain
+		case i < sectors+1:
+			value = uint32(i + 1)
+		}
+		binary.LittleEndian.PutUint32(fat[i*4:], value)
+	}
+	dir := make([]byte, 512)
+	for i, entry := range []struct {
+		name         string
+		kind         byte
+		child, start uint32
+		size         int
+	}{{"Root Entry", 5, 1, endOfChain, 0}, {"Workbook", 2, noStream, 2, len(stream)}, {"", 0, noStream, 0, 0}, {"", 0, noStream, 0, 0}} {
+		b := dir[i*128:]
+		name := utf16.Encode([]rune(entry.name))
+		for j, c := range name {
+			binary.LittleEndian.PutUint16(b[j*2:], c)
+		}
+		if len(name) > 0 {
+			binary.LittleEndian.PutUint16(b[64:], uint16(len(name)*2+2))
+		}
+		b[66], b[67] = entry.kind, 1
+		binary.LittleEndian.PutUint32(b[68:], noStream)
+		binary.LittleEndian.PutUint32(b[72:], noStream)
+		binary.LittleEndian.PutUint32(b[76:], entry.child)
+		binary.LittleEndian.PutUint32(b[116:], entry.start)
+		binary.LittleEndian.PutUint64(b[120:], uint64(entry.size))
+	}
+	data := append(append(header, fat...), dir...)
+	data = append(data, stream...)
+	return append(data, make([]byte, sectors*512-len(stream))...)
+}
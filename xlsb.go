@@ -0,0 +1,754 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Record types of the binary workbook parts.
+const (
+	xlsbRecordRowHdr         = 0
+	xlsbRecordCellBlank      = 1
+	xlsbRecordCellRk         = 2
+	xlsbRecordCellError      = 3
+	xlsbRecordCellBool       = 4
+	xlsbRecordCellReal       = 5
+	xlsbRecordCellSt         = 6
+	xlsbRecordCellIsst       = 7
+	xlsbRecordFmlaString     = 8
+	xlsbRecordFmlaNum        = 9
+	xlsbRecordFmlaBool       = 10
+	xlsbRecordFmlaError      = 11
+	xlsbRecordSSTItem        = 19
+	xlsbRecordFont           = 43
+	xlsbRecordFmt            = 44
+	xlsbRecordFill           = 45
+	xlsbRecordXF             = 47
+	xlsbRecordColInfo        = 60
+	xlsbRecordBeginSheetData = 145
+	xlsbRecordEndSheetData   = 146
+	xlsbRecordWbProp         = 153
+	xlsbRecordBundleSh       = 156
+	xlsbRecordMergeCell      = 176
+	xlsbRecordEndMergeCells  = 178
+	xlsbRecordBeginCellXFs   = 617
+	xlsbRecordEndCellXFs     = 618
+)
+
+// xlsbRecordSizes defined the minimum data size of the records which will be
+// read.
+var xlsbRecordSizes = map[int]int{
+	xlsbRecordRowHdr: 12, xlsbRecordCellBlank: 8, xlsbRecordCellRk: 12, xlsbRecordCellError: 9,
+	xlsbRecordCellBool: 9, xlsbRecordCellReal: 16, xlsbRecordCellSt: 12, xlsbRecordCellIsst: 12,
+	xlsbRecordFmlaString: 12, xlsbRecordFmlaNum: 16, xlsbRecordFmlaBool: 9, xlsbRecordFmlaError: 9,
+	xlsbRecordSSTItem: 5, xlsbRecordFont: 25, xlsbRecordFmt: 6, xlsbRecordFill: 20, xlsbRecordXF: 16,
+	xlsbRecordColInfo: 18, xlsbRecordWbProp: 4, xlsbRecordBundleSh: 16, xlsbRecordMergeCell: 16,
+}
+
+// xlsbHorizontalAlignments and xlsbVerticalAlignments defined the alignment
+// types of the cell formats by the alignment codes.
+var (
+	xlsbHorizontalAlignments = []string{"", "left", "center", "right", "fill", "justify", "centerContinuous", "distributed"}
+	xlsbVerticalAlignments   = []string{"top", "center", "", "justify", "distributed"}
+)
+
+// xlsbSheet directly maps the sheet of the binary workbook.
+type xlsbSheet struct {
+	name, relID string
+	state       uint32
+}
+
+// xlsbSheetPart directly maps the worksheet part of the binary workbook,
+// which will be converted to the worksheet XML part on first access.
+type xlsbSheetPart struct {
+	file     *zip.File
+	sheet    string
+	styles   []int
+	maxCells int
+}
+
+// xlsbReader directly maps the reader of the binary workbook.
+type xlsbReader struct {
+	f        *File
+	parts    map[string]*zip.File
+	sheets   []xlsbSheet
+	targets  map[string]string
+	types    map[string]string
+	styles   []int
+	maxCells int
+}
+
+// xlsbRecordReader directly maps the reader of the records in the part of
+// the binary workbook.
+type xlsbRecordReader struct {
+	r *bufio.Reader
+}
+
+// xlsbSheetReader directly maps the reader which converts the records of the
+// binary worksheet to the worksheet XML on the fly.
+type xlsbSheetReader struct {
+	rc                    io.ReadCloser
+	records               *xlsbRecordReader
+	buf                   bytes.Buffer
+	sheet                 string
+	styles                []int
+	row, sheetData        int
+	cells, maxCells       int
+	cols, rowOpen, merges bool
+	done                  bool
+}
+
+// isXLSBPackage provides a function to check if the zip package is a binary
+// workbook by the workbook part.
+func isXLSBPackage(zr *zip.Reader) bool {
+	for _, file := range zr.File {
+		if strings.EqualFold(file.Name, "xl/workbook.bin") {
+			return true
+		}
+	}
+	return false
+}
+
+// openXLSB provides a function to read the binary workbook (.xlsb) and
+// convert it to the spreadsheet. The cell values, cached values of the
+// formulas, number formats, fonts, fills, alignments, column widths, row
+// heights and merged cells will be converted. The worksheets will be
+// converted on first access, so the rows iterator could stream the binary
+// worksheet without loading it into memory. The number of cells in each
+// worksheet will be checked by the MaxCellsPerSheet of the given options
+// while converting.
+func openXLSB(zr *zip.Reader, opts Options) (*File, error) {
+	r := &xlsbReader{f: NewFile(), parts: make(map[string]*zip.File),
+		targets: make(map[string]string), types: make(map[string]string),
+		maxCells: opts.MaxCellsPerSheet,
+	}
+	for _, file := range zr.File {
+		r.parts[strings.ToLower(file.Name)] = file
+	}
+	if err := r.readRels(); err != nil {
+		return nil, err
+	}
+	if err := r.readPart("xl/workbook.bin", r.readWorkbook); err != nil {
+		return nil, err
+	}
+	if err := r.readPart(r.targets[SourceRelationshipSharedStrings], r.readSST); err != nil {
+		return nil, err
+	}
+	if err := r.readStyles(); err != nil {
+		return nil, err
+	}
+	return r.f, r.newSheets()
+}
+
+// readRels provides a function to read the relationships of the binary
+// workbook part.
+func (r *xlsbReader) readRels() error {
+	file, ok := r.parts["xl/_rels/workbook.bin.rels"]
+	if !ok {
+		return nil
+	}
+	content, err := readFile(file)
+	if err != nil {
+		return err
+	}
+	var rels xlsxRelationships
+	if err = r.f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
+		Decode(&rels); err != nil && err != io.EOF {
+		return err
+	}
+	for _, rel := range rels.Relationships {
+		target := path.Clean(path.Join("xl", rel.Target))
+		if strings.HasPrefix(rel.Target, "/") {
+			target = strings.TrimPrefix(rel.Target, "/")
+		}
+		r.targets[rel.ID], r.types[rel.ID] = target, rel.Type
+		if _, ok := r.targets[rel.Type]; !ok {
+			r.targets[rel.Type] = target
+		}
+	}
+	return nil
+}
+
+// readPart provides a function to read the records of the part by given part
+// name and record handler. The missing part will be ignored.
+func (r *xlsbReader) readPart(name string, fn func(id int, data []byte) error) error {
+	file, ok := r.parts[strings.ToLower(name)]
+	if name == "" || !ok {
+		return nil
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	records := newXLSBRecordReader(rc)
+	for {
+		id, data, err := records.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = fn(id, data); err != nil {
+			return err
+		}
+	}
+}
+
+// readWorkbook provides a function to read the workbook properties and the
+// sheets from the records of the workbook part.
+func (r *xlsbReader) readWorkbook(id int, data []byte) error {
+	switch id {
+	case xlsbRecordWbProp:
+		if binary.LittleEndian.Uint32(data)&1 != 0 {
+			wb := r.f.workbookReader()
+			if wb.WorkbookPr == nil {
+				wb.WorkbookPr = &xlsxWorkbookPr{}
+			}
+			wb.WorkbookPr.Date1904 = true
+		}
+	case xlsbRecordBundleSh:
+		relID, pos, err := getXLSBString(data, 8)
+		if err != nil {
+			return fmt.Errorf("invalid BIFF12 record %d", id)
+		}
+		name, _, err := getXLSBString(data, pos)
+		if err != nil {
+			return fmt.Errorf("invalid BIFF12 record %d", id)
+		}
+		r.sheets = append(r.sheets, xlsbSheet{name: name, relID: relID, state: binary.LittleEndian.Uint32(data)})
+	}
+	return nil
+}
+
+// readSST provides a function to read the shared string items from the
+// records of the shared strings part.
+func (r *xlsbReader) readSST(id int, data []byte) error {
+	if id != xlsbRecordSSTItem {
+		return nil
+	}
+	value, _, err := getXLSBString(data, 1)
+	if err != nil {
+		return fmt.Errorf("invalid BIFF12 record %d", id)
+	}
+	sst := r.f.sharedStringsReader()
+	t := xlsxT{Val: value}
+	if len(value) > 0 && (value[0] == 32 || value[len(value)-1] == 32) {
+		t.Space = xml.Attr{Name: xml.Name{Space: NameSpaceXML, Local: "space"}, Value: "preserve"}
+	}
+	sst.SI = append(sst.SI, xlsxSI{T: &t})
+	if _, ok := r.f.sharedStringsMap[value]; !ok {
+		r.f.sharedStringsMap[value] = len(sst.SI) - 1
+	}
+	sst.Count, sst.UniqueCount = len(sst.SI), len(sst.SI)
+	return nil
+}
+
+// readStyles provides a function to read the number formats, fonts, fills
+// and cell formats from the records of the styles part, and create the
+// styles of the cell formats.
+func (r *xlsbReader) readStyles() error {
+	var (
+		formats = make(map[int]string)
+		fonts   []*Font
+		fills   []Fill
+		xfs     [][]byte
+		cellXFs bool
+	)
+	if err := r.readPart(r.targets[SourceRelationshipStyles], func(id int, data []byte) error {
+		switch id {
+		case xlsbRecordFmt:
+			format, _, err := getXLSBString(data, 2)
+			if err != nil {
+				return fmt.Errorf("invalid BIFF12 record %d", id)
+			}
+			formats[int(binary.LittleEndian.Uint16(data))] = format
+		case xlsbRecordFont:
+			font, err := getXLSBFont(data)
+			if err != nil {
+				return fmt.Errorf("invalid BIFF12 record %d", id)
+			}
+			fonts = append(fonts, font)
+		case xlsbRecordFill:
+			fill := Fill{}
+			if pattern := int(binary.LittleEndian.Uint32(data)); pattern > 0 && pattern < 19 {
+				fill = Fill{Type: "pattern", Pattern: pattern}
+				if color := getXLSBColor(data[4:12]); color != "" {
+					fill.Color = []string{color}
+				}
+			}
+			fills = append(fills, fill)
+		case xlsbRecordBeginCellXFs:
+			cellXFs = true
+		case xlsbRecordEndCellXFs:
+			cellXFs = false
+		case xlsbRecordXF:
+			if cellXFs {
+				xfs = append(xfs, data)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	r.styles = make([]int, len(xfs))
+	for i, xf := range xfs {
+		style, styled := &Style{}, false
+		if numFmt := int(binary.LittleEndian.Uint16(xf[2:])); numFmt != 0 {
+			if format, ok := formats[numFmt]; ok {
+				style.CustomNumFmt = &format
+			} else {
+				style.NumFmt = numFmt
+			}
+			styled = true
+		}
+		if font := int(binary.LittleEndian.Uint16(xf[4:])); font > 0 && font < len(fonts) {
+			style.Font, styled = fonts[font], true
+		}
+		if fill := int(binary.LittleEndian.Uint16(xf[6:])); fill > 1 && fill < len(fills) && fills[fill].Type != "" {
+			style.Fill, styled = fills[fill], true
+		}
+		flags := binary.LittleEndian.Uint16(xf[12:])
+		alignment := &Alignment{
+			TextRotation: int(xf[10]), Indent: int(xf[11]), WrapText: flags&0x40 != 0, ShrinkToFit: flags&0x100 != 0,
+		}
+		if alc := int(flags & 7); alc < len(xlsbHorizontalAlignments) {
+			alignment.Horizontal = xlsbHorizontalAlignments[alc]
+		}
+		if alcv := int(flags >> 3 & 7); alcv < len(xlsbVerticalAlignments) {
+			alignment.Vertical = xlsbVerticalAlignments[alcv]
+		}
+		if *alignment != (Alignment{}) {
+			style.Alignment, styled = alignment, true
+		}
+		if !styled {
+			continue
+		}
+		styleID, err := r.f.NewStyle(style)
+		if err != nil {
+			return err
+		}
+		r.styles[i] = styleID
+	}
+	return nil
+}
+
+// newSheets provides a function to create the worksheets in the order of the
+// sheets, the worksheet parts will be converted on first access.
+func (r *xlsbReader) newSheets() error {
+	r.f.xlsbParts = make(map[string]*xlsbSheetPart)
+	var count int
+	for _, sheet := range r.sheets {
+		target := r.targets[sheet.relID]
+		if r.types[sheet.relID] != SourceRelationshipWorkSheet {
+			continue
+		}
+		file, ok := r.parts[strings.ToLower(target)]
+		if !ok {
+			return fmt.Errorf("sheet %s is not exist", sheet.name)
+		}
+		if name := trimSheetName(sheet.name); name == "" || name != sheet.name || count > 0 && r.f.GetSheetIndex(name) != -1 {
+			return fmt.Errorf("invalid sheet name %q", sheet.name)
+		}
+		if count++; count == 1 {
+			r.f.SetSheetName("Sheet1", sheet.name)
+		} else {
+			r.f.NewSheet(sheet.name)
+		}
+		name := r.f.sheetMap[sheet.name]
+		delete(r.f.Sheet, name)
+		delete(r.f.checked, name)
+		r.f.XLSX[name] = nil
+		r.f.xlsbParts[name] = &xlsbSheetPart{file: file, sheet: sheet.name, styles: r.styles, maxCells: r.maxCells}
+		wb := r.f.workbookReader()
+		for k, v := range wb.Sheets.Sheet {
+			if v.Name == sheet.name && sheet.state == 1 {
+				wb.Sheets.Sheet[k].State = "hidden"
+			}
+			if v.Name == sheet.name && sheet.state == 2 {
+				wb.Sheets.Sheet[k].State = "veryHidden"
+			}
+		}
+	}
+	if count == 0 {
+		return errors.New("no worksheet in binary workbook")
+	}
+	return nil
+}
+
+// open provides a function to get the reader of the worksheet XML which
+// converted from the binary worksheet part.
+func (p *xlsbSheetPart) open() (io.ReadCloser, error) {
+	rc, err := p.file.Open()
+	if err != nil {
+		return nil, err
+	}
+	sr := newXLSBSheetReader(rc, p.styles)
+	sr.sheet, sr.maxCells = p.sheet, p.maxCells
+	return sr, nil
+}
+
+// loadXLSBPart provides a function to convert the binary worksheet part to
+// the worksheet XML part by given part name.
+func (f *File) loadXLSBPart(name string, part *xlsbSheetPart) error {
+	rc, err := part.open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	content, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	delete(f.xlsbParts, name)
+	f.XLSX[name] = content
+	return nil
+}
+
+// newXLSBRecordReader provides a function to create the reader of the
+// records by given part reader.
+func newXLSBRecordReader(r io.Reader) *xlsbRecordReader {
+	return &xlsbRecordReader{r: bufio.NewReader(r)}
+}
+
+// next provides a function to read the type and data of the next record, it
+// returns io.EOF if there are no more records.
+func (rr *xlsbRecordReader) next() (int, []byte, error) {
+	id, err := rr.variable(2)
+	if err != nil {
+		return id, nil, err
+	}
+	size, err := rr.variable(4)
+	if err == nil {
+		data := make([]byte, size)
+		if _, err = io.ReadFull(rr.r, data); err == nil {
+			if min, ok := xlsbRecordSizes[id]; !ok || size >= min {
+				return id, data, nil
+			}
+		}
+	}
+	return id, nil, fmt.Errorf("invalid BIFF12 record %d", id)
+}
+
+// variable provides a function to read the variable length integer of the
+// record type or record size by given maximum bytes, the high bit of each
+// byte specifies if there is a following byte.
+func (rr *xlsbRecordReader) variable(n int) (int, error) {
+	var value int
+	for i := 0; i < n; i++ {
+		b, err := rr.r.ReadByte()
+		if err != nil {
+			if err == io.EOF && i > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return value, err
+		}
+		value |= int(b&0x7F) << (7 * uint(i))
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	return value, nil
+}
+
+// getXLSBString provides a function to read the wide string by given record
+// data and start position, returns the string and the position after the
+// string. The null string which length is 0xFFFFFFFF will be read as empty.
+func getXLSBString(data []byte, pos int) (string, int, error) {
+	if pos+4 > len(data) {
+		return "", pos, errors.New("invalid string")
+	}
+	cch := binary.LittleEndian.Uint32(data[pos:])
+	if pos += 4; cch == math.MaxUint32 {
+		return "", pos, nil
+	}
+	if uint64(cch)*2 > uint64(len(data)-pos) {
+		return "", pos, errors.New("invalid string")
+	}
+	chars := make([]uint16, cch)
+	for i := range chars {
+		chars[i] = binary.LittleEndian.Uint16(data[pos+i*2:])
+	}
+	return string(utf16.Decode(chars)), pos + int(cch)*2, nil
+}
+
+// getXLSBColor provides a function to get the RGB color in hex by given
+// color data, returns empty if the color isn't an RGB color.
+func getXLSBColor(data []byte) string {
+	if data[0]>>1 != 2 {
+		return ""
+	}
+	return fmt.Sprintf("#%02X%02X%02X", data[4], data[5], data[6])
+}
+
+// getXLSBFont provides a function to get the font by given Font record data.
+func getXLSBFont(data []byte) (*Font, error) {
+	family, _, err := getXLSBString(data, 21)
+	if err != nil {
+		return nil, err
+	}
+	grbit := binary.LittleEndian.Uint16(data[2:])
+	font := &Font{
+		Bold:   binary.LittleEndian.Uint16(data[4:]) >= 700,
+		Italic: grbit&2 != 0, Strike: grbit&8 != 0, Family: family,
+		Size:  float64(binary.LittleEndian.Uint16(data)) / 20,
+		Color: getXLSBColor(data[12:20]),
+	}
+	switch data[8] {
+	case 0x01:
+		font.Underline = "single"
+	case 0x02:
+		font.Underline = "double"
+	}
+	return font, nil
+}
+
+// newXLSBSheetReader provides a function to create the reader which converts
+// the binary worksheet to the worksheet XML by given part reader and styles
+// of the cell formats.
+func newXLSBSheetReader(rc io.ReadCloser, styles []int) *xlsbSheetReader {
+	sr := &xlsbSheetReader{rc: rc, records: newXLSBRecordReader(rc), styles: styles, row: -1}
+	sr.buf.WriteString(XMLHeader + `<worksheet xmlns="` + NameSpaceSpreadSheet.Value + `" xmlns:r="` + SourceRelationship.Value + `">`)
+	return sr
+}
+
+// Read implements the io.Reader interface to read the converted worksheet
+// XML.
+func (sr *xlsbSheetReader) Read(p []byte) (int, error) {
+	for sr.buf.Len() < len(p) && !sr.done {
+		id, data, err := sr.records.next()
+		if err == io.EOF {
+			sr.end()
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if err = sr.convert(id, data); err != nil {
+			return 0, err
+		}
+	}
+	if sr.buf.Len() == 0 && sr.done {
+		return 0, io.EOF
+	}
+	return sr.buf.Read(p)
+}
+
+// Close implements the io.Closer interface to close the binary worksheet
+// part.
+func (sr *xlsbSheetReader) Close() error {
+	return sr.rc.Close()
+}
+
+// convert provides a function to convert the record of the binary worksheet
+// to the worksheet XML by given record type and data.
+func (sr *xlsbSheetReader) convert(id int, data []byte) error {
+	switch id {
+	case xlsbRecordColInfo:
+		if !sr.cols {
+			sr.buf.WriteString(`<cols>`)
+			sr.cols = true
+		}
+		fmt.Fprintf(&sr.buf, `<col min="%d" max="%d" width="%s" customWidth="1"`,
+			binary.LittleEndian.Uint32(data)+1, binary.LittleEndian.Uint32(data[4:])+1,
+			strconv.FormatFloat(float64(binary.LittleEndian.Uint32(data[8:]))/256, 'f', -1, 64))
+		if style := sr.getStyle(binary.LittleEndian.Uint32(data[12:])); style != 0 {
+			fmt.Fprintf(&sr.buf, ` style="%d"`, style)
+		}
+		if binary.LittleEndian.Uint16(data[16:])&1 != 0 {
+			sr.buf.WriteString(` hidden="1"`)
+		}
+		sr.buf.WriteString(`/>`)
+	case xlsbRecordBeginSheetData:
+		sr.beginSheetData()
+	case xlsbRecordEndSheetData:
+		sr.endSheetData()
+	case xlsbRecordRowHdr:
+		row := int(binary.LittleEndian.Uint32(data))
+		if row >= TotalRows || sr.sheetData > 1 {
+			return fmt.Errorf("invalid BIFF12 record %d", id)
+		}
+		sr.beginSheetData()
+		if sr.rowOpen {
+			sr.buf.WriteString(`</row>`)
+		}
+		sr.row, sr.rowOpen = row, true
+		fmt.Fprintf(&sr.buf, `<row r="%d"`, row+1)
+		if data[11]&0x20 != 0 {
+			fmt.Fprintf(&sr.buf, ` ht="%s" customHeight="1"`,
+				strconv.FormatFloat(float64(binary.LittleEndian.Uint16(data[8:]))/20, 'f', -1, 64))
+		}
+		if data[11]&0x10 != 0 {
+			sr.buf.WriteString(` hidden="1"`)
+		}
+		sr.buf.WriteString(`>`)
+	case xlsbRecordCellBlank, xlsbRecordCellRk, xlsbRecordCellError, xlsbRecordCellBool,
+		xlsbRecordCellReal, xlsbRecordCellSt, xlsbRecordCellIsst, xlsbRecordFmlaString,
+		xlsbRecordFmlaNum, xlsbRecordFmlaBool, xlsbRecordFmlaError:
+		return sr.convertCell(id, data)
+	case xlsbRecordMergeCell:
+		sr.endSheetData()
+		if !sr.merges {
+			sr.buf.WriteString(`<mergeCells>`)
+			sr.merges = true
+		}
+		first, err := CoordinatesToCellName(int(binary.LittleEndian.Uint32(data[8:]))+1, int(binary.LittleEndian.Uint32(data))+1)
+		if err != nil {
+			return err
+		}
+		last, err := CoordinatesToCellName(int(binary.LittleEndian.Uint32(data[12:]))+1, int(binary.LittleEndian.Uint32(data[4:]))+1)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&sr.buf, `<mergeCell ref="%s:%s"/>`, first, last)
+	case xlsbRecordEndMergeCells:
+		sr.endMergeCells()
+	}
+	return nil
+}
+
+// convertCell provides a function to convert the cell record of the binary
+// worksheet to the cell element, the formula cells will be converted with
+// the cached values.
+func (sr *xlsbSheetReader) convertCell(id int, data []byte) error {
+	if !sr.rowOpen {
+		return fmt.Errorf("invalid BIFF12 record %d", id)
+	}
+	axis, err := CoordinatesToCellName(int(binary.LittleEndian.Uint32(data))+1, sr.row+1)
+	if err != nil {
+		return err
+	}
+	var typ, value string
+	switch id {
+	case xlsbRecordCellBlank:
+	case xlsbRecordCellRk:
+		value = strconv.FormatFloat(decodeXLSRK(binary.LittleEndian.Uint32(data[8:])), 'f', -1, 64)
+	case xlsbRecordCellError, xlsbRecordFmlaError:
+		typ, value = "e", xlsErrors[data[8]]
+	case xlsbRecordCellBool, xlsbRecordFmlaBool:
+		typ, value = "b", "0"
+		if data[8] != 0 {
+			value = "1"
+		}
+	case xlsbRecordCellReal, xlsbRecordFmlaNum:
+		value = strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(data[8:])), 'f', -1, 64)
+	case xlsbRecordCellIsst:
+		typ, value = "s", strconv.FormatUint(uint64(binary.LittleEndian.Uint32(data[8:])), 10)
+	case xlsbRecordCellSt, xlsbRecordFmlaString:
+		if value, _, err = getXLSBString(data, 8); err != nil {
+			return fmt.Errorf("invalid BIFF12 record %d", id)
+		}
+		typ = "str"
+		if id == xlsbRecordCellSt {
+			typ = "inlineStr"
+		}
+	}
+	style := sr.getStyle(uint32(data[4]) | uint32(data[5])<<8 | uint32(data[6])<<16)
+	if id == xlsbRecordCellBlank && style == 0 {
+		return nil
+	}
+	if sr.cells++; sr.maxCells > 0 && sr.cells > sr.maxCells {
+		return newMaxCellsError(sr.sheet, sr.maxCells)
+	}
+	fmt.Fprintf(&sr.buf, `<c r="%s"`, axis)
+	if style != 0 {
+		fmt.Fprintf(&sr.buf, ` s="%d"`, style)
+	}
+	if typ != "" {
+		fmt.Fprintf(&sr.buf, ` t="%s"`, typ)
+	}
+	if id == xlsbRecordCellBlank {
+		sr.buf.WriteString(`/>`)
+		return nil
+	}
+	sr.buf.WriteString(`>`)
+	if typ == "inlineStr" {
+		sr.buf.WriteString(`<is><t xml:space="preserve">`)
+		_ = xml.EscapeText(&sr.buf, []byte(value))
+		sr.buf.WriteString(`</t></is></c>`)
+		return nil
+	}
+	sr.buf.WriteString(`<v>`)
+	_ = xml.EscapeText(&sr.buf, []byte(value))
+	sr.buf.WriteString(`</v></c>`)
+	return nil
+}
+
+// getStyle provides a function to get the style ID by given index of the
+// cell format.
+func (sr *xlsbSheetReader) getStyle(xf uint32) int {
+	if int(xf) < len(sr.styles) {
+		return sr.styles[xf]
+	}
+	return 0
+}
+
+// beginSheetData provides a function to write the start element of the sheet
+// data if it has not been written.
+func (sr *xlsbSheetReader) beginSheetData() {
+	if sr.sheetData != 0 {
+		return
+	}
+	if sr.cols {
+		sr.buf.WriteString(`</cols>`)
+		sr.cols = false
+	}
+	sr.buf.WriteString(`<sheetData>`)
+	sr.sheetData = 1
+}
+
+// endSheetData provides a function to write the end element of the sheet
+// data, the empty sheet data will be written if it has not been started.
+func (sr *xlsbSheetReader) endSheetData() {
+	if sr.beginSheetData(); sr.sheetData != 1 {
+		return
+	}
+	if sr.rowOpen {
+		sr.buf.WriteString(`</row>`)
+		sr.rowOpen = false
+	}
+	sr.buf.WriteString(`</sheetData>`)
+	sr.sheetData = 2
+}
+
+// endMergeCells provides a function to write the end element of the merged
+// cells if it has been started.
+func (sr *xlsbSheetReader) endMergeCells() {
+	if sr.merges {
+		sr.buf.WriteString(`</mergeCells>`)
+		sr.merges = false
+	}
+}
+
+// end provides a function to close the elements which has been started at
+// the end of the binary worksheet.
+func (sr *xlsbSheetReader) end() {
+	sr.endSheetData()
+	sr.endMergeCells()
+	sr.buf.WriteString(`</worksheet>`)
+	sr.done = true
+}
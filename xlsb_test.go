@@ -0,0 +1,334 @@
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenXLSB(t *testing.T) {
+	f, err := OpenReader(bytes.NewReader(newXLSBPackage(t, newXLSBParts())))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Data", "Hidden"}, f.GetSheetList())
+	assert.False(t, f.GetSheetVisible("Hidden"))
+	assert.True(t, f.workbookReader().WorkbookPr.Date1904)
+
+	// Test open binary workbook with the default first sheet name.
+	parts := newXLSBParts()
+	parts["xl/workbook.bin"] = bytes.Replace(parts["xl/workbook.bin"], newXLSBBundleSh(0, "rId1", "Data"), newXLSBBundleSh(0, "rId1", "Sheet1"), 1)
+	def, err := OpenReader(bytes.NewReader(newXLSBPackage(t, parts)))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Hidden"}, def.GetSheetList())
+	cell, err := def.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", cell)
+
+	rows, err := f.GetRows("Data")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Hello", "inline", "12.5", "1.23"},
+		{"1", "#DIV/0!", "25.00%", "xy", "0", "#N/A", ""},
+		{"3"},
+	}, rows)
+	ws, err := f.workSheetReader("Data")
+	assert.NoError(t, err)
+	for axis, expected := range map[string]string{"A1": "s", "B1": "inlineStr", "A2": "b", "B2": "e", "D2": "str"} {
+		col, row, err := CellNameToCoordinates(axis)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ws.SheetData.Row[row-1].C[col-1].T, axis)
+	}
+	mergeCells, err := f.GetMergeCells("Data")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A8", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "B9", mergeCells[0].GetEndAxis())
+	width, err := f.GetColWidth("Data", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	visible, err := f.GetColVisible("Data", "D")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	height, err := f.GetRowHeight("Data", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	visible, err = f.GetRowVisible("Data", 3)
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	styleID, err := f.GetCellStyle("Data", "D1")
+	assert.NoError(t, err)
+	numFmtID := *f.stylesReader().CellXfs.Xf[styleID].NumFmtID
+	var formatCode string
+	for _, numFmt := range f.stylesReader().NumFmts.NumFmt {
+		if numFmt.NumFmtID == numFmtID {
+			formatCode = numFmt.FormatCode
+		}
+	}
+	assert.Equal(t, "0.000", formatCode)
+	styleID, err = f.GetCellStyle("Data", "G2")
+	assert.NoError(t, err)
+	xf := f.stylesReader().CellXfs.Xf[styleID]
+	font := f.stylesReader().Fonts.Font[*xf.FontID]
+	assert.NotNil(t, font.B)
+	assert.Equal(t, "Arial", *font.Name.Val)
+	assert.Equal(t, "FFFF0000", font.Color.RGB)
+	assert.Equal(t, "center", xf.Alignment.Horizontal)
+	assert.True(t, xf.Alignment.WrapText)
+	assert.Equal(t, "FFFFFF00", f.stylesReader().Fills.Fill[*xf.FillID].PatternFill.FgColor.RGB)
+	value, err := f.GetCellValue("Hidden", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "5", value)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestOpenXLSB.xlsx")))
+
+	f, err = OpenFile(filepath.Join("test", "TestOpenXLSB.xlsx"))
+	assert.NoError(t, err)
+	value, err = f.GetCellValue("Data", "C2")
+	assert.NoError(t, err)
+	assert.Equal(t, "25.00%", value)
+
+	// Test stream the binary worksheet by rows iterator.
+	b := newXLSBPackage(t, newXLSBParts())
+	f, err = OpenReaderAt(bytes.NewReader(b), int64(len(b)))
+	assert.NoError(t, err)
+	r, err := f.Rows("Data")
+	assert.NoError(t, err)
+	var cols [][]string
+	for r.Next() {
+		row, err := r.Columns()
+		assert.NoError(t, err)
+		cols = append(cols, row)
+	}
+	assert.NoError(t, r.Close())
+	assert.Equal(t, rows, cols)
+	assert.NotContains(t, f.Sheet, "xl/worksheets/sheet1.xml")
+	assert.Contains(t, f.xlsbParts, "xl/worksheets/sheet1.xml")
+
+	// Test open binary workbook with the limits, the worksheet will be checked
+	// while converting.
+	b = newXLSBPackage(t, newXLSBParts())
+	_, err = OpenReader(bytes.NewReader(b), Options{MaxCellsPerSheet: 2})
+	assert.EqualError(t, err, "the number of cells exceeds the limit 2: Data")
+	assert.True(t, errors.Is(err, ErrMaxCells))
+	f, err = OpenReaderAt(bytes.NewReader(b), int64(len(b)), Options{MaxCellsPerSheet: 2})
+	assert.NoError(t, err)
+	r, err = f.Rows("Data")
+	assert.NoError(t, err)
+	for r.Next() {
+		_, _ = r.Columns()
+	}
+	assert.True(t, errors.Is(r.Error(), ErrMaxCells))
+	assert.NoError(t, r.Close())
+
+	// Test open binary workbook with invalid records.
+	for _, c := range []struct {
+		parts    map[string][]byte
+		expected string
+	}{
+		{map[string][]byte{"xl/workbook.bin": newXLSBRecord(xlsbRecordBundleSh, append(make([]byte, 8), 100, 0, 0, 0, 0, 0, 0, 0))}, "invalid BIFF12 record 156"},
+		{map[string][]byte{"xl/workbook.bin": newXLSBRecord(xlsbRecordBundleSh, append(make([]byte, 12), 100, 0, 0, 0))}, "invalid BIFF12 record 156"},
+		{map[string][]byte{"xl/workbook.bin": {0x9C, 0x01, 0xFF}}, "invalid BIFF12 record 156"},
+		{map[string][]byte{"xl/workbook.bin": nil}, "no worksheet in binary workbook"},
+		{map[string][]byte{"xl/workbook.bin": newXLSBBundleSh(0, "rId1", "A:B")}, `invalid sheet name "A:B"`},
+		{map[string][]byte{"xl/workbook.bin": bytes.Join([][]byte{newXLSBBundleSh(0, "rId1", "A"), newXLSBBundleSh(0, "rId2", "A")}, nil)}, `invalid sheet name "A"`},
+		{map[string][]byte{"xl/worksheets/sheet2.bin": nil, "xl/_rels/workbook.bin.rels": []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet3.bin"/></Relationships>`)}, "sheet Hidden is not exist"},
+		{map[string][]byte{"xl/_rels/workbook.bin.rels": []byte(`<Relationships`)}, "XML syntax error on line 1: unexpected EOF"},
+		{map[string][]byte{"xl/sharedStrings.bin": newXLSBRecord(xlsbRecordSSTItem, []byte{0, 1, 0, 0, 0})}, "invalid BIFF12 record 19"},
+		{map[string][]byte{"xl/styles.bin": newXLSBRecord(xlsbRecordFmt, []byte{164, 0, 1, 0, 0, 0})}, "invalid BIFF12 record 44"},
+		{map[string][]byte{"xl/styles.bin": newXLSBRecord(xlsbRecordFont, append(make([]byte, 21), 1, 0, 0, 0))}, "invalid BIFF12 record 43"},
+	} {
+		parts := newXLSBParts()
+		for name, content := range c.parts {
+			parts[name] = content
+		}
+		_, err = OpenReader(bytes.NewReader(newXLSBPackage(t, parts)))
+		assert.EqualError(t, err, c.expected)
+	}
+
+	// Test read binary worksheet with invalid records.
+	for _, content := range [][]byte{
+		newXLSBCell(xlsbRecordCellReal, 0, 0, make([]byte, 8)),
+		append(newXLSBRecord(xlsbRecordRowHdr, make([]byte, 12)), newXLSBRecord(xlsbRecordCellSt, append(make([]byte, 8), 1, 0, 0, 0))...),
+		append(newXLSBRecord(xlsbRecordRowHdr, make([]byte, 12)), newXLSBCell(xlsbRecordCellReal, TotalColumns, 0, make([]byte, 8))...),
+		newXLSBRecord(xlsbRecordRowHdr, []byte{0, 0, 0x10, 0, 0, 0, 0, 0, 0, 0, 0, 0}),
+		append(newXLSBRecord(xlsbRecordMergeCell, make([]byte, 16)), newXLSBRecord(xlsbRecordRowHdr, make([]byte, 12))...),
+		newXLSBRecord(xlsbRecordMergeCell, []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10, 0, 0, 0, 0, 0}),
+		newXLSBRecord(xlsbRecordMergeCell, []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10, 0}),
+		newXLSBRecord(xlsbRecordColInfo, nil),
+	} {
+		parts := newXLSBParts()
+		parts["xl/worksheets/sheet2.bin"] = content
+		b := newXLSBPackage(t, parts)
+		f, err = OpenReaderAt(bytes.NewReader(b), int64(len(b)))
+		assert.NoError(t, err)
+		r, err := f.Rows("Hidden")
+		assert.NoError(t, err)
+		for r.Next() {
+			_, _ = r.Columns()
+		}
+		assert.Error(t, r.Error())
+		assert.NoError(t, r.Close())
+	}
+}
+
+func newXLSBParts() map[string][]byte {
+	fill := func(pattern uint32, color []byte) []byte {
+		data := make([]byte, 20)
+		binary.LittleEndian.PutUint32(data, pattern)
+		copy(data[4:], color)
+		return data
+	}
+	font := func(size, bold uint16, color []byte, name string) []byte {
+		data := make([]byte, 21)
+		binary.LittleEndian.PutUint16(data, size*20)
+		binary.LittleEndian.PutUint16(data[4:], bold)
+		copy(data[12:], color)
+		return append(data, newXLSBString(name)...)
+	}
+	xf := func(numFmt, font, fill, flags uint16) []byte {
+		data := make([]byte, 16)
+		binary.LittleEndian.PutUint16(data[2:], numFmt)
+		binary.LittleEndian.PutUint16(data[4:], font)
+		binary.LittleEndian.PutUint16(data[6:], fill)
+		binary.LittleEndian.PutUint16(data[12:], flags)
+		return data
+	}
+	real := func(value float64) []byte {
+		data := make([]byte, 8)
+		binary.LittleEndian.PutUint64(data, math.Float64bits(value))
+		return data
+	}
+	uint32s := func(values ...uint32) []byte {
+		data := make([]byte, len(values)*4)
+		for i, value := range values {
+			binary.LittleEndian.PutUint32(data[i*4:], value)
+		}
+		return data
+	}
+	rowHdr := func(row uint32, height uint16, flags byte) []byte {
+		data := make([]byte, 17)
+		binary.LittleEndian.PutUint32(data, row)
+		binary.LittleEndian.PutUint16(data[8:], height*20)
+		data[11] = flags
+		return newXLSBRecord(xlsbRecordRowHdr, data)
+	}
+	parts := map[string][]byte{
+		"xl/_rels/workbook.bin.rels": []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.bin"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.bin"/>` +
+			`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.bin"/>` +
+			`<Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.bin"/>` +
+			`</Relationships>`),
+		"xl/sharedStrings.bin": bytes.Join([][]byte{
+			newXLSBRecord(xlsbRecordSSTItem, append([]byte{0}, newXLSBString("Hello")...)),
+			newXLSBRecord(xlsbRecordSSTItem, append([]byte{0}, newXLSBString(" spaced ")...)),
+		}, nil),
+		"xl/styles.bin": bytes.Join([][]byte{
+			newXLSBRecord(xlsbRecordFmt, append([]byte{164, 0}, newXLSBString("0.000")...)),
+			newXLSBRecord(xlsbRecordFont, font(11, 400, nil, "Calibri")),
+			newXLSBRecord(xlsbRecordFont, font(14, 700, []byte{5, 0, 0, 0, 0xFF, 0, 0, 0xFF}, "Arial")),
+			newXLSBRecord(xlsbRecordFill, fill(0, nil)),
+			newXLSBRecord(xlsbRecordFill, fill(17, nil)),
+			newXLSBRecord(xlsbRecordFill, fill(1, []byte{5, 0, 0, 0, 0xFF, 0xFF, 0, 0xFF})),
+			newXLSBRecord(xlsbRecordXF, xf(14, 1, 2, 0)),
+			newXLSBRecord(xlsbRecordBeginCellXFs, nil),
+			newXLSBRecord(xlsbRecordXF, xf(0, 0, 0, 0x10)),
+			newXLSBRecord(xlsbRecordXF, xf(10, 0, 0, 0x10)),
+			newXLSBRecord(xlsbRecordXF, xf(164, 0, 0, 0x10)),
+			newXLSBRecord(xlsbRecordXF, xf(0, 1, 2, 0x52)),
+			newXLSBRecord(xlsbRecordEndCellXFs, nil),
+		}, nil),
+		"xl/workbook.bin": bytes.Join([][]byte{
+			newXLSBRecord(xlsbRecordWbProp, append([]byte{1, 0, 0, 0}, make([]byte, 68)...)),
+			newXLSBBundleSh(0, "rId1", "Data"),
+			newXLSBBundleSh(1, "rId2", "Hidden"),
+			newXLSBBundleSh(0, "rId3", "Styles"),
+		}, nil),
+		"xl/worksheets/sheet1.bin": bytes.Join([][]byte{
+			newXLSBRecord(xlsbRecordColInfo, append(uint32s(2, 2, 20*256, 0), 0, 0)),
+			newXLSBRecord(xlsbRecordColInfo, append(uint32s(3, 3, 10*256, 0), 1, 0)),
+			newXLSBRecord(xlsbRecordBeginSheetData, nil),
+			rowHdr(0, 0, 0),
+			newXLSBCell(xlsbRecordCellIsst, 0, 0, uint32s(0)),
+			newXLSBCell(xlsbRecordCellSt, 1, 0, newXLSBString("inline")),
+			newXLSBCell(xlsbRecordCellRk, 2, 0, uint32s(1250<<2|3)),
+			newXLSBCell(xlsbRecordCellReal, 3, 2, real(1.23)),
+			rowHdr(1, 30, 0x20),
+			newXLSBCell(xlsbRecordCellBool, 0, 0, []byte{1}),
+			newXLSBCell(xlsbRecordCellError, 1, 0, []byte{0x07}),
+			newXLSBCell(xlsbRecordFmlaNum, 2, 1, append(real(0.25), 0, 0)),
+			newXLSBCell(xlsbRecordFmlaString, 3, 0, append(newXLSBString("xy"), 0, 0)),
+			newXLSBCell(xlsbRecordFmlaBool, 4, 0, []byte{0, 0, 0}),
+			newXLSBCell(xlsbRecordFmlaError, 5, 0, []byte{0x2A, 0, 0}),
+			newXLSBCell(xlsbRecordCellBlank, 6, 3, nil),
+			newXLSBCell(xlsbRecordCellBlank, 7, 0, nil),
+			rowHdr(2, 0, 0x10),
+			newXLSBCell(xlsbRecordCellRk, 0, 0, uint32s(3<<2|2)),
+			newXLSBRecord(xlsbRecordEndSheetData, nil),
+			newXLSBRecord(177, uint32s(1)),
+			newXLSBRecord(xlsbRecordMergeCell, uint32s(7, 8, 0, 1)),
+			newXLSBRecord(xlsbRecordEndMergeCells, nil),
+		}, nil),
+		"xl/worksheets/sheet2.bin": bytes.Join([][]byte{
+			rowHdr(0, 0, 0),
+			newXLSBCell(xlsbRecordCellReal, 0, 0, real(5)),
+		}, nil),
+	}
+	return parts
+}
+
+func newXLSBPackage(t *testing.T, parts map[string][]byte) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func newXLSBRecord(id int, data []byte) []byte {
+	var b []byte
+	for i, value := range []int{id, len(data)} {
+		for n := 0; n < 2+i*2; n++ {
+			if value < 0x80 {
+				b = append(b, byte(value))
+				break
+			}
+			b, value = append(b, byte(value&0x7F|0x80)), value>>7
+		}
+	}
+	return append(b, data...)
+}
+
+func newXLSBString(s string) []byte {
+	chars := utf16.Encode([]rune(s))
+	b := make([]byte, 4+len(chars)*2)
+	binary.LittleEndian.PutUint32(b, uint32(len(chars)))
+	for i, c := range chars {
+		binary.LittleEndian.PutUint16(b[4+i*2:], c)
+	}
+	return b
+}
+
+func newXLSBCell(id int, col, style uint32, value []byte) []byte {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint32(data, col)
+	binary.LittleEndian.PutUint32(data[4:], style)
+	return newXLSBRecord(id, append(data, value...))
+}
+
+func newXLSBBundleSh(state uint32, relID, name string) []byte {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint32(data, state)
+	data = append(data, newXLSBString(relID)...)
+	return newXLSBRecord(xlsbRecordBundleSh, append(data, newXLSBString(name)...))
+}
@@ -46,6 +46,7 @@ const (
 	SourceRelationshipPivotTable                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotTable"
 	SourceRelationshipPivotCache                 = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheDefinition"
 	SourceRelationshipSharedStrings              = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings"
+	SourceRelationshipStyles                     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
 	SourceRelationshipVBAProject                 = "http://schemas.microsoft.com/office/2006/relationships/vbaProject"
 	NameSpaceXML                                 = "http://www.w3.org/XML/1998/namespace"
 	NameSpaceXMLSchemaInstance                   = "http://www.w3.org/2001/XMLSchema-instance"
@@ -54,12 +55,24 @@ const (
 	StrictSourceRelationshipComments             = "http://purl.oclc.org/ooxml/officeDocument/relationships/comments"
 	StrictSourceRelationshipImage                = "http://purl.oclc.org/ooxml/officeDocument/relationships/image"
 	StrictNameSpaceSpreadSheet                   = "http://purl.oclc.org/ooxml/spreadsheetml/main"
+	StrictNameSpaceExtendedProperties            = "http://purl.oclc.org/ooxml/officeDocument/extendedProperties"
+	StrictNameSpaceCustomProperties              = "http://purl.oclc.org/ooxml/officeDocument/customProperties"
+	StrictSourceRelationshipExtendProperties     = "http://purl.oclc.org/ooxml/officeDocument/relationships/extendedProperties"
+	StrictSourceRelationshipCustomProperties     = "http://purl.oclc.org/ooxml/officeDocument/relationships/customProperties"
+	NameSpaceExtendedProperties                  = "http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"
+	NameSpaceCustomProperties                    = "http://schemas.openxmlformats.org/officeDocument/2006/custom-properties"
+	SourceRelationshipExtendProperties           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties"
+	SourceRelationshipCustomProperties           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
 	NameSpaceDublinCore                          = "http://purl.org/dc/elements/1.1/"
 	NameSpaceDublinCoreTerms                     = "http://purl.org/dc/terms/"
 	NameSpaceDublinCoreMetadataIntiative         = "http://purl.org/dc/dcmitype/"
 	ContentTypeDrawing                           = "application/vnd.openxmlformats-officedocument.drawing+xml"
 	ContentTypeDrawingML                         = "application/vnd.openxmlformats-officedocument.drawingml.chart+xml"
 	ContentTypeMacro                             = "application/vnd.ms-excel.sheet.macroEnabled.main+xml"
+	ContentTypeMacroAddIn                        = "application/vnd.ms-excel.addin.macroEnabled.main+xml"
+	ContentTypeMacroTemplate                     = "application/vnd.ms-excel.template.macroEnabled.main+xml"
+	ContentTypeSheetML                           = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"
+	ContentTypeTemplate                          = "application/vnd.openxmlformats-officedocument.spreadsheetml.template.main+xml"
 	ContentTypeSpreadSheetMLChartsheet           = "application/vnd.openxmlformats-officedocument.spreadsheetml.chartsheet+xml"
 	ContentTypeSpreadSheetMLComments             = "application/vnd.openxmlformats-officedocument.spreadsheetml.comments+xml"
 	ContentTypeSpreadSheetMLPivotCacheDefinition = "application/vnd.openxmlformats-officedocument.spreadsheetml.pivotCacheDefinition+xml"
@@ -0,0 +1,72 @@
+// Copyright 2016 - 2020 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to
+// and read from XLSX / XLSM / XLTM files. Supports reading and writing
+// spreadsheet documents generated by Microsoft Exce™ 2007 and later. Supports
+// complex components by high compatibility, and provided streaming API for
+// generating or reading data from a worksheet with huge amounts of data. This
+// library needs Go version 1.10 or later.
+
+package excelize
+
+import "encoding/xml"
+
+// xlsxExternalLink directly maps the externalLink element. This element
+// represents the root of an external workbook references part, which
+// contains the cached data of the cells referenced in other workbooks.
+type xlsxExternalLink struct {
+	XMLName      xml.Name          `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main externalLink"`
+	ExternalBook *xlsxExternalBook `xml:"externalBook"`
+}
+
+// xlsxExternalBook directly maps the externalBook element. This element
+// defines an external workbook, the relationship of this element specifies
+// the location of the external workbook.
+type xlsxExternalBook struct {
+	RID          string                    `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr,omitempty"`
+	SheetNames   *xlsxExternalSheetNames   `xml:"sheetNames"`
+	SheetDataSet *xlsxExternalSheetDataSet `xml:"sheetDataSet"`
+}
+
+// xlsxExternalSheetNames directly maps the sheetNames element. This element
+// represents the list of worksheet names in the external workbook.
+type xlsxExternalSheetNames struct {
+	SheetName []xlsxExternalSheetName `xml:"sheetName"`
+}
+
+// xlsxExternalSheetName directly maps the sheetName element.
+type xlsxExternalSheetName struct {
+	Val string `xml:"val,attr,omitempty"`
+}
+
+// xlsxExternalSheetDataSet directly maps the sheetDataSet element. This
+// element represents the cached worksheet data of the external workbook.
+type xlsxExternalSheetDataSet struct {
+	SheetData []xlsxExternalSheetData `xml:"sheetData"`
+}
+
+// xlsxExternalSheetData directly maps the sheetData element of the external
+// workbook references part. The sheetId attribute is the zero-based index of
+// the worksheet in the sheetNames element.
+type xlsxExternalSheetData struct {
+	SheetID      int               `xml:"sheetId,attr"`
+	RefreshError bool              `xml:"refreshError,attr,omitempty"`
+	Row          []xlsxExternalRow `xml:"row"`
+}
+
+// xlsxExternalRow directly maps the row element of the cached worksheet data.
+type xlsxExternalRow struct {
+	R    int                `xml:"r,attr"`
+	Cell []xlsxExternalCell `xml:"cell"`
+}
+
+// xlsxExternalCell directly maps the cell element of the cached worksheet
+// data.
+type xlsxExternalCell struct {
+	R  string `xml:"r,attr,omitempty"`
+	T  string `xml:"t,attr,omitempty"`
+	VM int    `xml:"vm,attr,omitempty"`
+	V  string `xml:"v,omitempty"`
+}
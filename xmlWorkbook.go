@@ -44,6 +44,7 @@ type xlsxWorkbook struct {
 	PivotCaches         *xlsxPivotCaches         `xml:"pivotCaches"`
 	ExtLst              *xlsxExtLst              `xml:"extLst"`
 	FileRecoveryPr      *xlsxFileRecoveryPr      `xml:"fileRecoveryPr"`
+	AnyElements         []xlsxAnyElement         `xml:",any"`
 }
 
 // xlsxFileRecoveryPr maps sheet recovery information. This element defines
@@ -84,35 +85,37 @@ type xlsxWorkbookProtection struct {
 // properties that track which version of the application accessed the data and
 // source code contained in the file.
 type xlsxFileVersion struct {
-	AppName      string `xml:"appName,attr,omitempty"`
-	CodeName     string `xml:"codeName,attr,omitempty"`
-	LastEdited   string `xml:"lastEdited,attr,omitempty"`
-	LowestEdited string `xml:"lowestEdited,attr,omitempty"`
-	RupBuild     string `xml:"rupBuild,attr,omitempty"`
+	AppName      string       `xml:"appName,attr,omitempty"`
+	CodeName     string       `xml:"codeName,attr,omitempty"`
+	LastEdited   string       `xml:"lastEdited,attr,omitempty"`
+	LowestEdited string       `xml:"lowestEdited,attr,omitempty"`
+	RupBuild     string       `xml:"rupBuild,attr,omitempty"`
+	AnyAttrs     xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxWorkbookPr directly maps the workbookPr element from the namespace
 // http://schemas.openxmlformats.org/spreadsheetml/2006/main This element
 // defines a collection of workbook properties.
 type xlsxWorkbookPr struct {
-	AllowRefreshQuery          bool   `xml:"allowRefreshQuery,attr,omitempty"`
-	AutoCompressPictures       bool   `xml:"autoCompressPictures,attr,omitempty"`
-	BackupFile                 bool   `xml:"backupFile,attr,omitempty"`
-	CheckCompatibility         bool   `xml:"checkCompatibility,attr,omitempty"`
-	CodeName                   string `xml:"codeName,attr,omitempty"`
-	Date1904                   bool   `xml:"date1904,attr,omitempty"`
-	DefaultThemeVersion        string `xml:"defaultThemeVersion,attr,omitempty"`
-	FilterPrivacy              bool   `xml:"filterPrivacy,attr,omitempty"`
-	HidePivotFieldList         bool   `xml:"hidePivotFieldList,attr,omitempty"`
-	PromptedSolutions          bool   `xml:"promptedSolutions,attr,omitempty"`
-	PublishItems               bool   `xml:"publishItems,attr,omitempty"`
-	RefreshAllConnections      bool   `xml:"refreshAllConnections,attr,omitempty"`
-	SaveExternalLinkValues     bool   `xml:"saveExternalLinkValues,attr,omitempty"`
-	ShowBorderUnselectedTables bool   `xml:"showBorderUnselectedTables,attr,omitempty"`
-	ShowInkAnnotation          bool   `xml:"showInkAnnotation,attr,omitempty"`
-	ShowObjects                string `xml:"showObjects,attr,omitempty"`
-	ShowPivotChartFilter       bool   `xml:"showPivotChartFilter,attr,omitempty"`
-	UpdateLinks                string `xml:"updateLinks,attr,omitempty"`
+	AllowRefreshQuery          bool         `xml:"allowRefreshQuery,attr,omitempty"`
+	AutoCompressPictures       bool         `xml:"autoCompressPictures,attr,omitempty"`
+	BackupFile                 bool         `xml:"backupFile,attr,omitempty"`
+	CheckCompatibility         bool         `xml:"checkCompatibility,attr,omitempty"`
+	CodeName                   string       `xml:"codeName,attr,omitempty"`
+	Date1904                   bool         `xml:"date1904,attr,omitempty"`
+	DefaultThemeVersion        string       `xml:"defaultThemeVersion,attr,omitempty"`
+	FilterPrivacy              bool         `xml:"filterPrivacy,attr,omitempty"`
+	HidePivotFieldList         bool         `xml:"hidePivotFieldList,attr,omitempty"`
+	PromptedSolutions          bool         `xml:"promptedSolutions,attr,omitempty"`
+	PublishItems               bool         `xml:"publishItems,attr,omitempty"`
+	RefreshAllConnections      bool         `xml:"refreshAllConnections,attr,omitempty"`
+	SaveExternalLinkValues     bool         `xml:"saveExternalLinkValues,attr,omitempty"`
+	ShowBorderUnselectedTables bool         `xml:"showBorderUnselectedTables,attr,omitempty"`
+	ShowInkAnnotation          bool         `xml:"showInkAnnotation,attr,omitempty"`
+	ShowObjects                string       `xml:"showObjects,attr,omitempty"`
+	ShowPivotChartFilter       bool         `xml:"showPivotChartFilter,attr,omitempty"`
+	UpdateLinks                string       `xml:"updateLinks,attr,omitempty"`
+	AnyAttrs                   xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxBookViews directly maps the bookViews element. This element specifies the
@@ -127,19 +130,20 @@ type xlsxBookViews struct {
 // http://schemas.openxmlformats.org/spreadsheetml/2006/main This element
 // specifies a single Workbook view.
 type xlsxWorkBookView struct {
-	ActiveTab              int    `xml:"activeTab,attr,omitempty"`
-	AutoFilterDateGrouping bool   `xml:"autoFilterDateGrouping,attr,omitempty"`
-	FirstSheet             int    `xml:"firstSheet,attr,omitempty"`
-	Minimized              bool   `xml:"minimized,attr,omitempty"`
-	ShowHorizontalScroll   bool   `xml:"showHorizontalScroll,attr,omitempty"`
-	ShowSheetTabs          bool   `xml:"showSheetTabs,attr,omitempty"`
-	ShowVerticalScroll     bool   `xml:"showVerticalScroll,attr,omitempty"`
-	TabRatio               int    `xml:"tabRatio,attr,omitempty"`
-	Visibility             string `xml:"visibility,attr,omitempty"`
-	WindowHeight           int    `xml:"windowHeight,attr,omitempty"`
-	WindowWidth            int    `xml:"windowWidth,attr,omitempty"`
-	XWindow                string `xml:"xWindow,attr,omitempty"`
-	YWindow                string `xml:"yWindow,attr,omitempty"`
+	ActiveTab              int          `xml:"activeTab,attr,omitempty"`
+	AutoFilterDateGrouping bool         `xml:"autoFilterDateGrouping,attr,omitempty"`
+	FirstSheet             int          `xml:"firstSheet,attr,omitempty"`
+	Minimized              bool         `xml:"minimized,attr,omitempty"`
+	ShowHorizontalScroll   bool         `xml:"showHorizontalScroll,attr,omitempty"`
+	ShowSheetTabs          bool         `xml:"showSheetTabs,attr,omitempty"`
+	ShowVerticalScroll     bool         `xml:"showVerticalScroll,attr,omitempty"`
+	TabRatio               int          `xml:"tabRatio,attr,omitempty"`
+	Visibility             string       `xml:"visibility,attr,omitempty"`
+	WindowHeight           int          `xml:"windowHeight,attr,omitempty"`
+	WindowWidth            int          `xml:"windowWidth,attr,omitempty"`
+	XWindow                string       `xml:"xWindow,attr,omitempty"`
+	YWindow                string       `xml:"yWindow,attr,omitempty"`
+	AnyAttrs               xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxSheets directly maps the sheets element from the namespace
@@ -151,10 +155,11 @@ type xlsxSheets struct {
 // xlsxSheet defines a sheet in this workbook. Sheet data is stored in a
 // separate part.
 type xlsxSheet struct {
-	Name    string `xml:"name,attr,omitempty"`
-	SheetID int    `xml:"sheetId,attr,omitempty"`
-	ID      string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
-	State   string `xml:"state,attr,omitempty"`
+	Name     string       `xml:"name,attr,omitempty"`
+	SheetID  int          `xml:"sheetId,attr,omitempty"`
+	ID       string       `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	State    string       `xml:"state,attr,omitempty"`
+	AnyAttrs xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxExternalReferences directly maps the externalReferences element of the
@@ -207,22 +212,23 @@ type xlsxDefinedNames struct {
 // text that is used to represents a cell, range of cells, formula, or constant
 // value. For a descriptions of the attributes see https://docs.microsoft.com/en-us/dotnet/api/documentformat.openxml.spreadsheet.definedname
 type xlsxDefinedName struct {
-	Comment           string `xml:"comment,attr,omitempty"`
-	CustomMenu        string `xml:"customMenu,attr,omitempty"`
-	Description       string `xml:"description,attr,omitempty"`
-	Function          bool   `xml:"function,attr,omitempty"`
-	FunctionGroupID   int    `xml:"functionGroupId,attr,omitempty"`
-	Help              string `xml:"help,attr,omitempty"`
-	Hidden            bool   `xml:"hidden,attr,omitempty"`
-	LocalSheetID      *int   `xml:"localSheetId,attr"`
-	Name              string `xml:"name,attr,omitempty"`
-	PublishToServer   bool   `xml:"publishToServer,attr,omitempty"`
-	ShortcutKey       string `xml:"shortcutKey,attr,omitempty"`
-	StatusBar         string `xml:"statusBar,attr,omitempty"`
-	VbProcedure       bool   `xml:"vbProcedure,attr,omitempty"`
-	WorkbookParameter bool   `xml:"workbookParameter,attr,omitempty"`
-	Xlm               bool   `xml:"xml,attr,omitempty"`
-	Data              string `xml:",chardata"`
+	Comment           string       `xml:"comment,attr,omitempty"`
+	CustomMenu        string       `xml:"customMenu,attr,omitempty"`
+	Description       string       `xml:"description,attr,omitempty"`
+	Function          bool         `xml:"function,attr,omitempty"`
+	FunctionGroupID   int          `xml:"functionGroupId,attr,omitempty"`
+	Help              string       `xml:"help,attr,omitempty"`
+	Hidden            bool         `xml:"hidden,attr,omitempty"`
+	LocalSheetID      *int         `xml:"localSheetId,attr"`
+	Name              string       `xml:"name,attr,omitempty"`
+	PublishToServer   bool         `xml:"publishToServer,attr,omitempty"`
+	ShortcutKey       string       `xml:"shortcutKey,attr,omitempty"`
+	StatusBar         string       `xml:"statusBar,attr,omitempty"`
+	VbProcedure       bool         `xml:"vbProcedure,attr,omitempty"`
+	WorkbookParameter bool         `xml:"workbookParameter,attr,omitempty"`
+	Xlm               bool         `xml:"xml,attr,omitempty"`
+	Data              string       `xml:",chardata"`
+	AnyAttrs          xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxCalcPr directly maps the calcPr element. This element defines the
@@ -230,19 +236,20 @@ type xlsxDefinedName struct {
 // and details. Calculation is the process of computing formulas and then
 // displaying the results as values in the cells that contain the formulas.
 type xlsxCalcPr struct {
-	CalcCompleted         bool    `xml:"calcCompleted,attr,omitempty"`
-	CalcID                string  `xml:"calcId,attr,omitempty"`
-	CalcMode              string  `xml:"calcMode,attr,omitempty"`
-	CalcOnSave            bool    `xml:"calcOnSave,attr,omitempty"`
-	ConcurrentCalc        *bool   `xml:"concurrentCalc,attr"`
-	ConcurrentManualCount int     `xml:"concurrentManualCount,attr,omitempty"`
-	ForceFullCalc         bool    `xml:"forceFullCalc,attr,omitempty"`
-	FullCalcOnLoad        bool    `xml:"fullCalcOnLoad,attr,omitempty"`
-	FullPrecision         bool    `xml:"fullPrecision,attr,omitempty"`
-	Iterate               bool    `xml:"iterate,attr,omitempty"`
-	IterateCount          int     `xml:"iterateCount,attr,omitempty"`
-	IterateDelta          float64 `xml:"iterateDelta,attr,omitempty"`
-	RefMode               string  `xml:"refMode,attr,omitempty"`
+	CalcCompleted         bool         `xml:"calcCompleted,attr,omitempty"`
+	CalcID                string       `xml:"calcId,attr,omitempty"`
+	CalcMode              string       `xml:"calcMode,attr,omitempty"`
+	CalcOnSave            bool         `xml:"calcOnSave,attr,omitempty"`
+	ConcurrentCalc        *bool        `xml:"concurrentCalc,attr"`
+	ConcurrentManualCount int          `xml:"concurrentManualCount,attr,omitempty"`
+	ForceFullCalc         bool         `xml:"forceFullCalc,attr,omitempty"`
+	FullCalcOnLoad        bool         `xml:"fullCalcOnLoad,attr,omitempty"`
+	FullPrecision         bool         `xml:"fullPrecision,attr,omitempty"`
+	Iterate               bool         `xml:"iterate,attr,omitempty"`
+	IterateCount          int          `xml:"iterateCount,attr,omitempty"`
+	IterateDelta          float64      `xml:"iterateDelta,attr,omitempty"`
+	RefMode               string       `xml:"refMode,attr,omitempty"`
+	AnyAttrs              xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxCustomWorkbookViews defines the collection of custom workbook views that
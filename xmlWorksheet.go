@@ -60,6 +60,7 @@ type xlsxWorksheet struct {
 	WebPublishItems       *xlsxInnerXML                `xml:"webPublishItems"`
 	TableParts            *xlsxTableParts              `xml:"tableParts"`
 	ExtLst                *xlsxExtLst                  `xml:"extLst"`
+	AnyElements           []xlsxAnyElement             `xml:",any"`
 }
 
 // xlsxDrawing change r:id to rid in the namespace.
@@ -105,26 +106,27 @@ type xlsxDrawingHF struct {
 // http://schemas.openxmlformats.org/spreadsheetml/2006/main - Page setup
 // settings for the worksheet.
 type xlsxPageSetUp struct {
-	XMLName            xml.Name `xml:"pageSetup"`
-	BlackAndWhite      bool     `xml:"blackAndWhite,attr,omitempty"`
-	CellComments       string   `xml:"cellComments,attr,omitempty"`
-	Copies             int      `xml:"copies,attr,omitempty"`
-	Draft              bool     `xml:"draft,attr,omitempty"`
-	Errors             string   `xml:"errors,attr,omitempty"`
-	FirstPageNumber    int      `xml:"firstPageNumber,attr,omitempty"`
-	FitToHeight        int      `xml:"fitToHeight,attr,omitempty"`
-	FitToWidth         int      `xml:"fitToWidth,attr,omitempty"`
-	HorizontalDPI      int      `xml:"horizontalDpi,attr,omitempty"`
-	RID                string   `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr,omitempty"`
-	Orientation        string   `xml:"orientation,attr,omitempty"`
-	PageOrder          string   `xml:"pageOrder,attr,omitempty"`
-	PaperHeight        string   `xml:"paperHeight,attr,omitempty"`
-	PaperSize          int      `xml:"paperSize,attr,omitempty"`
-	PaperWidth         string   `xml:"paperWidth,attr,omitempty"`
-	Scale              int      `xml:"scale,attr,omitempty"`
-	UseFirstPageNumber bool     `xml:"useFirstPageNumber,attr,omitempty"`
-	UsePrinterDefaults bool     `xml:"usePrinterDefaults,attr,omitempty"`
-	VerticalDPI        int      `xml:"verticalDpi,attr,omitempty"`
+	XMLName            xml.Name     `xml:"pageSetup"`
+	BlackAndWhite      bool         `xml:"blackAndWhite,attr,omitempty"`
+	CellComments       string       `xml:"cellComments,attr,omitempty"`
+	Copies             int          `xml:"copies,attr,omitempty"`
+	Draft              bool         `xml:"draft,attr,omitempty"`
+	Errors             string       `xml:"errors,attr,omitempty"`
+	FirstPageNumber    int          `xml:"firstPageNumber,attr,omitempty"`
+	FitToHeight        int          `xml:"fitToHeight,attr,omitempty"`
+	FitToWidth         int          `xml:"fitToWidth,attr,omitempty"`
+	HorizontalDPI      int          `xml:"horizontalDpi,attr,omitempty"`
+	RID                string       `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr,omitempty"`
+	Orientation        string       `xml:"orientation,attr,omitempty"`
+	PageOrder          string       `xml:"pageOrder,attr,omitempty"`
+	PaperHeight        string       `xml:"paperHeight,attr,omitempty"`
+	PaperSize          int          `xml:"paperSize,attr,omitempty"`
+	PaperWidth         string       `xml:"paperWidth,attr,omitempty"`
+	Scale              int          `xml:"scale,attr,omitempty"`
+	UseFirstPageNumber bool         `xml:"useFirstPageNumber,attr,omitempty"`
+	UsePrinterDefaults bool         `xml:"usePrinterDefaults,attr,omitempty"`
+	VerticalDPI        int          `xml:"verticalDpi,attr,omitempty"`
+	AnyAttrs           xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxPrintOptions directly maps the printOptions element in the namespace
@@ -157,16 +159,17 @@ type xlsxPageMargins struct {
 // http://schemas.openxmlformats.org/spreadsheetml/2006/main. This element
 // specifies the sheet formatting properties.
 type xlsxSheetFormatPr struct {
-	XMLName          xml.Name `xml:"sheetFormatPr"`
-	BaseColWidth     uint8    `xml:"baseColWidth,attr,omitempty"`
-	DefaultColWidth  float64  `xml:"defaultColWidth,attr,omitempty"`
-	DefaultRowHeight float64  `xml:"defaultRowHeight,attr"`
-	CustomHeight     bool     `xml:"customHeight,attr,omitempty"`
-	ZeroHeight       bool     `xml:"zeroHeight,attr,omitempty"`
-	ThickTop         bool     `xml:"thickTop,attr,omitempty"`
-	ThickBottom      bool     `xml:"thickBottom,attr,omitempty"`
-	OutlineLevelRow  uint8    `xml:"outlineLevelRow,attr,omitempty"`
-	OutlineLevelCol  uint8    `xml:"outlineLevelCol,attr,omitempty"`
+	XMLName          xml.Name     `xml:"sheetFormatPr"`
+	BaseColWidth     uint8        `xml:"baseColWidth,attr,omitempty"`
+	DefaultColWidth  float64      `xml:"defaultColWidth,attr,omitempty"`
+	DefaultRowHeight float64      `xml:"defaultRowHeight,attr"`
+	CustomHeight     bool         `xml:"customHeight,attr,omitempty"`
+	ZeroHeight       bool         `xml:"zeroHeight,attr,omitempty"`
+	ThickTop         bool         `xml:"thickTop,attr,omitempty"`
+	ThickBottom      bool         `xml:"thickBottom,attr,omitempty"`
+	OutlineLevelRow  uint8        `xml:"outlineLevelRow,attr,omitempty"`
+	OutlineLevelCol  uint8        `xml:"outlineLevelCol,attr,omitempty"`
+	AnyAttrs         xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxSheetViews represents worksheet views collection.
@@ -204,6 +207,7 @@ type xlsxSheetView struct {
 	WorkbookViewID           int              `xml:"workbookViewId,attr"`
 	Pane                     *xlsxPane        `xml:"pane,omitempty"`
 	Selection                []*xlsxSelection `xml:"selection"`
+	AnyAttrs                 xlsxAnyAttrs     `xml:",any,attr"`
 }
 
 // xlsxSelection directly maps the selection element in the namespace
@@ -242,6 +246,7 @@ type xlsxSheetPr struct {
 	TabColor                          *xlsxTabColor    `xml:"tabColor,omitempty"`
 	OutlinePr                         *xlsxOutlinePr   `xml:"outlinePr,omitempty"`
 	PageSetUpPr                       *xlsxPageSetUpPr `xml:"pageSetUpPr,omitempty"`
+	AnyAttrs                          xlsxAnyAttrs     `xml:",any,attr"`
 }
 
 // xlsxOutlinePr maps to the outlinePr element. SummaryBelow allows you to
@@ -278,16 +283,17 @@ type xlsxCols struct {
 // xlsxCol directly maps the col (Column Width & Formatting). Defines column
 // width and column formatting for one or more columns of the worksheet.
 type xlsxCol struct {
-	BestFit      bool    `xml:"bestFit,attr,omitempty"`
-	Collapsed    bool    `xml:"collapsed,attr,omitempty"`
-	CustomWidth  bool    `xml:"customWidth,attr,omitempty"`
-	Hidden       bool    `xml:"hidden,attr,omitempty"`
-	Max          int     `xml:"max,attr"`
-	Min          int     `xml:"min,attr"`
-	OutlineLevel uint8   `xml:"outlineLevel,attr,omitempty"`
-	Phonetic     bool    `xml:"phonetic,attr,omitempty"`
-	Style        int     `xml:"style,attr,omitempty"`
-	Width        float64 `xml:"width,attr,omitempty"`
+	BestFit      bool         `xml:"bestFit,attr,omitempty"`
+	Collapsed    bool         `xml:"collapsed,attr,omitempty"`
+	CustomWidth  bool         `xml:"customWidth,attr,omitempty"`
+	Hidden       bool         `xml:"hidden,attr,omitempty"`
+	Max          int          `xml:"max,attr"`
+	Min          int          `xml:"min,attr"`
+	OutlineLevel uint8        `xml:"outlineLevel,attr,omitempty"`
+	Phonetic     bool         `xml:"phonetic,attr,omitempty"`
+	Style        int          `xml:"style,attr,omitempty"`
+	Width        float64      `xml:"width,attr,omitempty"`
+	AnyAttrs     xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxDimension directly maps the dimension element in the namespace
@@ -314,19 +320,20 @@ type xlsxSheetData struct {
 // about an entire row of a worksheet, and contains all cell definitions for a
 // particular row in the worksheet.
 type xlsxRow struct {
-	Collapsed    bool    `xml:"collapsed,attr,omitempty"`
-	CustomFormat bool    `xml:"customFormat,attr,omitempty"`
-	CustomHeight bool    `xml:"customHeight,attr,omitempty"`
-	Hidden       bool    `xml:"hidden,attr,omitempty"`
-	Ht           float64 `xml:"ht,attr,omitempty"`
-	OutlineLevel uint8   `xml:"outlineLevel,attr,omitempty"`
-	Ph           bool    `xml:"ph,attr,omitempty"`
-	R            int     `xml:"r,attr,omitempty"`
-	S            int     `xml:"s,attr,omitempty"`
-	Spans        string  `xml:"spans,attr,omitempty"`
-	ThickBot     bool    `xml:"thickBot,attr,omitempty"`
-	ThickTop     bool    `xml:"thickTop,attr,omitempty"`
-	C            []xlsxC `xml:"c"`
+	Collapsed    bool         `xml:"collapsed,attr,omitempty"`
+	CustomFormat bool         `xml:"customFormat,attr,omitempty"`
+	CustomHeight bool         `xml:"customHeight,attr,omitempty"`
+	Hidden       bool         `xml:"hidden,attr,omitempty"`
+	Ht           float64      `xml:"ht,attr,omitempty"`
+	OutlineLevel uint8        `xml:"outlineLevel,attr,omitempty"`
+	Ph           bool         `xml:"ph,attr,omitempty"`
+	R            int          `xml:"r,attr,omitempty"`
+	S            int          `xml:"s,attr,omitempty"`
+	Spans        string       `xml:"spans,attr,omitempty"`
+	ThickBot     bool         `xml:"thickBot,attr,omitempty"`
+	ThickTop     bool         `xml:"thickTop,attr,omitempty"`
+	C            []xlsxC      `xml:"c"`
+	AnyAttrs     xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxSortState directly maps the sortState element. This collection
@@ -462,23 +469,40 @@ type xlsxC struct {
 	R        string   `xml:"r,attr,omitempty"` // Cell ID, e.g. A1
 	S        int      `xml:"s,attr,omitempty"` // Style reference.
 	// Str string `xml:"str,attr,omitempty"` // Style reference.
-	T  string  `xml:"t,attr,omitempty"` // Type.
-	F  *xlsxF  `xml:"f,omitempty"`      // Formula
-	V  string  `xml:"v,omitempty"`      // Value
-	IS *xlsxSI `xml:"is"`
+	T        string       `xml:"t,attr,omitempty"` // Type.
+	F        *xlsxF       `xml:"f,omitempty"`      // Formula
+	V        string       `xml:"v,omitempty"`      // Value
+	IS       *xlsxSI      `xml:"is"`
+	AnyAttrs xlsxAnyAttrs `xml:",any,attr"`
 }
 
 func (c *xlsxC) hasValue() bool {
 	return c.S != 0 || c.V != "" || c.F != nil || c.T != ""
 }
 
+// removeAnyAttrs provides a function to remove the attributes of the cell
+// which have not been modeled by given attribute names. The value metadata
+// (vm) and cell metadata (cm) attributes reference the records in the
+// metadata part, and should be removed when the value or formula of the cell
+// is replaced.
+func (c *xlsxC) removeAnyAttrs(names ...string) {
+	var attrs xlsxAnyAttrs
+	for _, attr := range c.AnyAttrs {
+		if inStrSlice(names, attr.Name.Local) == -1 {
+			attrs = append(attrs, attr)
+		}
+	}
+	c.AnyAttrs = attrs
+}
+
 // xlsxF represents a formula for the cell. The formula expression is
 // contained in the character node of this element.
 type xlsxF struct {
-	Content string `xml:",chardata"`
-	T       string `xml:"t,attr,omitempty"`   // Formula type
-	Ref     string `xml:"ref,attr,omitempty"` // Shared formula ref
-	Si      string `xml:"si,attr,omitempty"`  // Shared formula index
+	Content  string       `xml:",chardata"`
+	T        string       `xml:"t,attr,omitempty"`   // Formula type
+	Ref      string       `xml:"ref,attr,omitempty"` // Shared formula ref
+	Si       string       `xml:"si,attr,omitempty"`  // Shared formula index
+	AnyAttrs xlsxAnyAttrs `xml:",any,attr"`
 }
 
 // xlsxSheetProtection collection expresses the sheet protection options to